@@ -34,6 +34,9 @@ const (
 	AVSResponseNameMatchZipNoMatchAddressMatch   // Cardholder's name and street address match, ZIP doesn't match.
 	AVSResponseNameMatchZipNoMatchAddressNoMatch // Cardholder's name matches, ZIP and street address don't match.
 	AVSResponseNameMatchZipMatchAddressMatch     // Cardholder's name, zip, and address all match
+
+	AVSResponseZipNoMatchAddressUnverified // ZIP doesn't match, street address not verified.
+	AVSResponseZipUnverifiedAddressNoMatch // ZIP not verified, street address doesn't match.
 )
 
 var avsCodeToString = map[AVSResponse]string{
@@ -59,12 +62,174 @@ var avsCodeToString = map[AVSResponse]string{
 
 	AVSResponseNameNoMatch:                       "AVSResponseNameNoMatch",
 	AVSResponseNameNoMatchAddressMatch:           "AVSResponseNameNoMatchAddressMatch",
+	AVSResponseNameNoMatchZipMatch:               "AVSResponseNameNoMatchZipMatch",
+	AVSResponseNameNoMatchZipMatchAddressMatch:   "AVSResponseNameNoMatchZipMatchAddressMatch",
 	AVSResponseNameMatchZipMatchAddressNoMatch:   "AVSResponseNameMatchZipMatchAddressNoMatch",
 	AVSResponseNameMatchZipNoMatchAddressMatch:   "AVSResponseNameMatchZipNoMatchAddressMatch",
 	AVSResponseNameMatchZipNoMatchAddressNoMatch: "AVSResponseNameMatchZipNoMatchAddressNoMatch",
+	AVSResponseNameMatchZipMatchAddressMatch:     "AVSResponseNameMatchZipMatchAddressMatch",
+
+	AVSResponseZipNoMatchAddressUnverified: "AVSResponseZipNoMatchAddressUnverified",
+	AVSResponseZipUnverifiedAddressNoMatch: "AVSResponseZipUnverifiedAddressNoMatch",
 }
 
 // String returns a string representation of a AVS response code
 func (code AVSResponse) String() string {
 	return avsCodeToString[code]
 }
+
+var stringToAVSCode = func() map[string]AVSResponse {
+	values := make(map[string]AVSResponse, len(avsCodeToString))
+	for value, name := range avsCodeToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseAVSResponse returns the AVS response code with the given name
+func ParseAVSResponse(name string) (AVSResponse, error) {
+	code, ok := stringToAVSCode[name]
+	if !ok {
+		return AVSResponseUnknown, &EnumNameError{Enum: "AVSResponse", Name: name}
+	}
+	return code, nil
+}
+
+// MarshalText encodes the AVS response code as its name
+func (code AVSResponse) MarshalText() ([]byte, error) {
+	return marshalEnumText("AVSResponse", int(code), code.String())
+}
+
+// UnmarshalText decodes the AVS response code from its name
+func (code *AVSResponse) UnmarshalText(text []byte) error {
+	parsed, err := ParseAVSResponse(string(text))
+	if err != nil {
+		return err
+	}
+	*code = parsed
+	return nil
+}
+
+// MarshalJSON encodes the AVS response code as a JSON string of its name
+func (code AVSResponse) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(code)
+}
+
+// UnmarshalJSON decodes the AVS response code from a JSON string of its name, or from a JSON number of its value
+func (code *AVSResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("AVSResponse", data, code, func(value int) string { return AVSResponse(value).String() })
+}
+
+// AVSMatch is the outcome of verifying a single component of the address
+type AVSMatch int
+
+const (
+	AVSMatchUnknown AVSMatch = iota // The component was not verified or its result was not reported.
+	AVSMatchYes                     // The component matches.
+	AVSMatchNo                      // The component doesn't match.
+)
+
+var avsMatchToString = map[AVSMatch]string{
+	AVSMatchUnknown: "AVSMatchUnknown",
+	AVSMatchYes:     "AVSMatchYes",
+	AVSMatchNo:      "AVSMatchNo",
+}
+
+// String returns a string representation of an AVS component match
+func (match AVSMatch) String() string {
+	return avsMatchToString[match]
+}
+
+var stringToAVSMatch = func() map[string]AVSMatch {
+	values := make(map[string]AVSMatch, len(avsMatchToString))
+	for value, name := range avsMatchToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseAVSMatch returns the AVS component match with the given name
+func ParseAVSMatch(name string) (AVSMatch, error) {
+	match, ok := stringToAVSMatch[name]
+	if !ok {
+		return AVSMatchUnknown, &EnumNameError{Enum: "AVSMatch", Name: name}
+	}
+	return match, nil
+}
+
+// MarshalText encodes the AVS component match as its name
+func (match AVSMatch) MarshalText() ([]byte, error) {
+	return marshalEnumText("AVSMatch", int(match), match.String())
+}
+
+// UnmarshalText decodes the AVS component match from its name
+func (match *AVSMatch) UnmarshalText(text []byte) error {
+	parsed, err := ParseAVSMatch(string(text))
+	if err != nil {
+		return err
+	}
+	*match = parsed
+	return nil
+}
+
+// MarshalJSON encodes the AVS component match as a JSON string of its name
+func (match AVSMatch) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(match)
+}
+
+// UnmarshalJSON decodes the AVS component match from a JSON string of its name, or from a JSON number of its value
+func (match *AVSMatch) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("AVSMatch", data, match, func(value int) string { return AVSMatch(value).String() })
+}
+
+// AVSComponents is a structured view of an AVSResponse, so rules can check individual address components
+// without switching over every AVSResponse value.
+type AVSComponents struct {
+	PostalCode AVSMatch
+	Street     AVSMatch
+	Name       AVSMatch
+	// International is true when the result was reported for a card issued outside the U.S.
+	International bool
+	// Available is false when no verification took place (unknown, error, unsupported or skipped)
+	Available bool
+}
+
+var avsCodeToComponents = map[AVSResponse]AVSComponents{
+	AVSResponseUnknown:     {},
+	AVSResponseError:       {},
+	AVSResponseUnsupported: {},
+	AVSResponseSkipped:     {},
+
+	AVSResponseZip9MatchAddressMatch:     {PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+	AVSResponseZip9MatchAddressNoMatch:   {PostalCode: AVSMatchYes, Street: AVSMatchNo, Available: true},
+	AVSResponseZip5MatchAddressMatch:     {PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+	AVSResponseZip5MatchAddressNoMatch:   {PostalCode: AVSMatchYes, Street: AVSMatchNo, Available: true},
+	AVSresponseZipMatchAddressMatch:      {PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+	AVSResponseZipNoMatchAddressMatch:    {PostalCode: AVSMatchNo, Street: AVSMatchYes, Available: true},
+	AVSResponseZipMatchAddressUnverified: {PostalCode: AVSMatchYes, Street: AVSMatchUnknown, Available: true},
+	AVSResponseZipUnverifiedAddressMatch: {PostalCode: AVSMatchUnknown, Street: AVSMatchYes, Available: true},
+	AVSResponseMatch:                     {PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+	AVSResponseNoMatch:                   {PostalCode: AVSMatchNo, Street: AVSMatchNo, Available: true},
+
+	AVSResponseNonUsZipMatchAddressMatch:      {PostalCode: AVSMatchYes, Street: AVSMatchYes, International: true, Available: true},
+	AVSResponseNonUsZipNoMatchAddressNoMatch:  {PostalCode: AVSMatchNo, Street: AVSMatchNo, International: true, Available: true},
+	AVSResponseNonUsZipUnverifiedAddressMatch: {PostalCode: AVSMatchUnknown, Street: AVSMatchYes, International: true, Available: true},
+
+	AVSResponseNameNoMatch:                       {Name: AVSMatchNo, Available: true},
+	AVSResponseNameNoMatchAddressMatch:           {Name: AVSMatchNo, Street: AVSMatchYes, Available: true},
+	AVSResponseNameNoMatchZipMatch:               {Name: AVSMatchNo, PostalCode: AVSMatchYes, Available: true},
+	AVSResponseNameNoMatchZipMatchAddressMatch:   {Name: AVSMatchNo, PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+	AVSResponseNameMatchZipMatchAddressNoMatch:   {Name: AVSMatchYes, PostalCode: AVSMatchYes, Street: AVSMatchNo, Available: true},
+	AVSResponseNameMatchZipNoMatchAddressMatch:   {Name: AVSMatchYes, PostalCode: AVSMatchNo, Street: AVSMatchYes, Available: true},
+	AVSResponseNameMatchZipNoMatchAddressNoMatch: {Name: AVSMatchYes, PostalCode: AVSMatchNo, Street: AVSMatchNo, Available: true},
+	AVSResponseNameMatchZipMatchAddressMatch:     {Name: AVSMatchYes, PostalCode: AVSMatchYes, Street: AVSMatchYes, Available: true},
+
+	AVSResponseZipNoMatchAddressUnverified: {PostalCode: AVSMatchNo, Street: AVSMatchUnknown, Available: true},
+	AVSResponseZipUnverifiedAddressNoMatch: {PostalCode: AVSMatchUnknown, Street: AVSMatchNo, Available: true},
+}
+
+// Components breaks the AVS response down into the match result of each address component.
+// Values that are not known to sleet are reported as unavailable.
+func (code AVSResponse) Components() AVSComponents {
+	return avsCodeToComponents[code]
+}
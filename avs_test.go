@@ -0,0 +1,46 @@
+package sleet
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestAVSResponseComponentsCoversAllValues(t *testing.T) {
+	for code := AVSResponseUnknown; code <= AVSResponseZipUnverifiedAddressNoMatch; code++ {
+		if _, ok := avsCodeToComponents[code]; !ok {
+			t.Errorf("AVS response %d has no components", code)
+		}
+	}
+}
+
+func TestAVSResponseComponents(t *testing.T) {
+	cases := []struct {
+		code AVSResponse
+		want AVSComponents
+	}{
+		{AVSResponseSkipped, AVSComponents{}},
+		{AVSResponseZip5MatchAddressNoMatch, AVSComponents{PostalCode: AVSMatchYes, Street: AVSMatchNo, Available: true}},
+		{AVSResponseZipMatchAddressUnverified, AVSComponents{PostalCode: AVSMatchYes, Street: AVSMatchUnknown, Available: true}},
+		{AVSResponseNonUsZipUnverifiedAddressMatch, AVSComponents{Street: AVSMatchYes, International: true, Available: true}},
+		{AVSResponseNameNoMatchZipMatch, AVSComponents{PostalCode: AVSMatchYes, Name: AVSMatchNo, Available: true}},
+		{AVSResponseZipNoMatchAddressUnverified, AVSComponents{PostalCode: AVSMatchNo, Available: true}},
+		{AVSResponse(1000), AVSComponents{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.code.String(), func(t *testing.T) {
+			if diff := deep.Equal(c.code.Components(), c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAuthorizationResponseAVSComponents(t *testing.T) {
+	response := AuthorizationResponse{AvsResult: AVSResponseNoMatch}
+	want := AVSComponents{PostalCode: AVSMatchNo, Street: AVSMatchNo, Available: true}
+	if diff := deep.Equal(response.AVSComponents(), want); diff != nil {
+		t.Error(diff)
+	}
+}
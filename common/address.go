@@ -0,0 +1,173 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/BoltApp/sleet"
+)
+
+// AddressProfile describes the constraints a gateway places on the address fields it accepts.
+// A max length of 0 means the field is not truncated.
+type AddressProfile struct {
+	StreetAddress1MaxLength int
+	StreetAddress2MaxLength int
+	LocalityMaxLength       int
+	RegionCodeMaxLength     int
+	PostalCodeMaxLength     int
+	CountryCodeMaxLength    int
+	CompanyMaxLength        int
+
+	// ASCIIOnly transliterates accented Latin characters to their ASCII equivalent and drops any other non-ASCII rune
+	ASCIIOnly bool
+	// DisallowedCharacters lists characters the gateway cannot accept (e.g. delimiters). They are replaced with a space.
+	DisallowedCharacters string
+	// PostalCodeRemovedCharacters lists characters removed from postal codes before they are truncated, such as the
+	// hyphen of a ZIP+4 code for gateways that only accept digits
+	PostalCodeRemovedCharacters string
+}
+
+// streetNumberRegex matches a leading numeric house number followed by the street name. House numbers with a suffix
+// or a range, such as "12B" or "12-14", are left in the street name.
+var streetNumberRegex = regexp.MustCompile(`^(\d+)\s(.*)`)
+
+// asciiTransliterations maps common accented Latin runes to an ASCII approximation
+var asciiTransliterations = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'Æ': "AE", 'æ': "ae",
+	'Ç': "C", 'Ć': "C", 'Č': "C", 'ç': "c", 'ć': "c", 'č': "c",
+	'Ď': "D", 'Đ': "D", 'Ð': "D", 'ď': "d", 'đ': "d", 'ð': "d",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'Ğ': "G", 'ğ': "g",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I", 'İ': "I",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'Ł': "L", 'ł': "l",
+	'Ñ': "N", 'Ń': "N", 'Ň': "N", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Ō': "O", 'Ő': "O",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'Œ': "OE", 'œ': "oe",
+	'Ř': "R", 'ř': "r",
+	'Ś': "S", 'Š': "S", 'Ş': "S", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss",
+	'Ť': "T", 'Ţ': "T", 'ť': "t", 'ţ': "t",
+	'Þ': "TH", 'þ': "th",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ū': "U", 'Ů': "U", 'Ű': "U",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'Ý': "Y", 'Ÿ': "Y", 'ý': "y", 'ÿ': "y",
+	'Ź': "Z", 'Ż': "Z", 'Ž': "Z", 'ź': "z", 'ż': "z", 'ž': "z",
+	'‘': "'", '’': "'", '“': "\"", '”': "\"", '–': "-", '—': "-",
+}
+
+// NormalizeAddress returns a copy of address whose fields satisfy the given gateway profile. Fields that are nil
+// stay nil. Email and PhoneNumber are copied as-is, see NormalizeEmail and NormalizePhoneNumber.
+func NormalizeAddress(address *sleet.Address, profile AddressProfile) *sleet.Address {
+	if address == nil {
+		return nil
+	}
+	return &sleet.Address{
+		StreetAddress1: normalizeField(address.StreetAddress1, profile, profile.StreetAddress1MaxLength),
+		StreetAddress2: normalizeField(address.StreetAddress2, profile, profile.StreetAddress2MaxLength),
+		Locality:       normalizeField(address.Locality, profile, profile.LocalityMaxLength),
+		RegionCode:     normalizeField(address.RegionCode, profile, profile.RegionCodeMaxLength),
+		PostalCode:     normalizePostalCodeField(address.PostalCode, profile),
+		CountryCode:    normalizeField(address.CountryCode, profile, profile.CountryCodeMaxLength),
+		Company:        normalizeField(address.Company, profile, profile.CompanyMaxLength),
+		Email:          address.Email,
+		PhoneNumber:    address.PhoneNumber,
+	}
+}
+
+// NormalizeAddressValue applies the character rules of profile to a single value and truncates it to maxLength.
+// This is useful for fields that are sent alongside an address, such as the cardholder name.
+func NormalizeAddressValue(value string, profile AddressProfile, maxLength int) string {
+	if profile.ASCIIOnly {
+		value = TransliterateASCII(value)
+	}
+	if profile.DisallowedCharacters != "" {
+		value = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(profile.DisallowedCharacters, r) {
+				return ' '
+			}
+			return r
+		}, value)
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if maxLength > 0 {
+		value = strings.TrimSpace(truncateRunes(value, maxLength))
+	}
+	return value
+}
+
+// NormalizePostalCode removes the PostalCodeRemovedCharacters of profile from value, then applies the character rules
+// of profile and truncates it to PostalCodeMaxLength
+func NormalizePostalCode(value string, profile AddressProfile) string {
+	if profile.PostalCodeRemovedCharacters != "" {
+		value = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(profile.PostalCodeRemovedCharacters, r) {
+				return -1
+			}
+			return r
+		}, value)
+	}
+	return NormalizeAddressValue(value, profile, profile.PostalCodeMaxLength)
+}
+
+// TransliterateASCII replaces accented Latin characters with their closest ASCII equivalent.
+// Other non-ASCII and control characters are dropped.
+func TransliterateASCII(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if r < unicode.MaxASCII && !unicode.IsControl(r) {
+			builder.WriteRune(r)
+			continue
+		}
+		if unicode.IsSpace(r) {
+			builder.WriteRune(' ')
+			continue
+		}
+		if replacement, ok := asciiTransliterations[r]; ok {
+			builder.WriteString(replacement)
+		}
+	}
+	return builder.String()
+}
+
+// SplitStreetNumber extracts the leading house number from a street address
+//
+//	returns (streetNumber, streetName) format
+//	If address does not have leading street number, will return ("", street)
+func SplitStreetNumber(streetAddress string) (string, string) {
+	streetExtraction := streetNumberRegex.FindStringSubmatch(streetAddress)
+	if streetExtraction == nil {
+		return "", streetAddress
+	}
+
+	return streetExtraction[1], streetExtraction[2]
+}
+
+func normalizeField(value *string, profile AddressProfile, maxLength int) *string {
+	if value == nil {
+		return nil
+	}
+	normalized := NormalizeAddressValue(*value, profile, maxLength)
+	return &normalized
+}
+
+func normalizePostalCodeField(value *string, profile AddressProfile) *string {
+	if value == nil {
+		return nil
+	}
+	normalized := NormalizePostalCode(*value, profile)
+	return &normalized
+}
+
+// truncateRunes truncates value to at most maxLength runes so multi-byte characters are never split
+func truncateRunes(value string, maxLength int) string {
+	runes := []rune(value)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength])
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	profile := AddressProfile{
+		StreetAddress1MaxLength: 21,
+		LocalityMaxLength:       10,
+		PostalCodeMaxLength:     5,
+		ASCIIOnly:               true,
+		DisallowedCharacters:    `&=`,
+	}
+
+	cases := []struct {
+		label string
+		in    *sleet.Address
+		want  *sleet.Address
+	}{
+		{
+			"Nil address",
+			nil,
+			nil,
+		},
+		{
+			"Nil fields stay nil",
+			&sleet.Address{PostalCode: SPtr("94103")},
+			&sleet.Address{PostalCode: SPtr("94103")},
+		},
+		{
+			"Transliterate, sanitize and truncate",
+			&sleet.Address{
+				StreetAddress1: SPtr("12 Rue de l'Église & Co"),
+				Locality:       SPtr("São  Paulo=Centro"),
+				PostalCode:     SPtr("94103-1234"),
+				CountryCode:    SPtr("BR"),
+				Email:          SPtr("test@bolt.com"),
+			},
+			&sleet.Address{
+				StreetAddress1: SPtr("12 Rue de l'Eglise Co"),
+				Locality:       SPtr("Sao Paulo"),
+				PostalCode:     SPtr("94103"),
+				CountryCode:    SPtr("BR"),
+				Email:          SPtr("test@bolt.com"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := NormalizeAddress(c.in, profile)
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeAddressValueKeepsUnicode(t *testing.T) {
+	got := NormalizeAddressValue("Zürich Straße", AddressProfile{}, 6)
+	if got != "Zürich" {
+		t.Errorf("Got %q, want %q", got, "Zürich")
+	}
+}
+
+func TestTransliterateASCII(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"Main Street", "Main Street"},
+		{"Straße", "Strasse"},
+		{"Łódź", "Lodz"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"東京 Tower", " Tower"},
+		{"Tab\tSeparated", "Tab Separated"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := TransliterateASCII(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePostalCode(t *testing.T) {
+	profile := AddressProfile{PostalCodeMaxLength: 9, PostalCodeRemovedCharacters: "- "}
+
+	cases := []struct {
+		label string
+		in    string
+		want  string
+	}{
+		{"ZIP code", "94103", "94103"},
+		{"ZIP+4 code", "94103-1234", "941031234"},
+		{"Postal code with a space", "K1A 0B1", "K1A0B1"},
+		{"Truncated after removal", "94103-12345", "941031234"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := NormalizePostalCode(c.in, profile)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSplitStreetNumber(t *testing.T) {
+	cases := []struct {
+		label      string
+		in         string
+		wantNumber string
+		wantStreet string
+	}{
+		{"Street number", "7683 Railroad Street", "7683", "Railroad Street"},
+		{"Street number with suffix", "12B Baker Street", "", "12B Baker Street"},
+		{"Street number range", "12-14 Main St", "", "12-14 Main St"},
+		{"No street number", "PO Box 123", "", "PO Box 123"},
+		{"Number attached to name", "123Street", "", "123Street"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			number, street := SplitStreetNumber(c.in)
+			if number != c.wantNumber || street != c.wantStreet {
+				t.Errorf("Got (%q, %q), want (%q, %q)", number, street, c.wantNumber, c.wantStreet)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// maxEmailLength is the maximum length of an email address per RFC 5321
+const maxEmailLength = 254
+
+// ValidateEmail performs basic syntax checks on an email address: a single addr-spec without display name,
+// a non-empty local part and a domain containing at least one dot.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return errors.New("email address is empty")
+	}
+	if len(email) > maxEmailLength {
+		return fmt.Errorf("email address is longer than %d characters: %s", maxEmailLength, email)
+	}
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Name != "" || parsed.Address != email {
+		return fmt.Errorf("invalid email address: %s", email)
+	}
+	at := strings.LastIndex(email, "@")
+	domain := email[at+1:]
+	if at < 1 || !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return fmt.Errorf("invalid email address: %s", email)
+	}
+	return nil
+}
+
+// NormalizeEmail trims whitespace and lowercases the domain of email. nil is returned when the email is missing or
+// invalid, so gateways never receive a malformed value.
+func NormalizeEmail(email *string) *string {
+	if email == nil {
+		return nil
+	}
+	normalized := strings.TrimSpace(*email)
+	if at := strings.LastIndex(normalized, "@"); at >= 0 {
+		normalized = normalized[:at] + strings.ToLower(normalized[at:])
+	}
+	if ValidateEmail(normalized) != nil {
+		return nil
+	}
+	return &normalized
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestValidateEmail(t *testing.T) {
+	cases := []struct {
+		email string
+		valid bool
+	}{
+		{"test@bolt.com", true},
+		{"first.last+tag@mail.example.co.uk", true},
+		{"", false},
+		{"test", false},
+		{"@bolt.com", false},
+		{"test@bolt", false},
+		{"test@.bolt.com", false},
+		{"test@bolt.com.", false},
+		{"Test User <test@bolt.com>", false},
+		{"test@bolt.com, other@bolt.com", false},
+		{"te st@bolt.com", false},
+		{strings.Repeat("a", 250) + "@bolt.com", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.email, func(t *testing.T) {
+			err := ValidateEmail(c.email)
+			if c.valid && err != nil {
+				t.Errorf("expected %q to be valid, got %s", c.email, err)
+			}
+			if !c.valid && err == nil {
+				t.Errorf("expected %q to be invalid", c.email)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	cases := []struct {
+		label string
+		in    *string
+		want  *string
+	}{
+		{"Nil email", nil, nil},
+		{"Trims and lowercases domain", SPtr("  Test.User@Bolt.COM "), SPtr("Test.User@bolt.com")},
+		{"Invalid email", SPtr("not an email"), nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if diff := deep.Equal(NormalizeEmail(c.in), c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
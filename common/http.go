@@ -1,6 +1,7 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -16,6 +17,30 @@ func DefaultHttpClient() *http.Client {
 	}
 }
 
+// HttpClientWithContext returns a copy of httpClient whose requests are sent with ctx, so that they honour its
+// cancellation and deadline. It is used with client libraries that build their requests without a context.
+func HttpClientWithContext(ctx context.Context, httpClient *http.Client) *http.Client {
+	withContext := http.Client{}
+	if httpClient != nil {
+		withContext = *httpClient
+	}
+	base := withContext.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	withContext.Transport = &contextTransport{ctx: ctx, base: base}
+	return &withContext
+}
+
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (transport *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return transport.base.RoundTrip(req.WithContext(transport.ctx))
+}
+
 // UserAgent specifies the Sleet library and version for PsPs that require this header
 func UserAgent() string {
 	return fmt.Sprintf("Sleet/%s", LibraryVersion)
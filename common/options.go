@@ -0,0 +1,55 @@
+package common
+
+import "strings"
+
+// ClientOption customises a gateway client when it is created
+type ClientOption func(*ClientOptions)
+
+// ClientOptions are the settings applied by ClientOption. Gateways ignore settings they have no use for.
+type ClientOptions struct {
+	// BaseURL replaces the scheme, host and path prefix of the endpoints of the gateway
+	BaseURL string
+	// SecondaryBaseURL replaces the second endpoint of gateways that have one, such as the failover host of Orbital
+	// and the Payments API of Adyen
+	SecondaryBaseURL string
+}
+
+// WithBaseURL sends the requests of a client to baseURL instead of the host of its environment, to use a local
+// stand-in of the gateway, a regional endpoint or an egress proxy
+func WithBaseURL(baseURL string) ClientOption {
+	return func(options *ClientOptions) {
+		options.BaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithSecondaryBaseURL overrides the second endpoint of gateways that have one, see ClientOptions.SecondaryBaseURL
+func WithSecondaryBaseURL(baseURL string) ClientOption {
+	return func(options *ClientOptions) {
+		options.SecondaryBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// NewClientOptions applies options in order
+func NewClientOptions(options ...ClientOption) ClientOptions {
+	var clientOptions ClientOptions
+	for _, option := range options {
+		option(&clientOptions)
+	}
+	return clientOptions
+}
+
+// BaseURLOr returns the overriding base URL, or defaultURL when there is none
+func (options ClientOptions) BaseURLOr(defaultURL string) string {
+	if options.BaseURL != "" {
+		return options.BaseURL
+	}
+	return defaultURL
+}
+
+// SecondaryBaseURLOr returns the overriding secondary base URL, or defaultURL when there is none
+func (options ClientOptions) SecondaryBaseURLOr(defaultURL string) string {
+	if options.SecondaryBaseURL != "" {
+		return options.SecondaryBaseURL
+	}
+	return defaultURL
+}
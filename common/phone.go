@@ -0,0 +1,202 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PhoneNumberFormat is the representation a gateway expects for a phone number
+type PhoneNumberFormat int
+
+const (
+	// PhoneNumberFormatE164 is the international format, e.g. +14155552671
+	PhoneNumberFormatE164 PhoneNumberFormat = iota
+	// PhoneNumberFormatNational is the national significant number as digits only, e.g. 4155552671
+	PhoneNumberFormatNational
+)
+
+const (
+	minNationalNumberLength = 4
+	maxE164Digits           = 15
+	nanpCallingCode         = "1"
+	nanpNationalLength      = 10
+)
+
+// PhoneNumber is a phone number split into its country calling code and national significant number
+type PhoneNumber struct {
+	CountryCallingCode string
+	NationalNumber     string
+}
+
+// callingCodes maps ISO 3166-1 alpha-2 country codes to their ITU-T E.164 country calling code
+var callingCodes = map[string]string{
+	"AD": "376", "AE": "971", "AF": "93", "AG": "1", "AI": "1", "AL": "355", "AM": "374", "AO": "244",
+	"AR": "54", "AS": "1", "AT": "43", "AU": "61", "AW": "297", "AX": "358", "AZ": "994", "BA": "387",
+	"BB": "1", "BD": "880", "BE": "32", "BF": "226", "BG": "359", "BH": "973", "BI": "257", "BJ": "229",
+	"BL": "590", "BM": "1", "BN": "673", "BO": "591", "BQ": "599", "BR": "55", "BS": "1", "BT": "975",
+	"BW": "267", "BY": "375", "BZ": "501", "CA": "1", "CD": "243", "CF": "236", "CG": "242", "CH": "41",
+	"CI": "225", "CK": "682", "CL": "56", "CM": "237", "CN": "86", "CO": "57", "CR": "506", "CU": "53",
+	"CV": "238", "CW": "599", "CY": "357", "CZ": "420", "DE": "49", "DJ": "253", "DK": "45", "DM": "1",
+	"DO": "1", "DZ": "213", "EC": "593", "EE": "372", "EG": "20", "ER": "291", "ES": "34", "ET": "251",
+	"FI": "358", "FJ": "679", "FK": "500", "FM": "691", "FO": "298", "FR": "33", "GA": "241", "GB": "44",
+	"GD": "1", "GE": "995", "GF": "594", "GG": "44", "GH": "233", "GI": "350", "GL": "299", "GM": "220",
+	"GN": "224", "GP": "590", "GQ": "240", "GR": "30", "GT": "502", "GU": "1", "GW": "245", "GY": "592",
+	"HK": "852", "HN": "504", "HR": "385", "HT": "509", "HU": "36", "ID": "62", "IE": "353", "IL": "972",
+	"IM": "44", "IN": "91", "IQ": "964", "IR": "98", "IS": "354", "IT": "39", "JE": "44", "JM": "1",
+	"JO": "962", "JP": "81", "KE": "254", "KG": "996", "KH": "855", "KI": "686", "KM": "269", "KN": "1",
+	"KP": "850", "KR": "82", "KW": "965", "KY": "1", "KZ": "7", "LA": "856", "LB": "961", "LC": "1",
+	"LI": "423", "LK": "94", "LR": "231", "LS": "266", "LT": "370", "LU": "352", "LV": "371", "LY": "218",
+	"MA": "212", "MC": "377", "MD": "373", "ME": "382", "MF": "590", "MG": "261", "MH": "692", "MK": "389",
+	"ML": "223", "MM": "95", "MN": "976", "MO": "853", "MP": "1", "MQ": "596", "MR": "222", "MS": "1",
+	"MT": "356", "MU": "230", "MV": "960", "MW": "265", "MX": "52", "MY": "60", "MZ": "258", "NA": "264",
+	"NC": "687", "NE": "227", "NG": "234", "NI": "505", "NL": "31", "NO": "47", "NP": "977", "NR": "674",
+	"NU": "683", "NZ": "64", "OM": "968", "PA": "507", "PE": "51", "PF": "689", "PG": "675", "PH": "63",
+	"PK": "92", "PL": "48", "PM": "508", "PR": "1", "PS": "970", "PT": "351", "PW": "680", "PY": "595",
+	"QA": "974", "RE": "262", "RO": "40", "RS": "381", "RU": "7", "RW": "250", "SA": "966", "SB": "677",
+	"SC": "248", "SD": "249", "SE": "46", "SG": "65", "SH": "290", "SI": "386", "SK": "421", "SL": "232",
+	"SM": "378", "SN": "221", "SO": "252", "SR": "597", "SS": "211", "ST": "239", "SV": "503", "SX": "1",
+	"SY": "963", "SZ": "268", "TC": "1", "TD": "235", "TG": "228", "TH": "66", "TJ": "992", "TL": "670",
+	"TM": "993", "TN": "216", "TO": "676", "TR": "90", "TT": "1", "TV": "688", "TW": "886", "TZ": "255",
+	"UA": "380", "UG": "256", "US": "1", "UY": "598", "UZ": "998", "VA": "39", "VC": "1", "VE": "58",
+	"VG": "1", "VI": "1", "VN": "84", "VU": "678", "WF": "681", "WS": "685", "YE": "967", "YT": "262",
+	"ZA": "27", "ZM": "260", "ZW": "263",
+}
+
+// knownCallingCodes is the set of calling codes in callingCodes, used to split numbers given in international format
+var knownCallingCodes = func() map[string]bool {
+	codes := make(map[string]bool, len(callingCodes))
+	for _, code := range callingCodes {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// leadingZeroCountries keep the leading zero of the national number in international format
+var leadingZeroCountries = map[string]bool{"39": true}
+
+// ParsePhoneNumber parses a phone number as entered by a shopper. Numbers in international format ("+44 20 7946 0958"
+// or "0044 20 7946 0958") are split using their own calling code, other numbers are interpreted as national numbers of
+// the given ISO 3166-1 alpha-2 country. Punctuation and a trailing extension ("x123", "ext. 123") are ignored.
+func ParsePhoneNumber(phone string, countryCode string) (*PhoneNumber, error) {
+	digits, international, err := extractPhoneDigits(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultCallingCode := callingCodes[strings.ToUpper(strings.TrimSpace(countryCode))]
+	if !international {
+		switch {
+		case strings.HasPrefix(digits, "00"):
+			digits, international = digits[2:], true
+		case defaultCallingCode == nanpCallingCode && strings.HasPrefix(digits, "011"):
+			digits, international = digits[3:], true
+		}
+	}
+
+	var number PhoneNumber
+	if international {
+		callingCode, ok := splitCallingCode(digits)
+		if !ok {
+			return nil, fmt.Errorf("unknown country calling code in phone number: %s", phone)
+		}
+		number = PhoneNumber{CountryCallingCode: callingCode, NationalNumber: digits[len(callingCode):]}
+	} else {
+		if defaultCallingCode == "" {
+			return nil, fmt.Errorf("unknown country code for phone number: %s", countryCode)
+		}
+		if defaultCallingCode == nanpCallingCode && len(digits) == nanpNationalLength+1 && strings.HasPrefix(digits, nanpCallingCode) {
+			digits = digits[1:]
+		}
+		number = PhoneNumber{CountryCallingCode: defaultCallingCode, NationalNumber: digits}
+	}
+
+	// drop the national trunk prefix, e.g. the 0 in "+44 (0)20 7946 0958" or "020 7946 0958"
+	if !leadingZeroCountries[number.CountryCallingCode] && number.CountryCallingCode != nanpCallingCode {
+		number.NationalNumber = strings.TrimPrefix(number.NationalNumber, "0")
+	}
+
+	if err := number.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %s", err.Error(), phone)
+	}
+	return &number, nil
+}
+
+// E164 returns the number in E.164 format, e.g. +14155552671
+func (p PhoneNumber) E164() string {
+	return "+" + p.CountryCallingCode + p.NationalNumber
+}
+
+// Format returns the number in the requested format
+func (p PhoneNumber) Format(format PhoneNumberFormat) string {
+	if format == PhoneNumberFormatNational {
+		return p.NationalNumber
+	}
+	return p.E164()
+}
+
+// NormalizePhoneNumber parses phone against countryCode and returns it in the requested format.
+// nil is returned when the phone number is missing or cannot be parsed, so gateways never receive a malformed value.
+func NormalizePhoneNumber(phone *string, countryCode *string, format PhoneNumberFormat) *string {
+	if phone == nil {
+		return nil
+	}
+	number, err := ParsePhoneNumber(*phone, SafeStr(countryCode))
+	if err != nil {
+		return nil
+	}
+	formatted := number.Format(format)
+	return &formatted
+}
+
+func (p PhoneNumber) validate() error {
+	if len(p.NationalNumber) < minNationalNumberLength {
+		return errors.New("phone number is too short")
+	}
+	if len(p.CountryCallingCode)+len(p.NationalNumber) > maxE164Digits {
+		return errors.New("phone number is too long")
+	}
+	if p.CountryCallingCode == nanpCallingCode && len(p.NationalNumber) != nanpNationalLength {
+		return fmt.Errorf("phone number must have %d digits", nanpNationalLength)
+	}
+	return nil
+}
+
+// extractPhoneDigits strips punctuation and extensions from phone and reports whether it starts with a "+"
+func extractPhoneDigits(phone string) (string, bool, error) {
+	phone = strings.TrimSpace(phone)
+	lower := strings.ToLower(phone)
+	for _, marker := range []string{"ext", "x", "#"} {
+		if index := strings.Index(lower, marker); index > 0 {
+			phone = phone[:index]
+			lower = lower[:index]
+		}
+	}
+
+	international := strings.HasPrefix(phone, "+")
+	var digits strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()/", r):
+		default:
+			return "", false, fmt.Errorf("invalid character %q in phone number: %s", r, phone)
+		}
+	}
+	if digits.Len() == 0 {
+		return "", false, fmt.Errorf("phone number has no digits: %s", phone)
+	}
+	return digits.String(), international, nil
+}
+
+// splitCallingCode returns the calling code prefix of digits, which is between one and three digits long
+func splitCallingCode(digits string) (string, bool) {
+	for length := 1; length <= 3 && length < len(digits); length++ {
+		if knownCallingCodes[digits[:length]] {
+			return digits[:length], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestParsePhoneNumber(t *testing.T) {
+	cases := []struct {
+		label       string
+		phone       string
+		countryCode string
+		want        *PhoneNumber
+	}{
+		{"US national with punctuation", "(415) 555-2671", "US", &PhoneNumber{"1", "4155552671"}},
+		{"US national with dashes", "555-555-5555", "US", &PhoneNumber{"1", "5555555555"}},
+		{"US with leading 1", "1 415 555 2671", "US", &PhoneNumber{"1", "4155552671"}},
+		{"US with extension", "415.555.2671 ext. 12", "US", &PhoneNumber{"1", "4155552671"}},
+		{"Lowercase country code", "4155552671", "us", &PhoneNumber{"1", "4155552671"}},
+		{"E.164 ignores country", "+44 20 7946 0958", "US", &PhoneNumber{"44", "2079460958"}},
+		{"International with trunk prefix", "+44 (0)20 7946 0958", "", &PhoneNumber{"44", "2079460958"}},
+		{"00 international prefix", "0049 30 1234567", "FR", &PhoneNumber{"49", "301234567"}},
+		{"011 international prefix from US", "011 33 1 23 45 67 89", "US", &PhoneNumber{"33", "123456789"}},
+		{"GB national with trunk prefix", "020 7946 0958", "GB", &PhoneNumber{"44", "2079460958"}},
+		{"IT keeps leading zero", "06 1234 5678", "IT", &PhoneNumber{"39", "0612345678"}},
+		{"Three digit calling code", "+353 1 234 5678", "", &PhoneNumber{"353", "12345678"}},
+		{"Unknown country", "4155552671", "ZZ", nil},
+		{"Missing country", "4155552671", "", nil},
+		{"Letters", "call me", "US", nil},
+		{"Too short for US", "555-1234", "US", nil},
+		{"Too short", "+44 12", "", nil},
+		{"Too long", "+44 1234 5678 9012 34", "", nil},
+		{"Empty", "", "US", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got, err := ParsePhoneNumber(c.phone, c.countryCode)
+			if c.want == nil {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	cases := []struct {
+		label       string
+		phone       *string
+		countryCode *string
+		format      PhoneNumberFormat
+		want        *string
+	}{
+		{"Nil phone", nil, SPtr("US"), PhoneNumberFormatE164, nil},
+		{"E.164", SPtr("(415) 555-2671"), SPtr("US"), PhoneNumberFormatE164, SPtr("+14155552671")},
+		{"National", SPtr("+1 415 555 2671"), nil, PhoneNumberFormatNational, SPtr("4155552671")},
+		{"Unparseable", SPtr("n/a"), SPtr("US"), PhoneNumberFormatE164, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := NormalizePhoneNumber(c.phone, c.countryCode, c.format)
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package common
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // ISO Units of Measure codes (http://t3.apptrix.com/syteline/Language/en-US/fields/i/iso_um_ums.htm)
 var unitOfMeasurementToCode = map[string]string{
@@ -400,13 +403,143 @@ var unitOfMeasurementToCode = map[string]string{
 	"cask":                            "Z3",
 }
 
+// unitOfMeasurementSynonyms maps abbreviations and alternative spellings to a name in unitOfMeasurementToCode
+var unitOfMeasurementSynonyms = map[string]string{
+	"ea":         "each",
+	"count":      "each",
+	"ct":         "each",
+	"item":       "each",
+	"items":      "each",
+	"pc":         "piece",
+	"pcs":        "piece",
+	"pieces":     "piece",
+	"units":      "unit",
+	"pairs":      "pair",
+	"boxes":      "box",
+	"cases":      "case",
+	"sets":       "set",
+	"dozens":     "dozen",
+	"doz":        "dozen",
+	"bottles":    "bottle",
+	"cans":       "can",
+	"cartons":    "carton",
+	"bags":       "bag",
+	"rolls":      "roll",
+	"sheets":     "sheet",
+	"kg":         "kilogram",
+	"kgs":        "kilogram",
+	"kilo":       "kilogram",
+	"kilos":      "kilogram",
+	"kilograms":  "kilogram",
+	"g":          "gram",
+	"gm":         "gram",
+	"grams":      "gram",
+	"mg":         "milligram",
+	"milligrams": "milligram",
+	"lb":         "pound",
+	"lbs":        "pound",
+	"pounds":     "pound",
+	"oz":         "ounce - av",
+	"ounce":      "ounce - av",
+	"ounces":     "ounce - av",
+	"fl oz":      "fluid ounce",
+	"l":          "liter",
+	"litre":      "liter",
+	"liters":     "liter",
+	"litres":     "liter",
+	"ml":         "milliliter",
+	"millilitre": "milliliter",
+	"gal":        "gallon",
+	"gallons":    "gallon",
+	"m":          "meter",
+	"metre":      "meter",
+	"meters":     "meter",
+	"metres":     "meter",
+	"cm":         "centimeter",
+	"centimetre": "centimeter",
+	"mm":         "millimeter",
+	"millimetre": "millimeter",
+	"km":         "kilometers",
+	"kilometer":  "kilometers",
+	"ft":         "foot",
+	"feet":       "foot",
+	"in":         "inch",
+	"inches":     "inch",
+	"yd":         "yard",
+	"yards":      "yard",
+	"sq ft":      "square foot",
+	"sqft":       "square foot",
+	"sq m":       "square meter",
+	"hr":         "hours",
+	"hrs":        "hours",
+	"hour":       "hours",
+	"day":        "days",
+	"min":        "minutes",
+	"minute":     "minutes",
+	"month":      "months",
+}
+
+// codeToUnitOfMeasurement is the reverse of unitOfMeasurementToCode
+var codeToUnitOfMeasurement = func() map[string]string {
+	codes := make(map[string]string, len(unitOfMeasurementToCode))
+	for unit, code := range unitOfMeasurementToCode {
+		codes[code] = unit
+	}
+	return codes
+}()
+
+// LookupUnitOfMeasurementCode returns the ISO code for unit. unit may be an already valid code ("KG"), a name
+// ("kilogram") or an abbreviation or synonym ("kg", "Each"). Valid codes are matched exactly and take precedence, so
+// "CT" is a carton while "ct" is a count; names and synonyms are matched case-insensitively.
+// An error is returned if unit cannot be resolved.
+func LookupUnitOfMeasurementCode(unit string) (string, error) {
+	if code := strings.TrimSpace(unit); IsValidUnitOfMeasurementCode(code) {
+		return code, nil
+	}
+	normalized := strings.ToLower(strings.Join(strings.Fields(unit), " "))
+	if code, ok := unitOfMeasurementToCode[normalized]; ok {
+		return code, nil
+	}
+	if name, ok := unitOfMeasurementSynonyms[normalized]; ok {
+		return unitOfMeasurementToCode[name], nil
+	}
+	if code := strings.ToUpper(normalized); IsValidUnitOfMeasurementCode(code) {
+		return code, nil
+	}
+	return "", fmt.Errorf("unknown unit of measurement: %s", unit)
+}
+
+// ConvertCodeToUnitOfMeasurement returns the name of the unit of measurement for an ISO code, e.g. "kilogram" for "KG"
+func ConvertCodeToUnitOfMeasurement(code string) (string, error) {
+	unit, ok := codeToUnitOfMeasurement[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return "", fmt.Errorf("unknown unit of measurement code: %s", code)
+	}
+	return unit, nil
+}
+
+// IsValidUnitOfMeasurementCode reports whether code is a known ISO unit of measurement code. The check is case-sensitive.
+func IsValidUnitOfMeasurementCode(code string) bool {
+	_, ok := codeToUnitOfMeasurement[code]
+	return ok
+}
+
+// LookupOptionalUnitOfMeasurementCode is LookupUnitOfMeasurementCode for optional units: a blank unit has no code.
+func LookupOptionalUnitOfMeasurementCode(unit string) (string, error) {
+	if strings.TrimSpace(unit) == "" {
+		return "", nil
+	}
+	return LookupUnitOfMeasurementCode(unit)
+}
+
 // ConvertUnitOfMeasurementToCode returns the codified version of the unit of measurement per
 // https://www.namm.org/standards/implementation-guide-/codes-tables/unit-measurement-uom-codes (not yet finalized).
 // If no code is found, we return the code for "each" as our best guess.
+//
+// Deprecated: use LookupUnitOfMeasurementCode, which reports unknown units instead of guessing.
 func ConvertUnitOfMeasurementToCode(unit string) string {
-	unitLower := strings.ToLower(unit)
-	code, ok := unitOfMeasurementToCode[unitLower]
-	if !ok {
+	code, err := LookupUnitOfMeasurementCode(unit)
+	if err != nil {
 		return unitOfMeasurementToCode["each"]
 	}
 	return code
@@ -0,0 +1,102 @@
+package common
+
+import "testing"
+
+func TestLookupUnitOfMeasurementCode(t *testing.T) {
+	cases := []struct {
+		unit    string
+		want    string
+		wantErr bool
+	}{
+		{"each", "EA", false},
+		{"Each", "EA", false},
+		{"EA", "EA", false},
+		{"ea", "EA", false},
+		{"count", "EA", false},
+		{"kg", "KG", false},
+		{"KG", "KG", false},
+		{"Kilograms", "KG", false},
+		{"  square   foot ", "SF", false},
+		{"sq ft", "SF", false},
+		{"lbs", "LB", false},
+		{"oz", "OZ", false},
+		{"4G", "4G", false},
+		{"box", "BX", false},
+		{"CT", "CT", false},
+		{"ct", "EA", false},
+		{"GM", "GM", false},
+		{"gm", "GR", false},
+		{"MG", "MG", false},
+		{"mg", "ME", false},
+		{"", "", true},
+		{"bushel of apples", "", true},
+		{"ZZ", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.unit, func(t *testing.T) {
+			got, err := LookupUnitOfMeasurementCode(c.unit)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLookupOptionalUnitOfMeasurementCode(t *testing.T) {
+	for _, unit := range []string{"", "  "} {
+		if got, err := LookupOptionalUnitOfMeasurementCode(unit); got != "" || err != nil {
+			t.Errorf("got %q and %v for %q, want no code", got, err, unit)
+		}
+	}
+	if got, err := LookupOptionalUnitOfMeasurementCode("kg"); got != "KG" || err != nil {
+		t.Errorf("got %q and %v, want KG", got, err)
+	}
+	if _, err := LookupOptionalUnitOfMeasurementCode("bushel of apples"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestConvertUnitOfMeasurementToCodeFallsBackToEach(t *testing.T) {
+	if got := ConvertUnitOfMeasurementToCode("bushel of apples"); got != "EA" {
+		t.Errorf("got %s, want EA", got)
+	}
+	if got := ConvertUnitOfMeasurementToCode("Pounds"); got != "LB" {
+		t.Errorf("got %s, want LB", got)
+	}
+}
+
+func TestConvertCodeToUnitOfMeasurement(t *testing.T) {
+	unit, err := ConvertCodeToUnitOfMeasurement("kg")
+	if err != nil || unit != "kilogram" {
+		t.Errorf("got (%s, %v), want kilogram", unit, err)
+	}
+	if _, err := ConvertCodeToUnitOfMeasurement("ZZ"); err == nil {
+		t.Error("expected error for unknown code")
+	}
+	for unit, code := range unitOfMeasurementToCode {
+		if got, _ := ConvertCodeToUnitOfMeasurement(code); got != unit {
+			t.Errorf("code %s maps back to %q, want %q", code, got, unit)
+		}
+	}
+}
+
+func TestUnitOfMeasurementSynonyms(t *testing.T) {
+	for synonym, unit := range unitOfMeasurementSynonyms {
+		if _, ok := unitOfMeasurementToCode[unit]; !ok {
+			t.Errorf("synonym %q refers to unknown unit %q", synonym, unit)
+		}
+		if _, ok := unitOfMeasurementToCode[synonym]; ok {
+			t.Errorf("synonym %q shadows an existing unit", synonym)
+		}
+	}
+}
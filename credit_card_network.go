@@ -21,3 +21,61 @@ const (
 	// CreditCardNetworkCitiPLCC citiplcc
 	CreditCardNetworkCitiPLCC
 )
+
+var creditCardNetworkToString = map[CreditCardNetwork]string{
+	CreditCardNetworkUnknown:    "CreditCardNetworkUnknown",
+	CreditCardNetworkVisa:       "CreditCardNetworkVisa",
+	CreditCardNetworkMastercard: "CreditCardNetworkMastercard",
+	CreditCardNetworkAmex:       "CreditCardNetworkAmex",
+	CreditCardNetworkDiscover:   "CreditCardNetworkDiscover",
+	CreditCardNetworkJcb:        "CreditCardNetworkJcb",
+	CreditCardNetworkUnionpay:   "CreditCardNetworkUnionpay",
+	CreditCardNetworkCitiPLCC:   "CreditCardNetworkCitiPLCC",
+}
+
+// String returns a string representation of a card network
+func (network CreditCardNetwork) String() string {
+	return creditCardNetworkToString[network]
+}
+
+var stringToCreditCardNetwork = func() map[string]CreditCardNetwork {
+	values := make(map[string]CreditCardNetwork, len(creditCardNetworkToString))
+	for value, name := range creditCardNetworkToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseCreditCardNetwork returns the card network with the given name
+func ParseCreditCardNetwork(name string) (CreditCardNetwork, error) {
+	network, ok := stringToCreditCardNetwork[name]
+	if !ok {
+		return CreditCardNetworkUnknown, &EnumNameError{Enum: "CreditCardNetwork", Name: name}
+	}
+	return network, nil
+}
+
+// MarshalText encodes the card network as its name
+func (network CreditCardNetwork) MarshalText() ([]byte, error) {
+	return marshalEnumText("CreditCardNetwork", int(network), network.String())
+}
+
+// UnmarshalText decodes the card network from its name
+func (network *CreditCardNetwork) UnmarshalText(text []byte) error {
+	parsed, err := ParseCreditCardNetwork(string(text))
+	if err != nil {
+		return err
+	}
+	*network = parsed
+	return nil
+}
+
+// MarshalJSON encodes the card network as a JSON string of its name
+func (network CreditCardNetwork) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(network)
+}
+
+// UnmarshalJSON decodes the card network from a JSON string of its name, or from a JSON number of its value
+func (network *CreditCardNetwork) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("CreditCardNetwork", data, network, func(value int) string { return CreditCardNetwork(value).String() })
+}
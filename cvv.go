@@ -34,3 +34,45 @@ var cvvCodeToString = map[CVVResponse]string{
 func (code CVVResponse) String() string {
 	return cvvCodeToString[code]
 }
+
+var stringToCVVCode = func() map[string]CVVResponse {
+	values := make(map[string]CVVResponse, len(cvvCodeToString))
+	for value, name := range cvvCodeToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseCVVResponse returns the CVV response code with the given name
+func ParseCVVResponse(name string) (CVVResponse, error) {
+	code, ok := stringToCVVCode[name]
+	if !ok {
+		return CVVResponseUnknown, &EnumNameError{Enum: "CVVResponse", Name: name}
+	}
+	return code, nil
+}
+
+// MarshalText encodes the CVV response code as its name
+func (code CVVResponse) MarshalText() ([]byte, error) {
+	return marshalEnumText("CVVResponse", int(code), code.String())
+}
+
+// UnmarshalText decodes the CVV response code from its name
+func (code *CVVResponse) UnmarshalText(text []byte) error {
+	parsed, err := ParseCVVResponse(string(text))
+	if err != nil {
+		return err
+	}
+	*code = parsed
+	return nil
+}
+
+// MarshalJSON encodes the CVV response code as a JSON string of its name
+func (code CVVResponse) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(code)
+}
+
+// UnmarshalJSON decodes the CVV response code from a JSON string of its name, or from a JSON number of its value
+func (code *CVVResponse) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("CVVResponse", data, code, func(value int) string { return CVVResponse(value).String() })
+}
@@ -0,0 +1,151 @@
+package sleet
+
+// DeclineReason is a gateway independent classification of why a transaction was declined.
+type DeclineReason int
+
+// Consts representing the decline reasons the gateway specific codes are translated into
+const (
+	DeclineReasonNone                    DeclineReason = iota // The transaction was not declined.
+	DeclineReasonUnknown                                      // The gateway code is not mapped to a reason.
+	DeclineReasonInsufficientFunds                            // The account does not have enough funds.
+	DeclineReasonDoNotHonor                                   // Generic issuer decline without a specific reason.
+	DeclineReasonExpiredCard                                  // The card is expired.
+	DeclineReasonInvalidCardNumber                            // The card number is invalid or the account does not exist.
+	DeclineReasonInvalidExpirationDate                        // The expiration date is invalid or does not match.
+	DeclineReasonLostOrStolenCard                             // The card was reported lost or stolen.
+	DeclineReasonSuspectedFraud                               // The issuer or gateway suspects fraud.
+	DeclineReasonCVVFailure                                   // The CVV did not match or is missing.
+	DeclineReasonAVSFailure                                   // The address verification failed.
+	DeclineReasonLimitExceeded                                // An amount or frequency limit was exceeded.
+	DeclineReasonRestrictedCard                               // The card cannot be used for this type of transaction.
+	DeclineReasonTransactionNotPermitted                      // The transaction is not permitted to the cardholder or merchant.
+	DeclineReasonCallIssuer                                   // The issuer requests a voice authorization.
+	DeclineReasonPickUpCard                                   // The issuer requests the card to be retained.
+	DeclineReasonInvalidAmount                                // The amount is invalid.
+	DeclineReasonAuthenticationRequired                       // The issuer requires cardholder authentication such as 3D Secure.
+	DeclineReasonDuplicateTransaction                         // The transaction was identified as a duplicate.
+	DeclineReasonIssuerUnavailable                            // The issuer or network could not be reached.
+	DeclineReasonProcessingError                              // The gateway or processor failed to process the transaction.
+)
+
+var declineReasonToString = map[DeclineReason]string{
+	DeclineReasonNone:                    "DeclineReasonNone",
+	DeclineReasonUnknown:                 "DeclineReasonUnknown",
+	DeclineReasonInsufficientFunds:       "DeclineReasonInsufficientFunds",
+	DeclineReasonDoNotHonor:              "DeclineReasonDoNotHonor",
+	DeclineReasonExpiredCard:             "DeclineReasonExpiredCard",
+	DeclineReasonInvalidCardNumber:       "DeclineReasonInvalidCardNumber",
+	DeclineReasonInvalidExpirationDate:   "DeclineReasonInvalidExpirationDate",
+	DeclineReasonLostOrStolenCard:        "DeclineReasonLostOrStolenCard",
+	DeclineReasonSuspectedFraud:          "DeclineReasonSuspectedFraud",
+	DeclineReasonCVVFailure:              "DeclineReasonCVVFailure",
+	DeclineReasonAVSFailure:              "DeclineReasonAVSFailure",
+	DeclineReasonLimitExceeded:           "DeclineReasonLimitExceeded",
+	DeclineReasonRestrictedCard:          "DeclineReasonRestrictedCard",
+	DeclineReasonTransactionNotPermitted: "DeclineReasonTransactionNotPermitted",
+	DeclineReasonCallIssuer:              "DeclineReasonCallIssuer",
+	DeclineReasonPickUpCard:              "DeclineReasonPickUpCard",
+	DeclineReasonInvalidAmount:           "DeclineReasonInvalidAmount",
+	DeclineReasonAuthenticationRequired:  "DeclineReasonAuthenticationRequired",
+	DeclineReasonDuplicateTransaction:    "DeclineReasonDuplicateTransaction",
+	DeclineReasonIssuerUnavailable:       "DeclineReasonIssuerUnavailable",
+	DeclineReasonProcessingError:         "DeclineReasonProcessingError",
+}
+
+// String returns a string representation of a decline reason
+func (reason DeclineReason) String() string {
+	return declineReasonToString[reason]
+}
+
+var stringToDeclineReason = func() map[string]DeclineReason {
+	values := make(map[string]DeclineReason, len(declineReasonToString))
+	for value, name := range declineReasonToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseDeclineReason returns the decline reason with the given name
+func ParseDeclineReason(name string) (DeclineReason, error) {
+	reason, ok := stringToDeclineReason[name]
+	if !ok {
+		return DeclineReasonNone, &EnumNameError{Enum: "DeclineReason", Name: name}
+	}
+	return reason, nil
+}
+
+// MarshalText encodes the decline reason as its name
+func (reason DeclineReason) MarshalText() ([]byte, error) {
+	return marshalEnumText("DeclineReason", int(reason), reason.String())
+}
+
+// UnmarshalText decodes the decline reason from its name
+func (reason *DeclineReason) UnmarshalText(text []byte) error {
+	parsed, err := ParseDeclineReason(string(text))
+	if err != nil {
+		return err
+	}
+	*reason = parsed
+	return nil
+}
+
+// MarshalJSON encodes the decline reason as a JSON string of its name
+func (reason DeclineReason) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(reason)
+}
+
+// UnmarshalJSON decodes the decline reason from a JSON string of its name, or from a JSON number of its value
+func (reason *DeclineReason) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("DeclineReason", data, reason, func(value int) string { return DeclineReason(value).String() })
+}
+
+// DeclineReasonFromISO8583 translates a two character ISO 8583 authorization response code, as forwarded by several
+// gateways from the card networks, to a DeclineReason. Approval codes translate to DeclineReasonNone.
+func DeclineReasonFromISO8583(code string) DeclineReason {
+	reason, ok := iso8583ToDeclineReason[code]
+	if !ok {
+		return DeclineReasonUnknown
+	}
+	return reason
+}
+
+var iso8583ToDeclineReason = map[string]DeclineReason{
+	"00": DeclineReasonNone,
+	"01": DeclineReasonCallIssuer,
+	"02": DeclineReasonCallIssuer,
+	"03": DeclineReasonTransactionNotPermitted,
+	"04": DeclineReasonPickUpCard,
+	"05": DeclineReasonDoNotHonor,
+	"06": DeclineReasonProcessingError,
+	"07": DeclineReasonPickUpCard,
+	"10": DeclineReasonNone,
+	"12": DeclineReasonTransactionNotPermitted,
+	"13": DeclineReasonInvalidAmount,
+	"14": DeclineReasonInvalidCardNumber,
+	"15": DeclineReasonInvalidCardNumber,
+	"19": DeclineReasonProcessingError,
+	"41": DeclineReasonLostOrStolenCard,
+	"43": DeclineReasonLostOrStolenCard,
+	"51": DeclineReasonInsufficientFunds,
+	"54": DeclineReasonExpiredCard,
+	"55": DeclineReasonTransactionNotPermitted,
+	"57": DeclineReasonTransactionNotPermitted,
+	"58": DeclineReasonTransactionNotPermitted,
+	"59": DeclineReasonSuspectedFraud,
+	"61": DeclineReasonLimitExceeded,
+	"62": DeclineReasonRestrictedCard,
+	"63": DeclineReasonSuspectedFraud,
+	"65": DeclineReasonLimitExceeded,
+	"75": DeclineReasonLimitExceeded,
+	"78": DeclineReasonRestrictedCard,
+	"85": DeclineReasonNone,
+	"91": DeclineReasonIssuerUnavailable,
+	"92": DeclineReasonIssuerUnavailable,
+	"93": DeclineReasonTransactionNotPermitted,
+	"94": DeclineReasonDuplicateTransaction,
+	"96": DeclineReasonProcessingError,
+	"1A": DeclineReasonAuthenticationRequired,
+	"N7": DeclineReasonCVVFailure,
+	"R0": DeclineReasonTransactionNotPermitted,
+	"R1": DeclineReasonTransactionNotPermitted,
+}
@@ -0,0 +1,37 @@
+package sleet
+
+import "testing"
+
+func TestDeclineReasonString(t *testing.T) {
+	for reason := DeclineReasonNone; reason <= DeclineReasonProcessingError; reason++ {
+		if reason.String() == "" {
+			t.Errorf("decline reason %d has no name", reason)
+		}
+	}
+}
+
+func TestDeclineReasonFromISO8583(t *testing.T) {
+	cases := []struct {
+		in   string
+		want DeclineReason
+	}{
+		{"00", DeclineReasonNone},
+		{"05", DeclineReasonDoNotHonor},
+		{"14", DeclineReasonInvalidCardNumber},
+		{"41", DeclineReasonLostOrStolenCard},
+		{"51", DeclineReasonInsufficientFunds},
+		{"54", DeclineReasonExpiredCard},
+		{"N7", DeclineReasonCVVFailure},
+		{"91", DeclineReasonIssuerUnavailable},
+		{"XX", DeclineReasonUnknown},
+		{"", DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			if got := DeclineReasonFromISO8583(c.in); got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package sleet
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// EnumNameError is returned when an enum value has no name, or a name does not match any value of the enum
+type EnumNameError struct {
+	Enum string
+	Name string
+}
+
+func (e *EnumNameError) Error() string {
+	return fmt.Sprintf("sleet: unknown %s %q", e.Enum, e.Name)
+}
+
+// marshalEnumText returns name, or an EnumNameError if the value of the enum has no name
+func marshalEnumText(enum string, value int, name string) ([]byte, error) {
+	if name == "" {
+		return nil, &EnumNameError{Enum: enum, Name: strconv.Itoa(value)}
+	}
+	return []byte(name), nil
+}
+
+// marshalEnumJSON encodes the text representation of an enum as a JSON string
+func marshalEnumJSON(value encoding.TextMarshaler) ([]byte, error) {
+	text, err := value.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// unmarshalEnumJSON decodes a JSON string through the UnmarshalText of the enum. JSON numbers are accepted as well so
+// that values serialized before enums had names can still be read; name converts them to the name of the value.
+func unmarshalEnumJSON(enum string, data []byte, value encoding.TextUnmarshaler, name func(int) string) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return value.UnmarshalText([]byte(text))
+	}
+	var number int
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("sleet: %s must be a JSON string or number: %w", enum, err)
+	}
+	text = name(number)
+	if text == "" {
+		return &EnumNameError{Enum: enum, Name: strconv.Itoa(number)}
+	}
+	return value.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,215 @@
+package sleet
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// enumNames returns the name of a value of each enum, by enum type
+var enumNames = map[string]func(value int) string{
+	"AVSResponse":       func(value int) string { return AVSResponse(value).String() },
+	"AVSMatch":          func(value int) string { return AVSMatch(value).String() },
+	"CVVResponse":       func(value int) string { return CVVResponse(value).String() },
+	"CreditCardNetwork": func(value int) string { return CreditCardNetwork(value).String() },
+	"DeclineReason":     func(value int) string { return DeclineReason(value).String() },
+	"DeclineType":       func(value int) string { return DeclineType(value).String() },
+	"RetryAdviceSource": func(value int) string { return RetryAdviceSource(value).String() },
+	"PendingReason":     func(value int) string { return PendingReason(value).String() },
+	"Level3Derivation":  func(value int) string { return Level3Derivation(value).String() },
+}
+
+// TestEnumConstantsHaveNames type checks the package source so that every constant of an enum is checked, including
+// constants added after this test was written.
+func TestEnumConstantsHaveNames(t *testing.T) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []*ast.File
+	for _, file := range packages["sleet"].Files {
+		files = append(files, file)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check("github.com/BoltApp/sleet", fset, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scope := pkg.Scope()
+	seen := map[string]bool{}
+	for _, identifier := range scope.Names() {
+		constValue, ok := scope.Lookup(identifier).(*types.Const)
+		if !ok {
+			continue
+		}
+		named, ok := constValue.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != pkg {
+			continue
+		}
+		if basic, ok := named.Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+			continue
+		}
+		enum := named.Obj().Name()
+		name, ok := enumNames[enum]
+		if !ok {
+			t.Errorf("enum %s is missing from enumNames", enum)
+			continue
+		}
+		seen[enum] = true
+		value, _ := constant.Int64Val(constValue.Val())
+		got := name(int(value))
+		if got == "" {
+			t.Errorf("%s constant %s has no name", enum, identifier)
+			continue
+		}
+		if got != identifier {
+			t.Errorf("%s constant %s is named %q", enum, identifier, got)
+		}
+	}
+	for enum := range enumNames {
+		if !seen[enum] {
+			t.Errorf("enum %s has no constants", enum)
+		}
+	}
+}
+
+func TestEnumTextRoundTrip(t *testing.T) {
+	cases := []struct {
+		value  encoding.TextMarshaler
+		parsed encoding.TextUnmarshaler
+		name   string
+	}{
+		{AVSResponseNameNoMatchZipMatch, new(AVSResponse), "AVSResponseNameNoMatchZipMatch"},
+		{AVSMatchNo, new(AVSMatch), "AVSMatchNo"},
+		{CVVResponseSuspicious, new(CVVResponse), "CVVResponseSuspicious"},
+		{CreditCardNetworkAmex, new(CreditCardNetwork), "CreditCardNetworkAmex"},
+		{DeclineReasonExpiredCard, new(DeclineReason), "DeclineReasonExpiredCard"},
+		{DeclineTypeHard, new(DeclineType), "DeclineTypeHard"},
+		{RetryAdviceSourceVisaCategory, new(RetryAdviceSource), "RetryAdviceSourceVisaCategory"},
+		{PendingReasonFraudReview, new(PendingReason), "PendingReasonFraudReview"},
+		{Level3DeriveLineItems, new(Level3Derivation), "Level3DeriveLineItems"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			text, err := c.value.MarshalText()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(text) != c.name {
+				t.Errorf("Got %q, want %q", text, c.name)
+			}
+			if err := c.parsed.UnmarshalText(text); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := deep.Equal(c.parsed, ptrTo(c.value)); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func ptrTo(value encoding.TextMarshaler) interface{} {
+	switch v := value.(type) {
+	case AVSResponse:
+		return &v
+	case AVSMatch:
+		return &v
+	case CVVResponse:
+		return &v
+	case CreditCardNetwork:
+		return &v
+	case DeclineReason:
+		return &v
+	case DeclineType:
+		return &v
+	case RetryAdviceSource:
+		return &v
+	case PendingReason:
+		return &v
+	case Level3Derivation:
+		return &v
+	}
+	return nil
+}
+
+func TestEnumJSON(t *testing.T) {
+	type event struct {
+		AvsResult     AVSResponse
+		CvvResult     CVVResponse
+		Network       CreditCardNetwork
+		DeclineReason DeclineReason
+		Networks      map[CreditCardNetwork]int
+	}
+	want := event{
+		AvsResult:     AVSResponseNameNoMatchZipMatchAddressMatch,
+		CvvResult:     CVVResponseNoMatch,
+		Network:       CreditCardNetworkVisa,
+		DeclineReason: DeclineReasonCVVFailure,
+		Networks:      map[CreditCardNetwork]int{CreditCardNetworkMastercard: 2},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	wantJSON := `{"AvsResult":"AVSResponseNameNoMatchZipMatchAddressMatch","CvvResult":"CVVResponseNoMatch",` +
+		`"Network":"CreditCardNetworkVisa","DeclineReason":"DeclineReasonCVVFailure","Networks":{"CreditCardNetworkMastercard":2}}`
+	if string(data) != wantJSON {
+		t.Errorf("Got %s, want %s", data, wantJSON)
+	}
+
+	var got event
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Error(diff)
+	}
+
+	t.Run("Legacy numbers", func(t *testing.T) {
+		var got event
+		if err := json.Unmarshal([]byte(`{"AvsResult":0,"CvvResult":5,"Network":1,"DeclineReason":null}`), &got); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if diff := deep.Equal(got, event{AvsResult: AVSResponseUnknown, CvvResult: CVVResponseNoMatch, Network: CreditCardNetworkVisa}); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestEnumNameErrors(t *testing.T) {
+	var nameErr *EnumNameError
+
+	if _, err := ParseAVSResponse("AVSResponseMaybe"); !errors.As(err, &nameErr) || nameErr.Enum != "AVSResponse" {
+		t.Errorf("expected EnumNameError, got %v", err)
+	}
+	if _, err := CVVResponse(1000).MarshalText(); !errors.As(err, &nameErr) || nameErr.Name != "1000" {
+		t.Errorf("expected EnumNameError, got %v", err)
+	}
+	if _, err := json.Marshal(CreditCardNetwork(-1)); !errors.As(err, &nameErr) {
+		t.Errorf("expected EnumNameError, got %v", err)
+	}
+	var network CreditCardNetwork
+	if err := json.Unmarshal([]byte(`42`), &network); !errors.As(err, &nameErr) {
+		t.Errorf("expected EnumNameError, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`"visa"`), &network); !errors.As(err, &nameErr) {
+		t.Errorf("expected EnumNameError, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package adyen
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/adyen/adyen-go-api-library/v4/src/adyen"
 	adyen_common "github.com/adyen/adyen-go-api-library/v4/src/common"
@@ -25,21 +26,42 @@ type AdyenClient struct {
 	liveURLPrefix   string
 	environment     common.Environment
 	httpClient      *http.Client
+	adyenClient     *adyen.APIClient
 }
 
 // NewClient creates an Adyen client with creds and default http client
-func NewClient(merchantAccount string, apiKey string, liveURLPrefix string, env common.Environment) *AdyenClient {
-	return NewWithHTTPClient(merchantAccount, apiKey, liveURLPrefix, env, common.DefaultHttpClient())
+func NewClient(merchantAccount string, apiKey string, liveURLPrefix string, env common.Environment, options ...common.ClientOption) *AdyenClient {
+	return NewWithHTTPClient(merchantAccount, apiKey, liveURLPrefix, env, common.DefaultHttpClient(), options...)
 }
 
-// NewWithHTTPClient creates an Adyen client with creds and user specified http client for custom behavior
-func NewWithHTTPClient(merchantAccount string, apiKey string, liveURLPrefix string, env common.Environment, httpClient *http.Client) *AdyenClient {
+// NewWithHTTPClient creates an Adyen client with creds and user specified http client for custom behavior.
+// common.WithBaseURL replaces the Checkout API endpoint and common.WithSecondaryBaseURL the Payments API endpoint used
+// for captures, refunds and voids, which defaults to the base URL when only that is given. Regional live endpoints
+// are set this way.
+func NewWithHTTPClient(merchantAccount string, apiKey string, liveURLPrefix string, env common.Environment, httpClient *http.Client, options ...common.ClientOption) *AdyenClient {
+	// the Adyen client only reads its configuration once built, so one is shared by all requests
+	adyenClient := adyen.NewClient(&adyen_common.Config{
+		ApiKey:                apiKey,
+		LiveEndpointURLPrefix: liveURLPrefix,
+		MerchantAccount:       merchantAccount,
+		Environment:           Environment(env),
+		HTTPClient:            httpClient,
+	})
+	clientOptions := common.NewClientOptions(options...)
+	if clientOptions.BaseURL != "" {
+		adyenClient.GetConfig().CheckoutEndpoint = clientOptions.BaseURL
+	}
+	if paymentsURL := clientOptions.SecondaryBaseURLOr(clientOptions.BaseURL); paymentsURL != "" {
+		adyenClient.GetConfig().Endpoint = paymentsURL
+	}
+
 	return &AdyenClient{
 		environment:     env,
 		apiKey:          apiKey,
 		liveURLPrefix:   liveURLPrefix,
 		merchantAccount: merchantAccount,
 		httpClient:      httpClient,
+		adyenClient:     adyenClient,
 	}
 }
 
@@ -53,15 +75,27 @@ func (client *AdyenClient) Authorize(request *sleet.AuthorizationRequest) (*slee
 // Note: In order to be compliant, a credit card CVV is required for all transactions where a customer did not agree
 // to have their card information saved or where a customer does not have a previous transaction with the caller.
 func (client *AdyenClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	adyenClient := adyen.NewClient(&adyen_common.Config{
-		ApiKey:                client.apiKey,
-		LiveEndpointURLPrefix: client.liveURLPrefix,
-		MerchantAccount:       client.merchantAccount,
-		Environment:           Environment(client.environment),
-		HTTPClient:            client.httpClient,
-	})
-
-	result, httpResp, err := adyenClient.Checkout.Payments(buildAuthRequest(request, client.merchantAccount), ctx)
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	request, err := sleet.PrepareLevel3Data(request)
+	if err != nil {
+		return nil, err
+	}
+	paymentRequest, err := buildAuthRequest(request, client.merchantAccount)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyKey, _ := request.Options[sleet.IdempotencyKeyOption].(string)
+	if idempotencyKey != "" {
+		ctx = adyen_common.WithIdempotencyKey(ctx, idempotencyKey)
+	}
+	result, httpResp, err := client.adyenClient.Checkout.Payments(paymentRequest, ctx)
+	// Adyen has no lookup by reference. An authorization sent with an idempotency key is sent once more after a
+	// network error, which returns the original response instead of authorizing twice. It is not sent once ctx is done.
+	if err != nil && idempotencyKey != "" && sleet.IsNetworkError(err) && ctx.Err() == nil {
+		result, httpResp, err = client.adyenClient.Checkout.Payments(paymentRequest, ctx)
+	}
 	var (
 		statusCode     int
 		responseHeader http.Header
@@ -112,6 +146,13 @@ func (client *AdyenClient) AuthorizeWithContext(ctx context.Context, request *sl
 		response.ErrorCode = result.RefusalReasonCode
 		response.Response = result.RefusalReason
 		response.ResultType = sleet.ResultTypePaymentError
+		response.DeclineReason = translateDeclineReason(result.RefusalReasonCode)
+		response.RetryAdvice = sleet.NewRetryAdvice(
+			response.DeclineReason,
+			request.CreditCard.Network,
+			response.MerchantAdviceCode,
+			response.NetworkResponseCode,
+		)
 	}
 	return response, nil
 }
@@ -123,15 +164,7 @@ func (client *AdyenClient) Capture(request *sleet.CaptureRequest) (*sleet.Captur
 
 // CaptureWithContext captures an existing transaction by reference
 func (client *AdyenClient) CaptureWithContext(ctx context.Context, request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
-	adyenClient := adyen.NewClient(&adyen_common.Config{
-		ApiKey:                client.apiKey,
-		LiveEndpointURLPrefix: client.liveURLPrefix,
-		MerchantAccount:       client.merchantAccount,
-		Environment:           Environment(client.environment),
-		HTTPClient:            client.httpClient,
-	})
-
-	capture, _, err := adyenClient.Payments.Capture(buildCaptureRequest(request, client.merchantAccount), ctx)
+	capture, _, err := client.adyenClient.Payments.Capture(buildCaptureRequest(request, client.merchantAccount), ctx)
 	if err != nil {
 		return &sleet.CaptureResponse{Success: false, TransactionReference: ""}, err
 	}
@@ -148,15 +181,10 @@ func (client *AdyenClient) Refund(request *sleet.RefundRequest) (*sleet.RefundRe
 
 // Refund a captured transaction by reference with specified amount
 func (client *AdyenClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
-	adyenClient := adyen.NewClient(&adyen_common.Config{
-		ApiKey:                client.apiKey,
-		LiveEndpointURLPrefix: client.liveURLPrefix,
-		MerchantAccount:       client.merchantAccount,
-		Environment:           Environment(client.environment),
-		HTTPClient:            client.httpClient,
-	})
-
-	refund, _, err := adyenClient.Payments.Refund(buildRefundRequest(request, client.merchantAccount), ctx)
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
+	refund, _, err := client.adyenClient.Payments.Refund(buildRefundRequest(request, client.merchantAccount), ctx)
 	if err != nil {
 		return &sleet.RefundResponse{Success: false, TransactionReference: ""}, err
 	}
@@ -173,15 +201,10 @@ func (client *AdyenClient) Void(request *sleet.VoidRequest) (*sleet.VoidResponse
 
 // VoidWithContext voids an authorized transaction (cancels the authorization)
 func (client *AdyenClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
-	adyenClient := adyen.NewClient(&adyen_common.Config{
-		ApiKey:                client.apiKey,
-		LiveEndpointURLPrefix: client.liveURLPrefix,
-		MerchantAccount:       client.merchantAccount,
-		Environment:           Environment(client.environment),
-		HTTPClient:            client.httpClient,
-	})
-
-	void, _, err := adyenClient.Payments.Cancel(buildVoidRequest(request, client.merchantAccount), ctx)
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
+	void, _, err := client.adyenClient.Payments.Cancel(buildVoidRequest(request, client.merchantAccount), ctx)
 	if err != nil {
 		return &sleet.VoidResponse{Success: false, TransactionReference: ""}, err
 	}
@@ -207,6 +230,9 @@ func addAdditionalDataFields(
 	if cvcRaw, isPresent := additionalData["cvcResultRaw"]; isPresent {
 		response.CvvResultRaw = cvcRaw.(string)
 	}
+	response.NetworkResponseCode = leadingCode(additionalDataString(additionalData, "refusalReasonRaw"))
+	response.MerchantAdviceCode = leadingCode(additionalDataString(additionalData, "merchantAdviceCode"))
+	response.NetworkTransactionID = additionalDataString(additionalData, "networkTxReference")
 
 	// set adyen additional recurring info on response
 	response.AdyenAdditionalData = getAdyenAdditionalData(additionalData)
@@ -216,6 +242,20 @@ func addAdditionalDataFields(
 	return err
 }
 
+// additionalDataString returns the string value of key in additionalData, or an empty string if it is not set
+func additionalDataString(additionalData map[string]interface{}, key string) string {
+	value, _ := additionalData[key].(string)
+	return value
+}
+
+// leadingCode strips the description Adyen appends to raw codes, as in "05 : Do Not Honor"
+func leadingCode(value string) string {
+	if i := strings.Index(value, ":"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
 func getAdyenAdditionalData(additionalData map[string]interface{}) map[string]string {
 	adyenMap := make(map[string]string)
 
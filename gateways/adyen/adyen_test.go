@@ -0,0 +1,166 @@
+//go:build unit
+// +build unit
+
+package adyen
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adyen/adyen-go-api-library/v4/src/adyen"
+	"github.com/go-test/deep"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// authorisedTransport answers every request with an authorised payment
+type authorisedTransport struct{}
+
+func (authorisedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"pspReference": "8515131751004933", "resultCode": "Authorised"}`)),
+		Request:    req,
+	}, nil
+}
+
+// BenchmarkAuthorize compares a client shared by all authorizations with a client built for each one, which pays
+// for building the Adyen client on every request
+func BenchmarkAuthorize(b *testing.B) {
+	httpClient := &http.Client{Transport: authorisedTransport{}}
+	request := sleet_testing.BaseAuthorizationRequest()
+	newClient := func() *AdyenClient {
+		return NewWithHTTPClient("merchant", "key", "", common.Sandbox, httpClient)
+	}
+
+	b.Run("SharedClient", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SharedClientParallel", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := client.Authorize(request); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	b.Run("ClientPerRequest", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := newClient().Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	capture := &sleet.CaptureRequest{Amount: &sleet.Amount{Amount: 100, Currency: "USD"}, TransactionReference: "8515131751004933"}
+
+	t.Run("Base URL", func(t *testing.T) {
+		transport := &failingTransport{}
+		client := NewWithHTTPClient("merchant", "key", "", common.Production, &http.Client{Transport: transport},
+			common.WithBaseURL("http://localhost:8080"))
+
+		_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+		_, _ = client.Capture(capture)
+
+		want := []string{
+			"http://localhost:8080/" + adyen.CheckoutAPIVersion + "/payments",
+			"http://localhost:8080/pal/servlet/Payment/" + adyen.APIVersion + "/capture",
+		}
+		if diff := deep.Equal(transport.urls, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Regional endpoints", func(t *testing.T) {
+		transport := &failingTransport{}
+		client := NewWithHTTPClient("merchant", "key", "", common.Production, &http.Client{Transport: transport},
+			common.WithBaseURL("https://prefix-checkout-live-us.adyenpayments.com/checkout"),
+			common.WithSecondaryBaseURL("https://prefix-pal-live-us.adyenpayments.com"))
+
+		_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+		_, _ = client.Capture(capture)
+
+		want := []string{
+			"https://prefix-checkout-live-us.adyenpayments.com/checkout/" + adyen.CheckoutAPIVersion + "/payments",
+			"https://prefix-pal-live-us.adyenpayments.com/pal/servlet/Payment/" + adyen.APIVersion + "/capture",
+		}
+		if diff := deep.Equal(transport.urls, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
+
+// flakyTransport fails the first request with a network error and answers the following ones with an authorised
+// payment. It records the idempotency key of every request.
+type flakyTransport struct {
+	idempotencyKeys []string
+}
+
+func (transport *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.idempotencyKeys = append(transport.idempotencyKeys, req.Header.Get("Idempotency-Key"))
+	if len(transport.idempotencyKeys) == 1 {
+		return nil, errors.New("connection reset by peer")
+	}
+	return authorisedTransport{}.RoundTrip(req)
+}
+
+func TestAuthorizeIdempotencyKey(t *testing.T) {
+	t.Run("Sent again with an idempotency key", func(t *testing.T) {
+		transport := &flakyTransport{}
+		client := NewWithHTTPClient("merchant", "key", "", common.Sandbox, &http.Client{Transport: transport})
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.Options = map[string]interface{}{sleet.IdempotencyKeyOption: "idempotency-key"}
+
+		got, err := client.Authorize(request)
+		if err != nil || !got.Success {
+			t.Fatalf("expected the original response, got %v, %v", got, err)
+		}
+		if diff := deep.Equal(transport.idempotencyKeys, []string{"idempotency-key", "idempotency-key"}); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Sent once without an idempotency key", func(t *testing.T) {
+		transport := &flakyTransport{}
+		client := NewWithHTTPClient("merchant", "key", "", common.Sandbox, &http.Client{Transport: transport})
+
+		if _, err := client.Authorize(sleet_testing.BaseAuthorizationRequest()); err == nil {
+			t.Error("expected the network error")
+		}
+		if diff := deep.Equal(transport.idempotencyKeys, []string{""}); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
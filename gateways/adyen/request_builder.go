@@ -2,7 +2,6 @@ package adyen
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 
 	"github.com/adyen/adyen-go-api-library/v4/src/checkout"
@@ -36,8 +35,6 @@ const (
 	recurringProcessingModelUnscheduledCardOnFile = "UnscheduledCardOnFile"
 )
 
-var streetNumberRegex = regexp.MustCompile(`^(\d+)\s(.*)`)
-
 // these maps are based on https://docs.adyen.com/online-payments/tokenization/create-and-use-tokens#set-parameters-to-flag-transactions
 var initiatorTypeToShopperInteraction = map[sleet.ProcessingInitiatorType]string{
 	sleet.ProcessingInitiatorTypeInitialCardOnFile:         shopperInteractionEcommerce,
@@ -55,7 +52,7 @@ var initiatorTypeToRecurringProcessingModel = map[sleet.ProcessingInitiatorType]
 	sleet.ProcessingInitiatorTypeFollowingRecurring:        recurringProcessingModelSubscription,
 }
 
-func buildAuthRequest(authRequest *sleet.AuthorizationRequest, merchantAccount string) *checkout.PaymentRequest {
+func buildAuthRequest(authRequest *sleet.AuthorizationRequest, merchantAccount string) (*checkout.PaymentRequest, error) {
 	request := &checkout.PaymentRequest{
 		Amount: checkout.Amount{
 			Value:    authRequest.Amount.Amount,
@@ -92,7 +89,11 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest, merchantAccount s
 
 	level3 := authRequest.Level3Data
 	if level3 != nil {
-		request.AdditionalData = buildLevel3Data(level3)
+		additionalData, err := buildLevel3Data(level3)
+		if err != nil {
+			return nil, err
+		}
+		request.AdditionalData = additionalData
 	}
 
 	// Attach results of 3DS verification if performed (and not "R"ejected)
@@ -112,7 +113,7 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest, merchantAccount s
 		}
 	}
 
-	return request
+	return request, nil
 }
 
 // addPaymentSpecificFields adds fields to the Adyen Payment request that are dependent on the payment method
@@ -200,11 +201,11 @@ func addShopperData(authRequest *sleet.AuthorizationRequest, request *checkout.P
 		request.ShopperIP = authRequest.Options[shopperIPOption].(string)
 	}
 	if authRequest.BillingAddress.Email != nil {
-		request.ShopperEmail = common.SafeStr(authRequest.BillingAddress.Email)
+		request.ShopperEmail = common.SafeStr(common.NormalizeEmail(authRequest.BillingAddress.Email))
 	}
 }
 
-func buildLevel3Data(level3Data *sleet.Level3Data) map[string]string {
+func buildLevel3Data(level3Data *sleet.Level3Data) (map[string]string, error) {
 	additionalData := map[string]string{
 		"enhancedSchemeData.customerReference":     sleet.DefaultIfEmpty(level3Data.CustomerReference, level3Default),
 		"enhancedSchemeData.destinationPostalCode": level3Data.DestinationPostalCode,
@@ -219,6 +220,10 @@ func buildLevel3Data(level3Data *sleet.Level3Data) map[string]string {
 		if idx == 9 {
 			break
 		}
+		unitOfMeasure, err := common.LookupOptionalUnitOfMeasurementCode(lineItem.UnitOfMeasure)
+		if err != nil {
+			return nil, err
+		}
 		keyBase = fmt.Sprintf("enhancedSchemeData.itemDetailLine%d.", idx+1)
 		// Due to issues with the credit card networks, dont send any line item if discount amount is 0
 		if lineItem.ItemDiscountAmount.Amount > 0 {
@@ -229,7 +234,7 @@ func buildLevel3Data(level3Data *sleet.Level3Data) map[string]string {
 		additionalData[keyBase+"productCode"] = sleet.TruncateString(lineItem.ProductCode, maxProductCodeLength)
 		additionalData[keyBase+"quantity"] = strconv.Itoa(int(lineItem.Quantity))
 		additionalData[keyBase+"totalAmount"] = sleet.AmountToString(&lineItem.TotalAmount)
-		additionalData[keyBase+"unitOfMeasure"] = common.ConvertUnitOfMeasurementToCode(lineItem.UnitOfMeasure)
+		addIfNonEmpty(unitOfMeasure, keyBase+"unitOfMeasure", &additionalData)
 		additionalData[keyBase+"unitPrice"] = sleet.AmountToString(&lineItem.UnitPrice)
 	}
 
@@ -237,7 +242,7 @@ func buildLevel3Data(level3Data *sleet.Level3Data) map[string]string {
 	addIfNonEmpty(level3Data.DestinationCountryCode, "enhancedSchemeData.destinationCountryCode", &additionalData)
 	addIfNonEmpty(level3Data.DestinationAdminArea, "enhancedSchemeData.destinationStateProvinceCode", &additionalData)
 
-	return additionalData
+	return additionalData, nil
 }
 
 func buildCaptureRequest(captureRequest *sleet.CaptureRequest, merchantAccount string) *payments.ModificationRequest {
@@ -282,10 +287,5 @@ func addIfNonEmpty(value string, key string, data *map[string]string) {
 //	returns (streetNumber, streetName) format
 //	If address does not have leading street number, will return ("", street)
 func extractAdyenStreetFormat(streetAddress string) (string, string) {
-	streetExtraction := streetNumberRegex.FindStringSubmatch(streetAddress)
-	if streetExtraction == nil {
-		return "", streetAddress
-	}
-
-	return streetExtraction[1], streetExtraction[2]
+	return common.SplitStreetNumber(streetAddress)
 }
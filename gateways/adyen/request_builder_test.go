@@ -297,7 +297,10 @@ func TestBuildAuthRequest(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.label, func(t *testing.T) {
-			got := buildAuthRequest(c.in, "merchant-account")
+			got, err := buildAuthRequest(c.in, "merchant-account")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
 			if diff := deep.Equal(got, c.want); diff != nil {
 				t.Error(diff)
 			}
@@ -308,7 +311,7 @@ func TestBuildAuthRequest(t *testing.T) {
 func TestBuild3DSAuthRequest(t *testing.T) {
 	// Absent flow
 	request := sleet_testing.BaseAuthorizationRequest()
-	result := buildAuthRequest(request, "merchant-account")
+	result, _ := buildAuthRequest(request, "merchant-account")
 	if result.MpiData != nil {
 		t.Errorf("expected no 3DS fields in request since none were provided but got %v", result.MpiData)
 	}
@@ -317,7 +320,7 @@ func TestBuild3DSAuthRequest(t *testing.T) {
 	request = sleet_testing.BaseAuthorizationRequest()
 	request.ThreeDS = sleet_testing.Base3DS()
 	request.ThreeDS.PAResStatus = "R"
-	result = buildAuthRequest(request, "merchant-account")
+	result, _ = buildAuthRequest(request, "merchant-account")
 	if result.MpiData != nil {
 		t.Errorf("expected no 3DS fields in request due to rejection status but got %v", result.MpiData)
 	}
@@ -326,7 +329,7 @@ func TestBuild3DSAuthRequest(t *testing.T) {
 	request = sleet_testing.BaseAuthorizationRequest()
 	request.ThreeDS = sleet_testing.Base3DS()
 	request.ECI = "eci"
-	result = buildAuthRequest(request, "merchant-account")
+	result, _ = buildAuthRequest(request, "merchant-account")
 	if result.MpiData == nil {
 		expected := &checkout.ThreeDSecureData{
 			Cavv:              "cavv",
@@ -410,3 +413,13 @@ func enhanceBaseAuthorizationDataWithAdditionalFields(authRequest *sleet.Authori
 		RegionCode:     common.SPtr("IL"),
 	}
 }
+
+func TestBuildAuthRequestUnknownUnitOfMeasure(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Level3Data.LineItems[0].UnitOfMeasure = "bushel of apples"
+
+	if _, err := buildAuthRequest(request, "merchant-account"); err == nil {
+		t.Error("expected error for an unknown unit of measure")
+	}
+}
@@ -115,3 +115,45 @@ func translateAvs(adyenAVS AVSResponse) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// declineReasonMap translates Adyen refusal reason codes
+// https://docs.adyen.com/development-resources/refusal-reasons
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"2":  sleet.DeclineReasonDoNotHonor,              // Refused
+	"3":  sleet.DeclineReasonCallIssuer,              // Referral
+	"4":  sleet.DeclineReasonProcessingError,         // Acquirer Error
+	"5":  sleet.DeclineReasonRestrictedCard,          // Blocked Card
+	"6":  sleet.DeclineReasonExpiredCard,             // Expired Card
+	"7":  sleet.DeclineReasonInvalidAmount,           // Invalid Amount
+	"8":  sleet.DeclineReasonInvalidCardNumber,       // Invalid Card Number
+	"9":  sleet.DeclineReasonIssuerUnavailable,       // Issuer Unavailable
+	"10": sleet.DeclineReasonTransactionNotPermitted, // Not supported
+	"11": sleet.DeclineReasonAuthenticationRequired,  // 3D Not Authenticated
+	"12": sleet.DeclineReasonInsufficientFunds,       // Not enough balance
+	"14": sleet.DeclineReasonSuspectedFraud,          // Acquirer Fraud
+	"18": sleet.DeclineReasonLimitExceeded,           // Pin tries exceeded
+	"20": sleet.DeclineReasonSuspectedFraud,          // FRAUD
+	"21": sleet.DeclineReasonProcessingError,         // Not Submitted
+	"22": sleet.DeclineReasonSuspectedFraud,          // FRAUD-CANCELLED
+	"23": sleet.DeclineReasonTransactionNotPermitted, // Transaction Not Permitted
+	"24": sleet.DeclineReasonCVVFailure,              // CVC Declined
+	"25": sleet.DeclineReasonRestrictedCard,          // Restricted Card
+	"26": sleet.DeclineReasonTransactionNotPermitted, // Revocation Of Auth
+	"27": sleet.DeclineReasonDoNotHonor,              // Declined Non Generic
+	"28": sleet.DeclineReasonLimitExceeded,           // Withdrawal amount exceeded
+	"29": sleet.DeclineReasonLimitExceeded,           // Withdrawal count exceeded
+	"31": sleet.DeclineReasonSuspectedFraud,          // Issuer Suspected Fraud
+	"32": sleet.DeclineReasonAVSFailure,              // AVS Declined
+	"38": sleet.DeclineReasonAuthenticationRequired,  // Authentication required
+	"42": sleet.DeclineReasonAuthenticationRequired,  // 3DS Authentication Error
+	"46": sleet.DeclineReasonTransactionNotPermitted, // Transaction blocked by Adyen to prevent excessive retry fees
+}
+
+// translateDeclineReason converts an Adyen refusal reason code to its equivalent Sleet decline reason.
+func translateDeclineReason(refusalReasonCode string) sleet.DeclineReason {
+	reason, ok := declineReasonMap[refusalReasonCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
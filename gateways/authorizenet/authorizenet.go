@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 
@@ -13,7 +14,9 @@ import (
 
 var (
 	// assert client interface
-	_ sleet.ClientWithContext = &AuthorizeNetClient{}
+	_ sleet.ClientWithContext        = &AuthorizeNetClient{}
+	_ sleet.TransactionDetailsClient = &AuthorizeNetClient{}
+	_ sleet.TransactionLookupClient  = &AuthorizeNetClient{}
 )
 
 // AuthorizeNetClient uses merchant name and transaction key to process requests. Optionally can provide custom http clients
@@ -25,17 +28,17 @@ type AuthorizeNetClient struct {
 }
 
 // NewClient uses authentication above with a default http client
-func NewClient(merchantName string, transactionKey string, environment common.Environment) *AuthorizeNetClient {
-	return NewWithHttpClient(merchantName, transactionKey, environment, common.DefaultHttpClient())
+func NewClient(merchantName string, transactionKey string, environment common.Environment, options ...common.ClientOption) *AuthorizeNetClient {
+	return NewWithHttpClient(merchantName, transactionKey, environment, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient uses authentication with custom http client
-func NewWithHttpClient(merchantName string, transactionKey string, environment common.Environment, httpClient *http.Client) *AuthorizeNetClient {
+func NewWithHttpClient(merchantName string, transactionKey string, environment common.Environment, httpClient *http.Client, options ...common.ClientOption) *AuthorizeNetClient {
 	return &AuthorizeNetClient{
 		merchantName:   merchantName,
 		transactionKey: transactionKey,
 		httpClient:     httpClient,
-		url:            authorizeNetURL(environment),
+		url:            common.NewClientOptions(options...).BaseURLOr(authorizeNetBaseURL(environment)) + requestPath,
 	}
 }
 
@@ -46,7 +49,17 @@ func (client *AuthorizeNetClient) Authorize(request *sleet.AuthorizationRequest)
 
 // AuthorizeWithContext a transaction for specified amount using Auth.net REST APIs
 func (client *AuthorizeNetClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	authorizeNetAuthorizeRequest := buildAuthRequest(client.merchantName, client.transactionKey, request)
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	request, err := sleet.PrepareLevel3Data(request)
+	if err != nil {
+		return nil, err
+	}
+	authorizeNetAuthorizeRequest, err := buildAuthRequest(client.merchantName, client.transactionKey, request)
+	if err != nil {
+		return nil, err
+	}
 	response, httpResp, err := client.sendRequest(ctx, *authorizeNetAuthorizeRequest)
 	if err != nil {
 		return nil, err
@@ -59,7 +72,7 @@ func (client *AuthorizeNetClient) AuthorizeWithContext(ctx context.Context, requ
 	responseHeader := sleet.GetHTTPResponseHeader(request.Options, *httpResp)
 
 	resp := sleet.AuthorizationResponse{
-		Success:              txnResponse.ResponseCode == ResponseCodeApproved || txnResponse.ResponseCode == ResponseCodeHeld,
+		Success:              txnResponse.ResponseCode == ResponseCodeApproved,
 		TransactionReference: txnResponse.TransID,
 		AvsResult:            translateAvs(txnResponse.AVSResultCode),
 		CvvResult:            translateCvv(txnResponse.CVVResultCode),
@@ -70,6 +83,15 @@ func (client *AuthorizeNetClient) AuthorizeWithContext(ctx context.Context, requ
 		StatusCode:           httpResp.StatusCode,
 		Metadata:             buildResponseMetadata(txnResponse),
 		Header:               responseHeader,
+		NetworkTransactionID: txnResponse.NetworkTransID,
+	}
+	if txnResponse.ResponseCode == ResponseCodeHeld {
+		resp.ResultType = sleet.ResultTypePending
+		resp.PendingReason = sleet.PendingReasonFraudReview
+	} else if !resp.Success {
+		resp.DeclineReason = translateDeclineReason(txnResponse)
+		// Authorize.Net returns neither the issuer response code nor the Merchant Advice Code
+		resp.RetryAdvice = sleet.NewRetryAdvice(resp.DeclineReason, request.CreditCard.Network, "", "")
 	}
 
 	return &resp, nil
@@ -106,6 +128,9 @@ func (client *AuthorizeNetClient) Void(request *sleet.VoidRequest) (*sleet.VoidR
 
 // VoidWithContext voids an existing authorized transaction
 func (client *AuthorizeNetClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	authorizeNetCaptureRequest := buildVoidRequest(client.merchantName, client.transactionKey, request)
 	authorizeNetResponse, _, err := client.sendRequest(ctx, *authorizeNetCaptureRequest)
 	if err != nil {
@@ -129,7 +154,9 @@ func (client *AuthorizeNetClient) Refund(request *sleet.RefundRequest) (*sleet.R
 
 // RefundWithContext refunds a captured transaction with amount and captured transaction reference
 func (client *AuthorizeNetClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
-
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	if request.Options != nil && request.Options[sleet.GooglePayTokenOption] != nil {
 		transactionDetailsResponse, err := client.GetTransactionDetails(&sleet.TransactionDetailsRequest{
 			TransactionReference: request.TransactionReference,
@@ -188,12 +215,60 @@ func (client *AuthorizeNetClient) GetTransactionDetailsWithContext(ctx context.C
 		}, nil
 	}
 
+	resultType, pendingReason := translateTransactionStatus(authorizeNetResponse.Transaction.TransactionStatus)
 	return &sleet.TransactionDetailsResponse{
-		ResultCode: string(authorizeNetResponse.Messsages.ResultCode),
-		CardNumber: authorizeNetResponse.Transaction.Payment.CreditCard.CardNumber,
+		ResultCode:    string(authorizeNetResponse.Messsages.ResultCode),
+		CardNumber:    authorizeNetResponse.Transaction.Payment.CreditCard.CardNumber,
+		Status:        string(authorizeNetResponse.Transaction.TransactionStatus),
+		ResultType:    resultType,
+		PendingReason: pendingReason,
 	}, nil
 }
 
+// LookupTransactionWithContext looks up a transaction whose outcome is unknown. Captures are looked up by the
+// transaction reference of the authorization, authorizations are searched among the unsettled transactions by the
+// invoice number sent for their MerchantOrderReference, most recent first. sleet.ErrTransactionLookupIncomplete is
+// returned if the transaction is not among the unsettledTransactionListMaxPages most recent pages.
+func (client *AuthorizeNetClient) LookupTransactionWithContext(ctx context.Context, request *sleet.TransactionLookupRequest) (*sleet.TransactionLookupResponse, error) {
+	if request.TransactionReference != "" {
+		details, err := client.GetTransactionDetailsWithContext(ctx, &sleet.TransactionDetailsRequest{
+			TransactionReference: request.TransactionReference,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if details.ResultCode != string(ResultCodeOK) {
+			return &sleet.TransactionLookupResponse{Found: false}, nil
+		}
+		return translateTransactionLookup(request.TransactionReference, TransactionStatus(details.Status)), nil
+	}
+
+	if request.MerchantOrderReference == "" {
+		return &sleet.TransactionLookupResponse{Found: false}, nil
+	}
+	invoiceNumber := sleet.TruncateString(request.MerchantOrderReference, InvoiceNumberMaxLength)
+	for page := 1; page <= unsettledTransactionListMaxPages; page++ {
+		listRequest := buildUnsettledTransactionListRequest(client.merchantName, client.transactionKey, page)
+		authorizeNetResponse, _, err := client.sendRequest(ctx, *listRequest)
+		if err != nil {
+			return nil, err
+		}
+		if authorizeNetResponse.Messsages.ResultCode != ResultCodeOK {
+			return nil, fmt.Errorf("authorize.net unsettled transaction list failed with result code %s", authorizeNetResponse.Messsages.ResultCode)
+		}
+		for _, transaction := range authorizeNetResponse.Transactions {
+			if transaction.InvoiceNumber == invoiceNumber {
+				return translateTransactionLookup(transaction.TransID, transaction.TransactionStatus), nil
+			}
+		}
+		// a partial page is the last one, every unsettled transaction was searched
+		if len(authorizeNetResponse.Transactions) < unsettledTransactionListLimit {
+			return &sleet.TransactionLookupResponse{Found: false}, nil
+		}
+	}
+	return nil, sleet.ErrTransactionLookupIncomplete
+}
+
 func (client *AuthorizeNetClient) sendRequest(ctx context.Context, data Request) (*Response, *http.Response, error) {
 	bodyJSON, err := json.Marshal(data)
 	if err != nil {
@@ -5,6 +5,8 @@ package authorizenet
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -49,6 +51,22 @@ func TestNewClient(t *testing.T) {
 			t.Error(cmp.Diff(want, got, sleet_t.CompareUnexported))
 		}
 	})
+
+	t.Run("Base URL override", func(t *testing.T) {
+		want := &AuthorizeNetClient{
+			url:            "http://localhost:8080/xml/v1/request.api",
+			httpClient:     common.DefaultHttpClient(),
+			merchantName:   "MerchantName",
+			transactionKey: "Key",
+		}
+
+		got := NewClient("MerchantName", "Key", common.Production, common.WithBaseURL("http://localhost:8080/"))
+
+		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
+			t.Error("Client does not match expected")
+			t.Error(cmp.Diff(want, got, sleet_t.CompareUnexported))
+		}
+	})
 }
 
 func TestSend(t *testing.T) {
@@ -61,7 +79,10 @@ func TestSend(t *testing.T) {
 	authResponseRaw = helper.ReadFile("test_data/authResponse.json")
 
 	base := sleet_t.BaseAuthorizationRequest()
-	request := buildAuthRequest("MerchantName", "Key", base)
+	request, err := buildAuthRequest("MerchantName", "Key", base)
+	if err != nil {
+		t.Fatalf("Error thrown after building request %q", err)
+	}
 
 	t.Run("With Successful Response", func(t *testing.T) {
 		httpmock.Activate()
@@ -163,6 +184,56 @@ func TestAuthorize(t *testing.T) {
 			Response:             "2",
 			StatusCode:           200,
 			Header:               http.Header{"X-Test-Header": {"test_header_value"}},
+			DeclineReason:        sleet.DeclineReasonDoNotHonor,
+			RetryAdvice: &sleet.RetryAdvice{
+				DeclineType: sleet.DeclineTypeSoft,
+				Retryable:   true,
+				Source:      sleet.RetryAdviceSourceDeclineReason,
+				Code:        "DeclineReasonDoNotHonor",
+			},
+			NetworkTransactionID: "5P60JW9QQKGBWAMZ2PGRR0C",
+		}
+
+		client := NewClient("MerchantName", "Key", common.Sandbox)
+
+		got, err := client.Authorize(request)
+
+		if err != nil {
+			t.Fatalf("Error thrown after sending request %q", err)
+		}
+
+		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
+			t.Error("Response body does not match expected")
+			t.Error(cmp.Diff(*want, *got, sleet_t.CompareUnexported))
+		}
+	})
+
+	t.Run("With Held Response", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", url, func(req *http.Request) (*http.Response, error) {
+			authResponseRaw = helper.ReadFile("test_data/authHeldResponse.json")
+			resp := httpmock.NewBytesResponse(http.StatusOK, authResponseRaw)
+			resp.Header = http.Header{"X-Test-Header": {"test_header_value"}}
+			return resp, nil
+		})
+
+		// held authorizations are not approved yet and carry no decline data
+		want := &sleet.AuthorizationResponse{
+			Success:              false,
+			TransactionReference: "2149186849",
+			AvsResult:            sleet.AVSResponseMatch,
+			CvvResult:            sleet.CVVResponseMatch,
+			AvsResultRaw:         "Y",
+			CvvResultRaw:         "M",
+			Response:             "4",
+			ErrorCode:            "253",
+			Metadata:             map[string]string{sleet.AuthCodeMetadata: "HH5414"},
+			StatusCode:           200,
+			Header:               http.Header{"X-Test-Header": {"test_header_value"}},
+			ResultType:           sleet.ResultTypePending,
+			PendingReason:        sleet.PendingReasonFraudReview,
 		}
 
 		client := NewClient("MerchantName", "Key", common.Sandbox)
@@ -421,6 +492,8 @@ func TestGetTransactionDetails(t *testing.T) {
 		want := &sleet.TransactionDetailsResponse{
 			ResultCode: "Ok",
 			CardNumber: "XXXX1111",
+			Status:     "settledSuccessfully",
+			ResultType: sleet.ResultTypeSuccess,
 		}
 
 		client := NewClient("MerchantName", "Key", common.Sandbox)
@@ -510,3 +583,91 @@ func TestAlreadyCaptured(t *testing.T) {
 		}
 	})
 }
+
+func TestLookupTransaction(t *testing.T) {
+	url := "https://apitest.authorize.net/xml/v1/request.api"
+
+	// listResponder serves total unsettled transactions, numbered from the most recent, with invoice numbers
+	// "invoice-<number>"
+	listResponder := func(total int, pages *[]int) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			var request Request
+			if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+				return nil, err
+			}
+			paging := request.GetUnsettledTransactionListRequest.Paging
+			*pages = append(*pages, paging.Offset)
+			response := Response{Messsages: Messages{ResultCode: ResultCodeOK}}
+			for i := (paging.Offset - 1) * paging.Limit; i < paging.Offset*paging.Limit && i < total; i++ {
+				response.Transactions = append(response.Transactions, TransactionSummary{
+					TransID:           fmt.Sprintf("%d", i),
+					TransactionStatus: TransactionStatusAuthorizedPendingCapture,
+					InvoiceNumber:     fmt.Sprintf("invoice-%d", i),
+				})
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, response)
+		}
+	}
+
+	cases := []struct {
+		label     string
+		total     int
+		reference string
+		wantFound bool
+		wantErr   error
+		wantPages []int
+	}{
+		{
+			label:     "Found on the first page",
+			total:     3 * unsettledTransactionListLimit,
+			reference: "invoice-1",
+			wantFound: true,
+			wantPages: []int{1},
+		},
+		{
+			label:     "Found on a later page",
+			total:     3 * unsettledTransactionListLimit,
+			reference: fmt.Sprintf("invoice-%d", 2*unsettledTransactionListLimit+1),
+			wantFound: true,
+			wantPages: []int{1, 2, 3},
+		},
+		{
+			label:     "Not found after the last page",
+			total:     unsettledTransactionListLimit + 1,
+			reference: "invoice-missing",
+			wantPages: []int{1, 2},
+		},
+		{
+			label:     "Incomplete after the maximum pages",
+			total:     (unsettledTransactionListMaxPages + 1) * unsettledTransactionListLimit,
+			reference: "invoice-missing",
+			wantErr:   sleet.ErrTransactionLookupIncomplete,
+			wantPages: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			var pages []int
+			httpmock.RegisterResponder("POST", url, listResponder(c.total, &pages))
+
+			client := NewClient("MerchantName", "Key", common.Sandbox)
+			got, err := client.LookupTransactionWithContext(context.Background(), &sleet.TransactionLookupRequest{
+				MerchantOrderReference: c.reference,
+			})
+
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Got error %v, want %v", err, c.wantErr)
+			}
+			if err == nil && got.Found != c.wantFound {
+				t.Errorf("Got found %t, want %t", got.Found, c.wantFound)
+			}
+			if !cmp.Equal(pages, c.wantPages) {
+				t.Errorf("Got pages %v, want %v", pages, c.wantPages)
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@ package authorizenet
 
 import "github.com/BoltApp/sleet/common"
 
-func authorizeNetURL(env common.Environment) string {
+// requestPath is the path of the XML API, relative to the base URL
+const requestPath = "/xml/v1/request.api"
+
+func authorizeNetBaseURL(env common.Environment) string {
 	if env == common.Production {
-		return "https://api.authorize.net/xml/v1/request.api"
+		return "https://api.authorize.net"
 	}
-	return "https://apitest.authorize.net/xml/v1/request.api"
+	return "https://apitest.authorize.net"
 }
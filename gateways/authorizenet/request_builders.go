@@ -12,6 +12,7 @@ import (
 
 const (
 	InvoiceNumberMaxLength = 20
+	PONumberMaxLength      = 25
 )
 
 // Options
@@ -19,7 +20,7 @@ const (
 	customerIPOption = "CustomerIP" // Pass as a string pointer
 )
 
-func buildAuthRequest(merchantName string, transactionKey string, authRequest *sleet.AuthorizationRequest) *Request {
+func buildAuthRequest(merchantName string, transactionKey string, authRequest *sleet.AuthorizationRequest) (*Request, error) {
 	amountStr := sleet.AmountToDecimalString(&authRequest.Amount)
 	billingAddress := authRequest.BillingAddress
 
@@ -79,10 +80,10 @@ func buildAuthRequest(merchantName string, transactionKey string, authRequest *s
 			State:       billingAddress.RegionCode,
 			Zip:         billingAddress.PostalCode,
 			Country:     billingAddress.CountryCode,
-			PhoneNumber: billingAddress.PhoneNumber,
+			PhoneNumber: common.NormalizePhoneNumber(billingAddress.PhoneNumber, billingAddress.CountryCode, common.PhoneNumberFormatE164),
 		}
 		authorizeRequest.TransactionRequest.Customer = &Customer{
-			Email: common.SafeStr(billingAddress.Email),
+			Email: common.SafeStr(common.NormalizeEmail(billingAddress.Email)),
 		}
 	}
 
@@ -91,7 +92,9 @@ func buildAuthRequest(merchantName string, transactionKey string, authRequest *s
 		authorizeRequest.TransactionRequest.Order = &Order{InvoiceNumber: invoiceNumber}
 	}
 
-	authorizeRequest = *addL2L3Data(authRequest, &authorizeRequest)
+	if err := addL2L3Data(authRequest, &authorizeRequest); err != nil {
+		return nil, err
+	}
 
 	// Pass customer ip if included in options map
 	if authRequest.Options[customerIPOption] != nil {
@@ -101,7 +104,7 @@ func buildAuthRequest(merchantName string, transactionKey string, authRequest *s
 		}
 	}
 
-	return &Request{CreateTransactionRequest: &authorizeRequest}
+	return &Request{CreateTransactionRequest: &authorizeRequest}, nil
 }
 
 func buildVoidRequest(merchantName string, transactionKey string, voidRequest *sleet.VoidRequest) *Request {
@@ -128,6 +131,7 @@ func buildCaptureRequest(merchantName string, transactionKey string, captureRequ
 			},
 		},
 	}
+	addLevel2Data(&request.CreateTransactionRequest.TransactionRequest, captureRequest.Level2Data)
 	return request
 }
 
@@ -177,9 +181,12 @@ func authentication(merchantName string, transactionKey string) MerchantAuthenti
 func addL2L3Data(
 	authRequest *sleet.AuthorizationRequest,
 	authNetAuthRequest *CreateTransactionRequest,
-) *CreateTransactionRequest {
+) error {
 	if authRequest.Level3Data != nil {
-		lineItemString := buildLineItemsString(authRequest)
+		lineItemString, err := buildLineItemsString(authRequest)
+		if err != nil {
+			return err
+		}
 		if lineItemString != nil {
 			authNetAuthRequest.TransactionRequest.LineItem = json.RawMessage(*lineItemString)
 		}
@@ -205,6 +212,9 @@ func addL2L3Data(
 		}
 	}
 
+	// Level 2 data is applied after Level 3 data so that its tax amount takes precedence
+	addLevel2Data(&authNetAuthRequest.TransactionRequest, authRequest.Level2Data)
+
 	if authRequest.ShippingAddress != nil {
 		authNetAuthRequest.TransactionRequest.ShippingAddress = &ShippingAddress{
 			FirstName: authRequest.CreditCard.FirstName,
@@ -218,12 +228,27 @@ func addL2L3Data(
 		}
 	}
 
-	return authNetAuthRequest
+	return nil
+}
+
+// addLevel2Data sets the purchasing card fields. Authorize.Net has no field for the merchant tax ID or the ship-from
+// postal code.
+func addLevel2Data(transactionRequest *TransactionRequest, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	transactionRequest.Tax = &ExtendedAmount{
+		Amount: sleet.AmountToDecimalString(&level2.TaxAmount),
+	}
+	transactionRequest.TaxExempt = common.SPtr(strconv.FormatBool(level2.TaxExempt))
+	if level2.PurchaseOrderNumber != "" {
+		transactionRequest.PONumber = common.SPtr(sleet.TruncateString(level2.PurchaseOrderNumber, PONumberMaxLength))
+	}
 }
 
 // Authorize net converts json to XML before processing the request. This leads to weird scenarios like repeating json
 // fields. LineItems is one of them so we will build it as a raw string
-func buildLineItemsString(authRequest *sleet.AuthorizationRequest) *string {
+func buildLineItemsString(authRequest *sleet.AuthorizationRequest) (*string, error) {
 	hasLineItem := false
 	maxLineItemCount := 30
 	lineItems := "{"
@@ -233,13 +258,18 @@ func buildLineItemsString(authRequest *sleet.AuthorizationRequest) *string {
 			break
 		}
 
+		unitOfMeasure, err := common.LookupOptionalUnitOfMeasurementCode(authRequestLineItem.UnitOfMeasure)
+		if err != nil {
+			return nil, err
+		}
 		lineItem := &LineItem{
 			ItemId: sleet.TruncateString(authRequestLineItem.ProductCode, 31),
 			// FIXME: Name is not available in our Sleet auth inputs yet. Description is used as a substitute.
-			Name:        sleet.TruncateString(authRequestLineItem.Description, 31),
-			Description: sleet.TruncateString(authRequestLineItem.Description, 255),
-			Quantity:    strconv.FormatInt(authRequestLineItem.Quantity, 10),
-			UnitPrice:   strconv.FormatInt(authRequestLineItem.UnitPrice.Amount, 10),
+			Name:          sleet.TruncateString(authRequestLineItem.Description, 31),
+			Description:   sleet.TruncateString(authRequestLineItem.Description, 255),
+			Quantity:      strconv.FormatInt(authRequestLineItem.Quantity, 10),
+			UnitPrice:     strconv.FormatInt(authRequestLineItem.UnitPrice.Amount, 10),
+			UnitOfMeasure: unitOfMeasure,
 		}
 
 		if lineItem.ItemId == "" {
@@ -268,9 +298,9 @@ func buildLineItemsString(authRequest *sleet.AuthorizationRequest) *string {
 	lineItems += "}"
 
 	if hasLineItem {
-		return &lineItems
+		return &lineItems, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func BuildTransactionDetailsRequest(merchantName string, transactionKey string, transactionDetailsRequest *sleet.TransactionDetailsRequest) (
@@ -285,3 +315,20 @@ func BuildTransactionDetailsRequest(merchantName string, transactionKey string,
 	}
 	return request, nil
 }
+
+// unsettledTransactionListLimit is the number of unsettled transactions of each page searched for a transaction
+const unsettledTransactionListLimit = 1000
+
+// unsettledTransactionListMaxPages bounds the number of pages searched for a transaction, most recent first
+const unsettledTransactionListMaxPages = 10
+
+// buildUnsettledTransactionListRequest requests the given page, starting at 1, of the unsettled transactions
+func buildUnsettledTransactionListRequest(merchantName string, transactionKey string, page int) *Request {
+	return &Request{
+		GetUnsettledTransactionListRequest: &GetUnsettledTransactionListRequest{
+			MerchantAuthentication: authentication(merchantName, transactionKey),
+			Sorting:                &Sorting{OrderBy: "submitTimeUTC", OrderDescending: true},
+			Paging:                 &Paging{Limit: unsettledTransactionListLimit, Offset: page},
+		},
+	}
+}
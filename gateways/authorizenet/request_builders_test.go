@@ -37,6 +37,7 @@ func TestBuildAuthRequest(t *testing.T) {
 	for i := range l2l3EmptyFields.Level3Data.LineItems {
 		l2l3EmptyFields.Level3Data.LineItems[i].ProductCode = ""
 		l2l3EmptyFields.Level3Data.LineItems[i].Description = ""
+		l2l3EmptyFields.Level3Data.LineItems[i].UnitOfMeasure = ""
 	}
 
 	withCustomerIP := sleet_testing.BaseAuthorizationRequestWithEmailPhoneNumber()
@@ -77,7 +78,7 @@ func TestBuildAuthRequest(t *testing.T) {
 							State:       base.BillingAddress.RegionCode,
 							Zip:         base.BillingAddress.PostalCode,
 							Country:     base.BillingAddress.CountryCode,
-							PhoneNumber: base.BillingAddress.PhoneNumber,
+							PhoneNumber: common.SPtr("+15555555555"),
 						},
 						Order: &Order{
 							InvoiceNumber: base.MerchantOrderReference[:InvoiceNumberMaxLength],
@@ -120,7 +121,7 @@ func TestBuildAuthRequest(t *testing.T) {
 							State:       base.BillingAddress.RegionCode,
 							Zip:         base.BillingAddress.PostalCode,
 							Country:     base.BillingAddress.CountryCode,
-							PhoneNumber: base.BillingAddress.PhoneNumber,
+							PhoneNumber: common.SPtr("+15555555555"),
 						},
 						Customer: &Customer{
 							Email: *base.BillingAddress.Email,
@@ -159,7 +160,7 @@ func TestBuildAuthRequest(t *testing.T) {
 							State:       base.BillingAddress.RegionCode,
 							Zip:         base.BillingAddress.PostalCode,
 							Country:     base.BillingAddress.CountryCode,
-							PhoneNumber: base.BillingAddress.PhoneNumber,
+							PhoneNumber: common.SPtr("+15555555555"),
 						},
 						Customer: &Customer{
 							Email: *base.BillingAddress.Email,
@@ -196,7 +197,7 @@ func TestBuildAuthRequest(t *testing.T) {
 						Order: &Order{
 							InvoiceNumber: baseL2L3.MerchantOrderReference[:InvoiceNumberMaxLength],
 						},
-						LineItem: json.RawMessage(`{"lineItem":{"itemId":"abc","name":"pot","description":"pot","quantity":"2","unitPrice":"500"}}`),
+						LineItem: json.RawMessage(`{"lineItem":{"itemId":"abc","name":"pot","description":"pot","quantity":"2","unitPrice":"500","unitOfMeasure":"EA"}}`),
 						Tax: &ExtendedAmount{
 							Amount: "100",
 						},
@@ -251,7 +252,7 @@ func TestBuildAuthRequest(t *testing.T) {
 						Order: &Order{
 							InvoiceNumber: baseL2L3MultipleItems.MerchantOrderReference[:InvoiceNumberMaxLength],
 						},
-						LineItem: json.RawMessage(`{"lineItem":{"itemId":"abc","name":"pot","description":"pot","quantity":"2","unitPrice":"500"},"lineItem":{"itemId":"123","name":"vase","description":"vase","quantity":"5","unitPrice":"1000"}}`),
+						LineItem: json.RawMessage(`{"lineItem":{"itemId":"abc","name":"pot","description":"pot","quantity":"2","unitPrice":"500","unitOfMeasure":"EA"},"lineItem":{"itemId":"123","name":"vase","description":"vase","quantity":"5","unitPrice":"1000","unitOfMeasure":"EA"}}`),
 						Tax: &ExtendedAmount{
 							Amount: "100",
 						},
@@ -357,7 +358,7 @@ func TestBuildAuthRequest(t *testing.T) {
 							State:       withCustomerIP.BillingAddress.RegionCode,
 							Zip:         withCustomerIP.BillingAddress.PostalCode,
 							Country:     withCustomerIP.BillingAddress.CountryCode,
-							PhoneNumber: withCustomerIP.BillingAddress.PhoneNumber,
+							PhoneNumber: common.SPtr("+15555555555"),
 						},
 						Order: &Order{
 							InvoiceNumber: withCustomerIP.MerchantOrderReference[:InvoiceNumberMaxLength],
@@ -407,7 +408,10 @@ func TestBuildAuthRequest(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.label, func(t *testing.T) {
-			got := buildAuthRequest("MerchantName", "Key", c.in)
+			got, err := buildAuthRequest("MerchantName", "Key", c.in)
+			if err != nil {
+				t.Fatalf("Error thrown after building request %q", err)
+			}
 			if diff := deep.Equal(got, c.want); diff != nil {
 				t.Error(diff)
 			}
@@ -415,6 +419,16 @@ func TestBuildAuthRequest(t *testing.T) {
 	}
 }
 
+func TestBuildAuthRequestUnknownUnitOfMeasure(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Level3Data.LineItems[0].UnitOfMeasure = "bushel of apples"
+
+	if _, err := buildAuthRequest("MerchantName", "Key", request); err == nil {
+		t.Error("expected error for an unknown unit of measure")
+	}
+}
+
 func TestBuildCaptureRequest(t *testing.T) {
 	base := sleet_testing.BaseCaptureRequest()
 
@@ -44,3 +44,103 @@ func translateAvs(avs AVSResultCode) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// declineReasonMap translates Authorize.Net response reason codes
+// https://developer.authorize.net/api/reference/responseCodes.html
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"2":   sleet.DeclineReasonDoNotHonor,
+	"3":   sleet.DeclineReasonCallIssuer,
+	"4":   sleet.DeclineReasonPickUpCard,
+	"5":   sleet.DeclineReasonInvalidAmount,
+	"6":   sleet.DeclineReasonInvalidCardNumber,
+	"7":   sleet.DeclineReasonInvalidExpirationDate,
+	"8":   sleet.DeclineReasonExpiredCard,
+	"11":  sleet.DeclineReasonDuplicateTransaction,
+	"19":  sleet.DeclineReasonProcessingError,
+	"20":  sleet.DeclineReasonProcessingError,
+	"21":  sleet.DeclineReasonProcessingError,
+	"22":  sleet.DeclineReasonProcessingError,
+	"23":  sleet.DeclineReasonProcessingError,
+	"25":  sleet.DeclineReasonProcessingError,
+	"26":  sleet.DeclineReasonProcessingError,
+	"27":  sleet.DeclineReasonAVSFailure,
+	"28":  sleet.DeclineReasonTransactionNotPermitted,
+	"37":  sleet.DeclineReasonInvalidCardNumber,
+	"44":  sleet.DeclineReasonCVVFailure,
+	"45":  sleet.DeclineReasonCVVFailure,
+	"57":  sleet.DeclineReasonProcessingError,
+	"58":  sleet.DeclineReasonProcessingError,
+	"59":  sleet.DeclineReasonProcessingError,
+	"60":  sleet.DeclineReasonProcessingError,
+	"61":  sleet.DeclineReasonProcessingError,
+	"62":  sleet.DeclineReasonProcessingError,
+	"63":  sleet.DeclineReasonProcessingError,
+	"65":  sleet.DeclineReasonCVVFailure,
+	"78":  sleet.DeclineReasonCVVFailure,
+	"120": sleet.DeclineReasonIssuerUnavailable,
+	"121": sleet.DeclineReasonProcessingError,
+	"122": sleet.DeclineReasonProcessingError,
+	"127": sleet.DeclineReasonAVSFailure,
+	"141": sleet.DeclineReasonSuspectedFraud,
+	"165": sleet.DeclineReasonCVVFailure,
+	"250": sleet.DeclineReasonSuspectedFraud,
+	"251": sleet.DeclineReasonSuspectedFraud,
+	"254": sleet.DeclineReasonSuspectedFraud,
+	"315": sleet.DeclineReasonInvalidCardNumber,
+	"316": sleet.DeclineReasonInvalidExpirationDate,
+	"317": sleet.DeclineReasonExpiredCard,
+}
+
+// translateDeclineReason converts the reason code of the first error of a declined transaction to its equivalent
+// Sleet decline reason.
+func translateDeclineReason(txnResponse TransactionResponse) sleet.DeclineReason {
+	if len(txnResponse.Errors) == 0 {
+		return sleet.DeclineReasonUnknown
+	}
+	reason, ok := declineReasonMap[txnResponse.Errors[0].ErrorCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
+
+var transactionStatusMap = map[TransactionStatus]sleet.ResultType{
+	TransactionStatusAuthorizedPendingCapture:   sleet.ResultTypeSuccess,
+	TransactionStatusCapturedPendingSettlement:  sleet.ResultTypeSuccess,
+	TransactionStatusSettledSuccessfully:        sleet.ResultTypeSuccess,
+	TransactionStatusApprovedReview:             sleet.ResultTypeSuccess,
+	TransactionStatusFDSPendingReview:           sleet.ResultTypePending,
+	TransactionStatusFDSAuthorizedPendingReview: sleet.ResultTypePending,
+	TransactionStatusUnderReview:                sleet.ResultTypePending,
+	TransactionStatusDeclined:                   sleet.ResultTypePaymentError,
+	TransactionStatusFailedReview:               sleet.ResultTypePaymentError,
+	TransactionStatusVoided:                     sleet.ResultTypePaymentError,
+	TransactionStatusExpired:                    sleet.ResultTypePaymentError,
+	TransactionStatusGeneralError:               sleet.ResultTypePaymentError,
+}
+
+// translateTransactionStatus converts a transaction status to a Sleet result type. Transactions pending on
+// Authorize.Net are held by the Fraud Detection Suite for review.
+func translateTransactionStatus(status TransactionStatus) (sleet.ResultType, sleet.PendingReason) {
+	resultType, ok := transactionStatusMap[status]
+	if !ok {
+		return sleet.ResultTypeUnknownError, sleet.PendingReasonNone
+	}
+	if resultType == sleet.ResultTypePending {
+		return resultType, sleet.PendingReasonFraudReview
+	}
+	return resultType, sleet.PendingReasonNone
+}
+
+// translateTransactionLookup converts the status of a transaction found by a lookup
+func translateTransactionLookup(transID string, status TransactionStatus) *sleet.TransactionLookupResponse {
+	resultType, pendingReason := translateTransactionStatus(status)
+	return &sleet.TransactionLookupResponse{
+		Found:                true,
+		TransactionReference: transID,
+		ResultType:           resultType,
+		PendingReason:        pendingReason,
+		Captured:             status == TransactionStatusCapturedPendingSettlement || status == TransactionStatusSettledSuccessfully,
+		Status:               string(status),
+	}
+}
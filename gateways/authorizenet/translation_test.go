@@ -4,6 +4,7 @@
 package authorizenet
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/BoltApp/sleet"
@@ -62,3 +63,49 @@ func TestTranslateAvs(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateDeclineReason(t *testing.T) {
+	cases := []struct {
+		in   []Error
+		want sleet.DeclineReason
+	}{
+		{[]Error{{ErrorCode: "2"}}, sleet.DeclineReasonDoNotHonor},
+		{[]Error{{ErrorCode: "8"}}, sleet.DeclineReasonExpiredCard},
+		{[]Error{{ErrorCode: "27"}}, sleet.DeclineReasonAVSFailure},
+		{[]Error{{ErrorCode: "44"}}, sleet.DeclineReasonCVVFailure},
+		{[]Error{{ErrorCode: "9999"}}, sleet.DeclineReasonUnknown},
+		{nil, sleet.DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v", c.in), func(t *testing.T) {
+			got := translateDeclineReason(TransactionResponse{Errors: c.in})
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateTransactionStatus(t *testing.T) {
+	cases := []struct {
+		in         TransactionStatus
+		wantResult sleet.ResultType
+		wantReason sleet.PendingReason
+	}{
+		{TransactionStatusAuthorizedPendingCapture, sleet.ResultTypeSuccess, sleet.PendingReasonNone},
+		{TransactionStatusFDSPendingReview, sleet.ResultTypePending, sleet.PendingReasonFraudReview},
+		{TransactionStatusFDSAuthorizedPendingReview, sleet.ResultTypePending, sleet.PendingReasonFraudReview},
+		{TransactionStatusFailedReview, sleet.ResultTypePaymentError, sleet.PendingReasonNone},
+		{"unknown", sleet.ResultTypeUnknownError, sleet.PendingReasonNone},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			result, reason := translateTransactionStatus(c.in)
+			if result != c.wantResult || reason != c.wantReason {
+				t.Errorf("Got %q %q, want %q %q", result, reason, c.wantResult, c.wantReason)
+			}
+		})
+	}
+}
@@ -26,6 +26,24 @@ const (
 	MessageResponseCodeAlreadyCaptured = "311"
 )
 
+// TransactionStatus status of a transaction as returned by getTransactionDetails
+type TransactionStatus string
+
+const (
+	TransactionStatusAuthorizedPendingCapture   TransactionStatus = "authorizedPendingCapture"
+	TransactionStatusCapturedPendingSettlement  TransactionStatus = "capturedPendingSettlement"
+	TransactionStatusSettledSuccessfully        TransactionStatus = "settledSuccessfully"
+	TransactionStatusApprovedReview             TransactionStatus = "approvedReview"
+	TransactionStatusFDSPendingReview           TransactionStatus = "FDSPendingReview"
+	TransactionStatusFDSAuthorizedPendingReview TransactionStatus = "FDSAuthorizedPendingReview"
+	TransactionStatusUnderReview                TransactionStatus = "underReview"
+	TransactionStatusDeclined                   TransactionStatus = "declined"
+	TransactionStatusFailedReview               TransactionStatus = "failedReview"
+	TransactionStatusVoided                     TransactionStatus = "voided"
+	TransactionStatusExpired                    TransactionStatus = "expired"
+	TransactionStatusGeneralError               TransactionStatus = "generalError"
+)
+
 const (
 	GooglePayPaymentDescriptor = "COMMON.GOOGLE.INAPP.PAYMENT"
 )
@@ -92,8 +110,9 @@ const expirationDateXXXX = "XXXX"
 
 // Request contains a createTransactionRequest for authorizations
 type Request struct {
-	CreateTransactionRequest     *CreateTransactionRequest     `json:"createTransactionRequest,omitempty"`
-	GetTransactionDetailsRequest *GetTransactionDetailsRequest `json:"getTransactionDetailsRequest,omitempty"`
+	CreateTransactionRequest           *CreateTransactionRequest           `json:"createTransactionRequest,omitempty"`
+	GetTransactionDetailsRequest       *GetTransactionDetailsRequest       `json:"getTransactionDetailsRequest,omitempty"`
+	GetUnsettledTransactionListRequest *GetUnsettledTransactionListRequest `json:"getUnsettledTransactionListRequest,omitempty"`
 }
 
 // GetTransactionDetailsRequest contains a transaction ID for fetching transaction details
@@ -102,6 +121,25 @@ type GetTransactionDetailsRequest struct {
 	TransID                string                 `json:"transId,omitempty"`
 }
 
+// GetUnsettledTransactionListRequest lists the transactions that have not been settled yet
+type GetUnsettledTransactionListRequest struct {
+	MerchantAuthentication MerchantAuthentication `json:"merchantAuthentication"`
+	Sorting                *Sorting               `json:"sorting,omitempty"`
+	Paging                 *Paging                `json:"paging,omitempty"`
+}
+
+// Sorting specifies the order of transaction lists
+type Sorting struct {
+	OrderBy         string `json:"orderBy"`
+	OrderDescending bool   `json:"orderDescending"`
+}
+
+// Paging specifies the page of transaction lists, offsets start at 1
+type Paging struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
 // CreateTransactionRequest specifies the merchant authentication to be used for request as well as transaction
 // details specified in transactionRequest
 type CreateTransactionRequest struct {
@@ -135,6 +173,8 @@ type TransactionRequest struct {
 	Tax             *ExtendedAmount  `json:"tax,omitempty"`
 	Duty            *ExtendedAmount  `json:"duty,omitempty"`
 	Shipping        *ExtendedAmount  `json:"shipping,omitempty"`
+	TaxExempt       *string          `json:"taxExempt,omitempty"` // boolean as string
+	PONumber        *string          `json:"poNumber,omitempty"`  // max length = 25
 	Customer        *Customer        `json:"customer,omitempty"`
 	BillingAddress  *BillingAddress  `json:"billTo,omitempty"`
 	ShippingAddress *ShippingAddress `json:"shipTo,omitempty"`
@@ -142,12 +182,13 @@ type TransactionRequest struct {
 }
 
 type LineItem struct {
-	ItemId      string `json:"itemId,omitempty"`      // min length = 1, max length = 31
-	Name        string `json:"name,omitempty"`        // min length = 1, max length = 31
-	Description string `json:"description,omitempty"` // max length = 255
-	Quantity    string `json:"quantity,omitempty"`    // decimal as string, max decimal precision = 4
-	UnitPrice   string `json:"unitPrice,omitempty"`   // decimal as string, max decimal precision = 4
-	Taxable     string `json:"taxable,omitempty"`     // boolean as string
+	ItemId        string `json:"itemId,omitempty"`        // min length = 1, max length = 31
+	Name          string `json:"name,omitempty"`          // min length = 1, max length = 31
+	Description   string `json:"description,omitempty"`   // max length = 255
+	Quantity      string `json:"quantity,omitempty"`      // decimal as string, max decimal precision = 4
+	UnitPrice     string `json:"unitPrice,omitempty"`     // decimal as string, max decimal precision = 4
+	Taxable       string `json:"taxable,omitempty"`       // boolean as string
+	UnitOfMeasure string `json:"unitOfMeasure,omitempty"` // max length = 12
 }
 
 type ExtendedAmount struct {
@@ -217,16 +258,25 @@ type Order struct {
 
 // Response is a generic Auth.net response
 type Response struct {
-	TransactionResponse TransactionResponse `json:"transactionResponse"`
-	Transaction         *Transaction        `json:"transaction,omitempty"`
-	RefID               string              `json:"refId"`
-	Messsages           Messages            `json:"messages"`
+	TransactionResponse TransactionResponse  `json:"transactionResponse"`
+	Transaction         *Transaction         `json:"transaction,omitempty"`
+	Transactions        []TransactionSummary `json:"transactions,omitempty"`
+	RefID               string               `json:"refId"`
+	Messsages           Messages             `json:"messages"`
 }
 
 // Transaction describes the transaction details
 type Transaction struct {
-	TransID string   `json:"transId,omitempty"`
-	Payment *Payment `json:"payment,omitempty"`
+	TransID           string            `json:"transId,omitempty"`
+	TransactionStatus TransactionStatus `json:"transactionStatus,omitempty"`
+	Payment           *Payment          `json:"payment,omitempty"`
+}
+
+// TransactionSummary describes a transaction of a transaction list
+type TransactionSummary struct {
+	TransID           string            `json:"transId"`
+	TransactionStatus TransactionStatus `json:"transactionStatus"`
+	InvoiceNumber     string            `json:"invoiceNumber"`
 }
 
 // TransactionResponse contains the information from issuer about AVS, CVV and whether or not authorization was successful
@@ -237,6 +287,7 @@ type TransactionResponse struct {
 	CVVResultCode  CVVResultCode                `json:"cvvResultCode"`
 	CAVVResultCode CAVVResultCode               `json:"cavvResultCode"`
 	TransID        string                       `json:"transId"`
+	NetworkTransID string                       `json:"networkTransId"`
 	RefTransID     string                       `json:"refTransID"`
 	TransHash      string                       `json:"transHash"`
 	AccountNumber  string                       `json:"accountNumber"`
@@ -49,21 +49,27 @@ type BraintreeClient struct {
 	privateKey  string
 	environment braintree_go.Environment
 	httpClient  *http.Client
+	btClient    *braintree_go.Braintree
 }
 
 // NewClient creates a Braintree client with creds and default http client
-func NewClient(merchantID string, publicKey string, privateKey string, environment common.Environment) *BraintreeClient {
-	return NewWithHttpClient(merchantID, publicKey, privateKey, environment, defaultClient)
+func NewClient(merchantID string, publicKey string, privateKey string, environment common.Environment, options ...common.ClientOption) *BraintreeClient {
+	return NewWithHttpClient(merchantID, publicKey, privateKey, environment, defaultClient, options...)
 }
 
 // NewWithHttpClient creates a Braintree client with creds and user specified http client for custom behavior
-func NewWithHttpClient(merchantID string, publicKey string, privateKey string, environment common.Environment, httpClient *http.Client) *BraintreeClient {
+func NewWithHttpClient(merchantID string, publicKey string, privateKey string, environment common.Environment, httpClient *http.Client, options ...common.ClientOption) *BraintreeClient {
+	btEnvironment := braintreeEnvironment(environment)
+	if baseURL := common.NewClientOptions(options...).BaseURL; baseURL != "" {
+		btEnvironment = braintree_go.NewEnvironment(baseURL)
+	}
 	return &BraintreeClient{
 		merchantID:  merchantID,
 		publicKey:   publicKey,
 		privateKey:  privateKey,
-		environment: braintreeEnvironment(environment),
+		environment: btEnvironment,
 		httpClient:  httpClient,
+		btClient:    braintree_go.NewWithHttpClient(btEnvironment, merchantID, publicKey, privateKey, httpClient),
 	}
 }
 
@@ -74,29 +80,45 @@ func (client *BraintreeClient) Authorize(request *sleet.AuthorizationRequest) (*
 
 // AuthorizeWithContext authorizes a transaction. This transaction must be captured to receive funds
 func (client *BraintreeClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	request, err := sleet.PrepareLevel3Data(request)
+	if err != nil {
+		return nil, err
+	}
 	authRequest, err := buildAuthRequest(request)
 	if err != nil {
 		return nil, err
 	}
-	btClient := braintree_go.NewWithHttpClient(client.environment, client.merchantID, client.publicKey, client.privateKey, client.httpClient)
-	auth, err := btClient.Transaction().Create(ctx, authRequest)
+	auth, err := client.btClient.Transaction().Create(ctx, authRequest)
 	if err != nil {
-		var statusCode int
+		response := &sleet.AuthorizationResponse{Success: false}
 		if respErr, ok := err.(*braintree_go.BraintreeError); ok && respErr != nil {
-			statusCode = respErr.StatusCode()
+			response.StatusCode = respErr.StatusCode()
+			// declined transactions are returned as errors carrying the transaction
+			if respErr.Transaction != nil {
+				response.DeclineReason = translateDeclineReason(respErr.Transaction)
+			}
 		}
-		return &sleet.AuthorizationResponse{Success: false, StatusCode: statusCode}, err
+		return response, err
 	}
 
-	avsResult := fmt.Sprintf("%s:%s:%s", auth.AVSErrorResponseCode, auth.AVSStreetAddressResponseCode, auth.AVSStreetAddressResponseCode)
+	success := auth.Status == braintree_go.TransactionStatusAuthorized
+	declineReason := sleet.DeclineReasonNone
+	if !success {
+		declineReason = translateDeclineReason(auth)
+	}
+	avsResult := fmt.Sprintf("%s:%s:%s", auth.AVSErrorResponseCode, auth.AVSPostalCodeResponseCode, auth.AVSStreetAddressResponseCode)
 	return &sleet.AuthorizationResponse{
-		Success:              auth.Status == braintree_go.TransactionStatusAuthorized,
+		Success:              success,
 		TransactionReference: auth.Id,
 		Response:             auth.ProcessorAuthorizationCode,
-		AvsResult:            sleet.AVSresponseZipMatchAddressMatch, // TODO: Add translator
-		CvvResult:            sleet.CVVResponseMatch,                // TODO: Add translator
+		AvsResult:            translateAvs(auth.AVSErrorResponseCode, auth.AVSPostalCodeResponseCode, auth.AVSStreetAddressResponseCode),
+		CvvResult:            translateCvv(auth.CVVResponseCode),
 		AvsResultRaw:         avsResult,
 		CvvResultRaw:         string(auth.CVVResponseCode),
+		DeclineReason:        declineReason,
 	}, nil
 }
 
@@ -111,8 +133,7 @@ func (client *BraintreeClient) CaptureWithContext(ctx context.Context, request *
 	if err != nil {
 		return nil, err
 	}
-	btClient := braintree_go.NewWithHttpClient(client.environment, client.merchantID, client.publicKey, client.privateKey, client.httpClient)
-	capture, err := btClient.Transaction().SubmitForSettlement(ctx, request.TransactionReference, amount)
+	capture, err := client.btClient.Transaction().SubmitForSettlement(ctx, request.TransactionReference, amount)
 	if err != nil {
 		return &sleet.CaptureResponse{Success: false, TransactionReference: ""}, err
 	}
@@ -129,8 +150,10 @@ func (client *BraintreeClient) Void(request *sleet.VoidRequest) (*sleet.VoidResp
 
 // VoidWithContext voids an authorized transaction with reference (cancels void)
 func (client *BraintreeClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
-	btClient := braintree_go.NewWithHttpClient(client.environment, client.merchantID, client.publicKey, client.privateKey, client.httpClient)
-	void, err := btClient.Transaction().Void(ctx, request.TransactionReference)
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
+	void, err := client.btClient.Transaction().Void(ctx, request.TransactionReference)
 	if err != nil {
 		return &sleet.VoidResponse{
 			Success: false,
@@ -149,12 +172,14 @@ func (client *BraintreeClient) Refund(request *sleet.RefundRequest) (*sleet.Refu
 
 // RefundWithContext captures a captured transaction with reference and specified amount
 func (client *BraintreeClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	amount, err := convertToBraintreeDecimal(request.Amount.Amount, request.Amount.Currency)
 	if err != nil {
 		return nil, err
 	}
-	btClient := braintree_go.NewWithHttpClient(client.environment, client.merchantID, client.publicKey, client.privateKey, client.httpClient)
-	refund, err := btClient.Transaction().Refund(ctx, request.TransactionReference, amount)
+	refund, err := client.btClient.Transaction().Refund(ctx, request.TransactionReference, amount)
 	if err != nil {
 		return &sleet.RefundResponse{
 			Success: false,
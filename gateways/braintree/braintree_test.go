@@ -0,0 +1,96 @@
+package braintree
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// authorizedTransport answers every request with an authorized transaction
+type authorizedTransport struct{}
+
+func (authorizedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`<transaction><id>7hbnt5n4</id><status>authorized</status></transaction>`)),
+		Request:    req,
+	}, nil
+}
+
+// BenchmarkAuthorize compares a client shared by all authorizations with a client built for each one, which pays
+// for building the Braintree client on every request
+func BenchmarkAuthorize(b *testing.B) {
+	httpClient := &http.Client{Transport: authorizedTransport{}}
+	request := sleet_testing.BaseAuthorizationRequest()
+	newClient := func() *BraintreeClient {
+		return NewWithHttpClient("merchant", "public", "private", common.Sandbox, httpClient)
+	}
+
+	b.Run("SharedClient", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SharedClientParallel", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := client.Authorize(request); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	b.Run("ClientPerRequest", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := newClient().Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient(
+		"merchant",
+		"public",
+		"private",
+		common.Production,
+		&http.Client{Transport: transport},
+		common.WithBaseURL("http://localhost:3000"),
+	)
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:3000/merchants/merchant/transactions"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
@@ -2,6 +2,7 @@ package braintree
 
 import (
 	"fmt"
+	"strconv"
 
 	braintree_go "github.com/BoltApp/braintree-go"
 
@@ -9,6 +10,16 @@ import (
 	"github.com/BoltApp/sleet/common"
 )
 
+// Field limits from https://developer.paypal.com/braintree/docs/reference/request/transaction/sale
+const (
+	maxLineItemCount             = 249
+	maxLineItemNameLength        = 35
+	maxLineItemDescriptionLength = 127
+	maxProductCodeLength         = 12
+	maxCommodityCodeLength       = 12
+	maxPurchaseOrderNumberLength = 17
+)
+
 func buildAuthRequest(authRequest *sleet.AuthorizationRequest) (*braintree_go.TransactionRequest, error) {
 	billingAddress := authRequest.BillingAddress
 	card := authRequest.CreditCard
@@ -40,9 +51,72 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest) (*braintree_go.Tr
 			CountryCodeAlpha2: common.SafeStr(billingAddress.CountryCode),
 		}
 	}
+
+	if authRequest.Level3Data != nil {
+		if err := addLevel3Data(request, authRequest.Level3Data, authRequest.Amount.Currency); err != nil {
+			return nil, err
+		}
+	}
 	return request, nil
 }
 
+// addLevel3Data sets the Level 2 and Level 3 fields supported by Braintree. Line item amounts use the currency of
+// the authorization.
+func addLevel3Data(request *braintree_go.TransactionRequest, level3 *sleet.Level3Data, currencyCode string) error {
+	taxAmount, err := convertToBraintreeDecimal(level3.TaxAmount.Amount, currencyCode)
+	if err != nil {
+		return err
+	}
+	request.TaxAmount = taxAmount
+	request.PurchaseOrderNumber = sleet.TruncateString(level3.CustomerReference, maxPurchaseOrderNumberLength)
+
+	if len(level3.LineItems) > maxLineItemCount {
+		return fmt.Errorf("braintree accepts at most %d line items, got %d", maxLineItemCount, len(level3.LineItems))
+	}
+	for i, lineItem := range level3.LineItems {
+		unitAmount, err := convertToBraintreeDecimal(lineItem.UnitPrice.Amount, currencyCode)
+		if err != nil {
+			return err
+		}
+		totalAmount, err := convertToBraintreeDecimal(lineItem.TotalAmount.Amount, currencyCode)
+		if err != nil {
+			return err
+		}
+		itemTaxAmount, err := convertToBraintreeDecimal(lineItem.ItemTaxAmount.Amount, currencyCode)
+		if err != nil {
+			return err
+		}
+		itemDiscountAmount, err := convertToBraintreeDecimal(lineItem.ItemDiscountAmount.Amount, currencyCode)
+		if err != nil {
+			return err
+		}
+		unitOfMeasure, err := common.LookupOptionalUnitOfMeasurementCode(lineItem.UnitOfMeasure)
+		if err != nil {
+			return err
+		}
+
+		// Name is required by Braintree but is not part of our line items, so Description is used as a substitute
+		name := sleet.DefaultIfEmpty(lineItem.Description, lineItem.ProductCode)
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		request.LineItems = append(request.LineItems, &braintree_go.TransactionLineItemRequest{
+			Name:           sleet.TruncateString(name, maxLineItemNameLength),
+			Description:    sleet.TruncateString(lineItem.Description, maxLineItemDescriptionLength),
+			Kind:           braintree_go.TransactionLineItemKindDebit,
+			Quantity:       braintree_go.NewDecimal(lineItem.Quantity, 0),
+			UnitAmount:     unitAmount,
+			TotalAmount:    totalAmount,
+			TaxAmount:      itemTaxAmount,
+			DiscountAmount: itemDiscountAmount,
+			UnitOfMeasure:  unitOfMeasure,
+			ProductCode:    sleet.TruncateString(lineItem.ProductCode, maxProductCodeLength),
+			CommodityCode:  sleet.TruncateString(lineItem.CommodityCode, maxCommodityCodeLength),
+		})
+	}
+	return nil
+}
+
 func convertToBraintreeDecimal(amount int64, currencyCode string) (*braintree_go.Decimal, error) {
 	code, err := common.GetCode(currencyCode)
 	if err != nil {
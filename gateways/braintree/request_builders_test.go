@@ -0,0 +1,79 @@
+package braintree
+
+import (
+	"testing"
+
+	braintree_go "github.com/BoltApp/braintree-go"
+	"github.com/go-test/deep"
+
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+func TestBuildAuthRequestLevel3(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Level3Data.LineItems[0].UnitOfMeasure = "kg"
+
+	got, err := buildAuthRequest(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if diff := deep.Equal(got.TaxAmount, braintree_go.NewDecimal(100, 2)); diff != nil {
+		t.Error(diff)
+	}
+	if got.PurchaseOrderNumber != "customer" {
+		t.Errorf("got purchase order number %s, want customer", got.PurchaseOrderNumber)
+	}
+	want := braintree_go.TransactionLineItemRequests{
+		{
+			Name:           "pot",
+			Description:    "pot",
+			Kind:           braintree_go.TransactionLineItemKindDebit,
+			Quantity:       braintree_go.NewDecimal(2, 0),
+			UnitAmount:     braintree_go.NewDecimal(500, 2),
+			TotalAmount:    braintree_go.NewDecimal(1000, 2),
+			TaxAmount:      braintree_go.NewDecimal(0, 2),
+			DiscountAmount: braintree_go.NewDecimal(0, 2),
+			UnitOfMeasure:  "KG",
+			ProductCode:    "abc",
+			CommodityCode:  "cmd",
+		},
+	}
+	if diff := deep.Equal(got.LineItems, want); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBuildAuthRequestWithoutLevel3(t *testing.T) {
+	got, err := buildAuthRequest(sleet_testing.BaseAuthorizationRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.TaxAmount != nil || got.LineItems != nil {
+		t.Errorf("expected no Level 3 data, got %v", got)
+	}
+}
+
+func TestBuildAuthRequestUnknownUnitOfMeasure(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Level3Data.LineItems[0].UnitOfMeasure = "bushel of apples"
+
+	if _, err := buildAuthRequest(request); err == nil {
+		t.Error("expected error for an unknown unit of measure")
+	}
+}
+
+func TestBuildAuthRequestTooManyLineItems(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	lineItem := request.Level3Data.LineItems[0]
+	for len(request.Level3Data.LineItems) <= maxLineItemCount {
+		request.Level3Data.LineItems = append(request.Level3Data.LineItems, lineItem)
+	}
+
+	if _, err := buildAuthRequest(request); err == nil {
+		t.Errorf("expected error for more than %d line items", maxLineItemCount)
+	}
+}
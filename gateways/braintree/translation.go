@@ -0,0 +1,136 @@
+package braintree
+
+import (
+	braintree_go "github.com/BoltApp/braintree-go"
+
+	"github.com/BoltApp/sleet"
+)
+
+var cvvMap = map[braintree_go.CVVResponseCode]sleet.CVVResponse{
+	braintree_go.CVVResponseCodeMatches:                  sleet.CVVResponseMatch,
+	braintree_go.CVVResponseCodeDoesNotMatch:             sleet.CVVResponseNoMatch,
+	braintree_go.CVVResponseCodeNotVerified:              sleet.CVVResponseNotProcessed,
+	braintree_go.CVVResponseCodeNotProvided:              sleet.CVVResponseSkipped,
+	braintree_go.CVVResponseCodeIssuerDoesNotParticipate: sleet.CVVResponseUnsupported,
+	braintree_go.CVVResponseCodeNotApplicable:            sleet.CVVResponseSkipped,
+}
+
+// translateCvv converts a Braintree CVV response code to its equivalent Sleet standard code.
+func translateCvv(code braintree_go.CVVResponseCode) sleet.CVVResponse {
+	sleetCode, ok := cvvMap[code]
+	if !ok {
+		return sleet.CVVResponseUnknown
+	}
+	return sleetCode
+}
+
+// avsErrorMap covers the AVS error codes, which take precedence over the postal code and street address codes
+var avsErrorMap = map[braintree_go.AVSResponseCode]sleet.AVSResponse{
+	braintree_go.AVSResponseCodeSystemError:   sleet.AVSResponseError,
+	braintree_go.AVSResponseCodeNotSupported:  sleet.AVSResponseUnsupported,
+	braintree_go.AVSResponseCodeNotApplicable: sleet.AVSResponseSkipped,
+}
+
+// avsCodes is the pair of postal code and street address codes Braintree returns for a transaction
+type avsCodes struct {
+	postalCode braintree_go.AVSResponseCode
+	street     braintree_go.AVSResponseCode
+}
+
+// Braintree does not report whether a 5 or 9 digit postal code was matched; partial matches map to the 5-digit codes.
+var avsMap = map[avsCodes]sleet.AVSResponse{
+	{braintree_go.AVSResponseCodeMatches, braintree_go.AVSResponseCodeMatches}:             sleet.AVSResponseMatch,
+	{braintree_go.AVSResponseCodeMatches, braintree_go.AVSResponseCodeDoesNotMatch}:        sleet.AVSResponseZip5MatchAddressNoMatch,
+	{braintree_go.AVSResponseCodeMatches, braintree_go.AVSResponseCodeNotVerified}:         sleet.AVSResponseZipMatchAddressUnverified,
+	{braintree_go.AVSResponseCodeMatches, braintree_go.AVSResponseCodeNotProvided}:         sleet.AVSResponseZipMatchAddressUnverified,
+	{braintree_go.AVSResponseCodeDoesNotMatch, braintree_go.AVSResponseCodeMatches}:        sleet.AVSResponseZipNoMatchAddressMatch,
+	{braintree_go.AVSResponseCodeDoesNotMatch, braintree_go.AVSResponseCodeDoesNotMatch}:   sleet.AVSResponseNoMatch,
+	{braintree_go.AVSResponseCodeDoesNotMatch, braintree_go.AVSResponseCodeNotVerified}:    sleet.AVSResponseZipNoMatchAddressUnverified,
+	{braintree_go.AVSResponseCodeDoesNotMatch, braintree_go.AVSResponseCodeNotProvided}:    sleet.AVSResponseZipNoMatchAddressUnverified,
+	{braintree_go.AVSResponseCodeNotVerified, braintree_go.AVSResponseCodeMatches}:         sleet.AVSResponseZipUnverifiedAddressMatch,
+	{braintree_go.AVSResponseCodeNotProvided, braintree_go.AVSResponseCodeMatches}:         sleet.AVSResponseZipUnverifiedAddressMatch,
+	{braintree_go.AVSResponseCodeNotVerified, braintree_go.AVSResponseCodeDoesNotMatch}:    sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{braintree_go.AVSResponseCodeNotProvided, braintree_go.AVSResponseCodeDoesNotMatch}:    sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{braintree_go.AVSResponseCodeNotVerified, braintree_go.AVSResponseCodeNotVerified}:     sleet.AVSResponseSkipped,
+	{braintree_go.AVSResponseCodeNotProvided, braintree_go.AVSResponseCodeNotProvided}:     sleet.AVSResponseSkipped,
+	{braintree_go.AVSResponseCodeNotVerified, braintree_go.AVSResponseCodeNotProvided}:     sleet.AVSResponseSkipped,
+	{braintree_go.AVSResponseCodeNotProvided, braintree_go.AVSResponseCodeNotVerified}:     sleet.AVSResponseSkipped,
+	{braintree_go.AVSResponseCodeNotSupported, braintree_go.AVSResponseCodeNotSupported}:   sleet.AVSResponseUnsupported,
+	{braintree_go.AVSResponseCodeSystemError, braintree_go.AVSResponseCodeSystemError}:     sleet.AVSResponseError,
+	{braintree_go.AVSResponseCodeNotApplicable, braintree_go.AVSResponseCodeNotApplicable}: sleet.AVSResponseSkipped,
+}
+
+// translateAvs converts the Braintree AVS error, postal code and street address response codes to the equivalent
+// Sleet standard code.
+func translateAvs(errorCode, postalCode, street braintree_go.AVSResponseCode) sleet.AVSResponse {
+	if sleetCode, ok := avsErrorMap[errorCode]; ok {
+		return sleetCode
+	}
+	sleetCode, ok := avsMap[avsCodes{postalCode: postalCode, street: street}]
+	if !ok {
+		return sleet.AVSResponseUnknown
+	}
+	return sleetCode
+}
+
+// Codes taken from: https://developer.paypal.com/braintree/articles/control-panel/transactions/declines
+var processorDeclineReasonMap = map[braintree_go.ProcessorResponseCode]sleet.DeclineReason{
+	2000: sleet.DeclineReasonDoNotHonor,
+	2001: sleet.DeclineReasonInsufficientFunds,
+	2002: sleet.DeclineReasonLimitExceeded,
+	2003: sleet.DeclineReasonLimitExceeded,
+	2004: sleet.DeclineReasonExpiredCard,
+	2005: sleet.DeclineReasonInvalidCardNumber,
+	2006: sleet.DeclineReasonInvalidExpirationDate,
+	2007: sleet.DeclineReasonInvalidCardNumber,
+	2008: sleet.DeclineReasonInvalidCardNumber,
+	2009: sleet.DeclineReasonInvalidCardNumber,
+	2010: sleet.DeclineReasonCVVFailure,
+	2011: sleet.DeclineReasonCallIssuer,
+	2012: sleet.DeclineReasonLostOrStolenCard,
+	2013: sleet.DeclineReasonLostOrStolenCard,
+	2014: sleet.DeclineReasonSuspectedFraud,
+	2015: sleet.DeclineReasonTransactionNotPermitted,
+	2016: sleet.DeclineReasonDuplicateTransaction,
+	2017: sleet.DeclineReasonTransactionNotPermitted,
+	2018: sleet.DeclineReasonTransactionNotPermitted,
+	2019: sleet.DeclineReasonTransactionNotPermitted,
+	2020: sleet.DeclineReasonRestrictedCard,
+	2021: sleet.DeclineReasonSuspectedFraud,
+	2023: sleet.DeclineReasonTransactionNotPermitted,
+	2024: sleet.DeclineReasonTransactionNotPermitted,
+	2025: sleet.DeclineReasonProcessingError,
+	2026: sleet.DeclineReasonProcessingError,
+	2038: sleet.DeclineReasonDoNotHonor,
+	2046: sleet.DeclineReasonDoNotHonor,
+	2047: sleet.DeclineReasonPickUpCard,
+	2053: sleet.DeclineReasonLostOrStolenCard,
+	2057: sleet.DeclineReasonRestrictedCard,
+	2059: sleet.DeclineReasonAVSFailure,
+	2060: sleet.DeclineReasonAVSFailure,
+	2099: sleet.DeclineReasonAuthenticationRequired,
+	3000: sleet.DeclineReasonIssuerUnavailable,
+}
+
+var gatewayRejectionDeclineReasonMap = map[braintree_go.GatewayRejectionReason]sleet.DeclineReason{
+	braintree_go.GatewayRejectionReasonApplicationIncomplete: sleet.DeclineReasonProcessingError,
+	braintree_go.GatewayRejectionReasonAVS:                   sleet.DeclineReasonAVSFailure,
+	braintree_go.GatewayRejectionReasonAVSAndCVV:             sleet.DeclineReasonAVSFailure,
+	braintree_go.GatewayRejectionReasonCVV:                   sleet.DeclineReasonCVVFailure,
+	braintree_go.GatewayRejectionReasonDuplicate:             sleet.DeclineReasonDuplicateTransaction,
+	braintree_go.GatewayRejectionReasonFraud:                 sleet.DeclineReasonSuspectedFraud,
+	braintree_go.GatewayRejectionReasonThreeDSecure:          sleet.DeclineReasonAuthenticationRequired,
+}
+
+// translateDeclineReason converts the gateway rejection reason or, failing that, the processor response code of a
+// declined Braintree transaction to its equivalent Sleet decline reason.
+func translateDeclineReason(transaction *braintree_go.Transaction) sleet.DeclineReason {
+	if reason, ok := gatewayRejectionDeclineReasonMap[transaction.GatewayRejectionReason]; ok {
+		return reason
+	}
+	reason, ok := processorDeclineReasonMap[transaction.ProcessorResponseCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
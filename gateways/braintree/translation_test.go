@@ -0,0 +1,100 @@
+package braintree
+
+import (
+	"fmt"
+	"testing"
+
+	braintree_go "github.com/BoltApp/braintree-go"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestTranslateCvv(t *testing.T) {
+	cases := []struct {
+		in   braintree_go.CVVResponseCode
+		want sleet.CVVResponse
+	}{
+		{braintree_go.CVVResponseCodeMatches, sleet.CVVResponseMatch},
+		{braintree_go.CVVResponseCodeDoesNotMatch, sleet.CVVResponseNoMatch},
+		{braintree_go.CVVResponseCodeNotVerified, sleet.CVVResponseNotProcessed},
+		{braintree_go.CVVResponseCodeNotProvided, sleet.CVVResponseSkipped},
+		{braintree_go.CVVResponseCodeIssuerDoesNotParticipate, sleet.CVVResponseUnsupported},
+		{braintree_go.CVVResponseCodeNotApplicable, sleet.CVVResponseSkipped},
+		{"", sleet.CVVResponseUnknown},
+		{"Fake Result", sleet.CVVResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateCvv(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateAvs(t *testing.T) {
+	cases := []struct {
+		errorCode  braintree_go.AVSResponseCode
+		postalCode braintree_go.AVSResponseCode
+		street     braintree_go.AVSResponseCode
+		want       sleet.AVSResponse
+	}{
+		{"", "M", "M", sleet.AVSResponseMatch},
+		{"", "M", "N", sleet.AVSResponseZip5MatchAddressNoMatch},
+		{"", "M", "U", sleet.AVSResponseZipMatchAddressUnverified},
+		{"", "M", "I", sleet.AVSResponseZipMatchAddressUnverified},
+		{"", "N", "M", sleet.AVSResponseZipNoMatchAddressMatch},
+		{"", "N", "N", sleet.AVSResponseNoMatch},
+		{"", "U", "M", sleet.AVSResponseZipUnverifiedAddressMatch},
+		{"", "I", "M", sleet.AVSResponseZipUnverifiedAddressMatch},
+		{"", "U", "U", sleet.AVSResponseSkipped},
+		{"", "I", "I", sleet.AVSResponseSkipped},
+		{"", "I", "U", sleet.AVSResponseSkipped},
+		{"", "S", "S", sleet.AVSResponseUnsupported},
+		{"", "E", "E", sleet.AVSResponseError},
+		{"", "A", "A", sleet.AVSResponseSkipped},
+		{"E", "", "", sleet.AVSResponseError},
+		{"S", "M", "M", sleet.AVSResponseUnsupported},
+		{"A", "", "", sleet.AVSResponseSkipped},
+		{"", "N", "U", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"", "N", "I", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"", "U", "N", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"", "I", "N", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"", "N", "S", sleet.AVSResponseUnknown},
+		{"", "", "", sleet.AVSResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s:%s:%s", c.errorCode, c.postalCode, c.street), func(t *testing.T) {
+			got := translateAvs(c.errorCode, c.postalCode, c.street)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateDeclineReason(t *testing.T) {
+	cases := []struct {
+		label       string
+		transaction braintree_go.Transaction
+		want        sleet.DeclineReason
+	}{
+		{"Insufficient funds", braintree_go.Transaction{ProcessorResponseCode: 2001}, sleet.DeclineReasonInsufficientFunds},
+		{"Expired card", braintree_go.Transaction{ProcessorResponseCode: 2004}, sleet.DeclineReasonExpiredCard},
+		{"CVV rejection", braintree_go.Transaction{GatewayRejectionReason: braintree_go.GatewayRejectionReasonCVV}, sleet.DeclineReasonCVVFailure},
+		{"Fraud rejection", braintree_go.Transaction{GatewayRejectionReason: braintree_go.GatewayRejectionReasonFraud, ProcessorResponseCode: 1000}, sleet.DeclineReasonSuspectedFraud},
+		{"Unmapped", braintree_go.Transaction{ProcessorResponseCode: 2999}, sleet.DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := translateDeclineReason(&c.transaction)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
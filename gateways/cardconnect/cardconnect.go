@@ -18,22 +18,28 @@ var (
 	_ sleet.ClientWithContext = &CardConnectClient{}
 )
 
-func NewClient(username string, password string, merchantID string, URL string, environment common.Environment) *CardConnectClient {
-	return NewWithHttpClient(username, password, merchantID, URL, environment, common.DefaultHttpClient())
+func NewClient(username string, password string, merchantID string, URL string, environment common.Environment, options ...common.ClientOption) *CardConnectClient {
+	return NewWithHttpClient(username, password, merchantID, URL, environment, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient uses authentication with custom http client
-func NewWithHttpClient(username string, password string, merchantID string, URL string, environment common.Environment, httpClient *http.Client) *CardConnectClient {
+func NewWithHttpClient(username string, password string, merchantID string, URL string, environment common.Environment, httpClient *http.Client, options ...common.ClientOption) *CardConnectClient {
 	return &CardConnectClient{
 		httpClient: httpClient,
 		username:   username,
 		password:   password,
 		merchantID: merchantID,
 		URL:        normalizeURL(URL),
+		baseURL:    common.NewClientOptions(options...).BaseURL,
 	}
 }
 
 func (client *CardConnectClient) buildURL(path string) (string, error) {
+	// an overriding base URL is used as given, keeping its scheme and path prefix
+	if client.baseURL != "" {
+		return client.baseURL + path, nil
+	}
+
 	url, err := url.Parse(client.URL)
 	if err != nil {
 		return "", err
@@ -103,6 +109,9 @@ func (client *CardConnectClient) Authorize(request *sleet.AuthorizationRequest)
 
 // AuthorizeWithContext authorizes a transaction. This transaction must be captured to receive funds
 func (client *CardConnectClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
 	response, httpResponse, err := client.sendRequest(ctx, buildAuthorizeParams(request), AuthorizePath)
 	if err != nil {
 		return nil, err
@@ -120,13 +129,16 @@ func (client *CardConnectClient) AuthorizeWithContext(ctx context.Context, reque
 			CvvResultRaw:          response.CVVResp,
 			CvvResult:             translateCvv(response.CVVResp),
 			ExternalTransactionID: response.RetRef,
+			NetworkResponseCode:   translateNetworkResponseCode(response.RespProc, response.RespCode),
 		}, nil
 	}
 
 	return &sleet.AuthorizationResponse{
-		ErrorCode:  response.RespCode,
-		StatusCode: httpResponse.StatusCode,
-		Header:     responseHeader,
+		ErrorCode:           response.RespCode,
+		StatusCode:          httpResponse.StatusCode,
+		Header:              responseHeader,
+		DeclineReason:       translateDeclineReason(response.RespProc, response.RespCode),
+		NetworkResponseCode: translateNetworkResponseCode(response.RespProc, response.RespCode),
 	}, nil
 }
 
@@ -161,6 +173,9 @@ func (client *CardConnectClient) Void(request *sleet.VoidRequest) (*sleet.VoidRe
 
 // VoidWithContext voids an authorized transaction
 func (client *CardConnectClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	response, httpResponse, err := client.sendRequest(ctx, buildVoidParams(request), VoidPath)
 	if err != nil {
 		return nil, err
@@ -184,6 +199,9 @@ func (client *CardConnectClient) Refund(request *sleet.RefundRequest) (*sleet.Re
 
 // RefundWithContext refunds a captured transaction
 func (client *CardConnectClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	response, httpResponse, err := client.sendRequest(ctx, buildRefundParams(request), RefundPath)
 	if err != nil {
 		return nil, err
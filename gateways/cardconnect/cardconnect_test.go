@@ -0,0 +1,87 @@
+package cardconnect
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient("user", "pass", "merchant", "fts.cardconnect.com", common.Production, &http.Client{Transport: transport},
+		common.WithBaseURL("http://localhost:8080"))
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:8080/cardconnect/rest/auth"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
+
+// responseTransport answers every request with the given CardConnect response body
+type responseTransport struct {
+	body string
+}
+
+func (transport responseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(transport.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestAuthorizeNetworkResponseCode(t *testing.T) {
+	cases := []struct {
+		label string
+		body  string
+		want  string
+	}{
+		{
+			"Approved by the processor",
+			`{"respstat": "A", "retref": "343005123105", "respcode": "00", "respproc": "RPCT", "resptext": "Approval"}`,
+			"00",
+		},
+		{
+			"Declined by the processor",
+			`{"respstat": "C", "retref": "343005123105", "respcode": "51", "respproc": "RPCT", "resptext": "Insufficient funds"}`,
+			"51",
+		},
+		{
+			"Declined by the gateway",
+			`{"respstat": "C", "retref": "343005123105", "respcode": "16", "respproc": "PPS", "resptext": "Expired card"}`,
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			client := NewWithHttpClient("user", "pass", "merchant", "fts.cardconnect.com", common.Production,
+				&http.Client{Transport: responseTransport{c.body}})
+			got, err := client.Authorize(sleet_testing.BaseAuthorizationRequest())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.NetworkResponseCode != c.want {
+				t.Errorf("Got %q, want %q", got.NetworkResponseCode, c.want)
+			}
+		})
+	}
+}
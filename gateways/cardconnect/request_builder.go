@@ -4,8 +4,12 @@ import (
 	"fmt"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 )
 
+// maxPONumberLength is the longest purchase order number CardConnect accepts
+const maxPONumberLength = 36
+
 var (
 	CIT = "C"
 	MIT = "M"
@@ -42,7 +46,7 @@ func buildAuthorizeParams(request *sleet.AuthorizationRequest) *Request {
 
 	name := request.CreditCard.FirstName + " " + request.CreditCard.LastName
 
-	return &Request{
+	params := &Request{
 		Amount:       &amount,
 		Expiry:       &expirationDate,
 		Account:      &request.CreditCard.Number,
@@ -59,9 +63,11 @@ func buildAuthorizeParams(request *sleet.AuthorizationRequest) *Request {
 		Address:      request.BillingAddress.StreetAddress1,
 		Address2:     request.BillingAddress.StreetAddress2,
 		Postal:       request.BillingAddress.PostalCode,
-		Phone:        request.BillingAddress.PhoneNumber,
-		Email:        request.BillingAddress.Email,
+		Phone:        common.NormalizePhoneNumber(request.BillingAddress.PhoneNumber, request.BillingAddress.CountryCode, common.PhoneNumberFormatNational),
+		Email:        common.NormalizeEmail(request.BillingAddress.Email),
 	}
+	addLevel2Data(params, request.Level2Data)
+	return params
 }
 
 func buildCaptureParams(request *sleet.CaptureRequest) *Request {
@@ -71,10 +77,12 @@ func buildCaptureParams(request *sleet.CaptureRequest) *Request {
 		amount = &res
 	}
 
-	return &Request{
+	params := &Request{
 		Amount: amount,
 		RetRef: &request.TransactionReference,
 	}
+	addLevel2Data(params, request.Level2Data)
+	return params
 }
 
 func buildVoidParams(request *sleet.VoidRequest) *Request {
@@ -95,3 +103,23 @@ func buildRefundParams(request *sleet.RefundRequest) *Request {
 		RetRef: &request.TransactionReference,
 	}
 }
+
+// addLevel2Data sets the purchasing card fields, which CardConnect accepts on both authorization and capture
+func addLevel2Data(params *Request, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	taxAmount := sleet.AmountToDecimalString(&level2.TaxAmount)
+	taxExempt := NO
+	if level2.TaxExempt {
+		taxExempt = YES
+	}
+	params.TaxAmount = &taxAmount
+	params.TaxExempt = &taxExempt
+	if level2.PurchaseOrderNumber != "" {
+		params.PONumber = common.SPtr(sleet.TruncateString(level2.PurchaseOrderNumber, maxPONumberLength))
+	}
+	if level2.ShipFromPostalCode != "" {
+		params.ShipFromZip = common.SPtr(level2.ShipFromPostalCode)
+	}
+}
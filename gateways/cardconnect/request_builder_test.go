@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 
 	"github.com/go-test/deep"
 
@@ -132,8 +133,27 @@ func TestBuildAuthRequest(t *testing.T) {
 	}
 }
 
+func TestBuildAuthRequestLevel2(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level2Data = sleet_testing.BaseLevel2Data()
+	request.Level2Data.PurchaseOrderNumber = "PO-00000000001111111111222222222233333333334444444444"
+
+	got := buildAuthorizeParams(request)
+	want := []*string{
+		common.SPtr("PO-000000000011111111112222222222333"),
+		common.SPtr("8.25"),
+		common.SPtr("N"),
+		common.SPtr("94105"),
+	}
+	if diff := deep.Equal([]*string{got.PONumber, got.TaxAmount, got.TaxExempt, got.ShipFromZip}, want); diff != nil {
+		t.Error(diff)
+	}
+}
+
 func TestBuildCaptureRequest(t *testing.T) {
 	base := sleet_testing.BaseCaptureRequest()
+	withLevel2 := sleet_testing.BaseCaptureRequest()
+	withLevel2.Level2Data = sleet_testing.BaseLevel2Data()
 	cases := []struct {
 		label string
 		in    *sleet.CaptureRequest
@@ -147,6 +167,18 @@ func TestBuildCaptureRequest(t *testing.T) {
 				Amount: &defaultTestAmount,
 			},
 		},
+		{
+			"Capture Request with Level 2 data",
+			withLevel2,
+			Request{
+				RetRef:      &OriginalID,
+				Amount:      &defaultTestAmount,
+				PONumber:    common.SPtr("PO-12345"),
+				TaxAmount:   common.SPtr("8.25"),
+				TaxExempt:   common.SPtr("N"),
+				ShipFromZip: common.SPtr("94105"),
+			},
+		},
 	}
 
 	for _, c := range cases {
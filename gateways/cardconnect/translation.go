@@ -46,3 +46,39 @@ func translateAvs(rawAvs string) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// respProcGateway is the respproc of responses generated by the CardPointe gateway itself rather than the processor
+const respProcGateway = "PPS"
+
+// Codes taken from: https://developer.cardpointe.com/gateway-response-codes#cardpointe-gateway-response-codes
+var gatewayDeclineReasonMap = map[string]sleet.DeclineReason{
+	"11": sleet.DeclineReasonInvalidCardNumber,
+	"12": sleet.DeclineReasonInvalidCardNumber,
+	"13": sleet.DeclineReasonInvalidCardNumber,
+	"14": sleet.DeclineReasonCVVFailure,
+	"15": sleet.DeclineReasonInvalidExpirationDate,
+	"16": sleet.DeclineReasonExpiredCard,
+	"17": sleet.DeclineReasonAVSFailure,
+}
+
+// translateDeclineReason converts a CardConnect respcode to its equivalent Sleet decline reason. Processor response
+// codes follow ISO 8583, gateway response codes are translated separately.
+func translateDeclineReason(respProc string, respCode string) sleet.DeclineReason {
+	if respProc != respProcGateway {
+		return sleet.DeclineReasonFromISO8583(respCode)
+	}
+	reason, ok := gatewayDeclineReasonMap[respCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
+
+// translateNetworkResponseCode returns the respcode of responses forwarded from the processor. Responses generated by
+// the CardPointe gateway itself carry no network response code.
+func translateNetworkResponseCode(respProc string, respCode string) string {
+	if respProc == respProcGateway {
+		return ""
+	}
+	return respCode
+}
@@ -18,6 +18,7 @@ type CardConnectClient struct {
 	merchantID string
 	httpClient *http.Client
 	URL        string
+	baseURL    string
 }
 
 func UnmarshalRequest(data []byte) (Request, error) {
@@ -52,6 +53,10 @@ type Request struct {
 	Phone         *string `json:"phone,omitempty"`
 	Email         *string `json:"email,omitempty"`
 	Company       *string `json:"company,omitempty"`
+	PONumber      *string `json:"ponumber,omitempty"`
+	TaxAmount     *string `json:"taxamnt,omitempty"`
+	TaxExempt     *string `json:"taxexempt,omitempty"`
+	ShipFromZip   *string `json:"shipfromzip,omitempty"`
 }
 
 func UnmarshalResponse(data []byte) (Response, error) {
@@ -9,17 +9,20 @@ import (
 	"github.com/checkout/checkout-sdk-go/transfers"
 
 	"github.com/checkout/checkout-sdk-go/configuration"
+	"github.com/checkout/checkout-sdk-go/payments"
 	"github.com/checkout/checkout-sdk-go/payments/nas"
 
 	"github.com/BoltApp/sleet"
 	"github.com/BoltApp/sleet/common"
 
 	"github.com/checkout/checkout-sdk-go"
+	checkoutclient "github.com/checkout/checkout-sdk-go/client"
 )
 
 var (
 	// assert client interface
-	_ sleet.ClientWithContext = &CheckoutComClient{}
+	_ sleet.ClientWithContext       = &CheckoutComClient{}
+	_ sleet.TransactionLookupClient = &CheckoutComClient{}
 )
 
 // checkout.com documentation here: https://www.checkout.com/docs/four/payments/accept-payments, SDK here: https://github.com/checkout/checkout-sdk-go
@@ -30,6 +33,10 @@ type CheckoutComClient struct {
 	processingChannelId *string
 	httpClient          *http.Client
 	env                 *configuration.CheckoutEnv
+
+	// config is built once and shared by all requests, see apiClient
+	config    *configuration.Configuration
+	configErr error
 }
 
 const AcceptedStatusCode = 202
@@ -38,48 +45,55 @@ const CreatedStatusCode = 201
 // NewClient creates a CheckoutComClient
 // Note: PCID is optional to support legacy checkout.com merchants whose PCID is linked to their API key.
 // New merchants will need to provide their PCID or ask their checkout.com rep to disable the field requirement.
-func NewClient(env common.Environment, apiKey string, processingChannelId *string) *CheckoutComClient {
-	return NewWithHTTPClient(env, apiKey, processingChannelId, common.DefaultHttpClient())
+func NewClient(env common.Environment, apiKey string, processingChannelId *string, options ...common.ClientOption) *CheckoutComClient {
+	return NewWithHTTPClient(env, apiKey, processingChannelId, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHTTPClient uses a custom http client for requests
-func NewWithHTTPClient(env common.Environment, apiKey string, processingChannelId *string, httpClient *http.Client) *CheckoutComClient {
-	return &CheckoutComClient{
+func NewWithHTTPClient(env common.Environment, apiKey string, processingChannelId *string, httpClient *http.Client, options ...common.ClientOption) *CheckoutComClient {
+	client := &CheckoutComClient{
 		apiKey:              apiKey,
 		httpClient:          httpClient,
-		env:                 GetEnv(env),
+		env:                 withBaseURL(GetEnv(env), common.NewClientOptions(options...).BaseURL),
 		processingChannelId: processingChannelId,
 	}
+	client.config, client.configErr = client.buildConfiguration()
+	return client
 }
 
-func (client *CheckoutComClient) generateCheckoutDCClient() (*nas.Client, error) {
-	builder := checkout.Builder().
-		StaticKeys().
-		WithEnvironment(client.env).
-		WithSecretKey(client.apiKey)
-
-	if client.httpClient != nil {
-		builder.WithHttpClient(client.httpClient)
+// buildConfiguration validates the API key and builds the configuration of the SDK, as the SDK builder would
+func (client *CheckoutComClient) buildConfiguration() (*configuration.Configuration, error) {
+	builder := checkout.Builder().StaticKeys().WithSecretKey(client.apiKey)
+	if err := builder.ValidateSecretKey(configuration.DefaultSecretKeyPattern); err != nil {
+		return nil, err
 	}
+	credentials := configuration.NewDefaultKeysSdkCredentials(client.apiKey, "")
+	return configuration.NewConfiguration(credentials, client.env, client.httpClient, nil), nil
+}
 
-	api, err := builder.Build()
-	if err != nil {
-		return nil, err
+// apiClient returns an SDK client sending requests to baseURI with ctx. The SDK builds its requests without a context,
+// so each request gets a client whose http client binds ctx; the configuration is shared and the SDK API is not
+// rebuilt.
+func (client *CheckoutComClient) apiClient(ctx context.Context, baseURI string) *checkoutclient.ApiClient {
+	return &checkoutclient.ApiClient{
+		HttpClient: *common.HttpClientWithContext(ctx, &client.config.HttpClient),
+		BaseUri:    baseURI,
+		Log:        client.config.Logger,
 	}
+}
 
-	return api.Payments, nil
+func (client *CheckoutComClient) generateCheckoutDCClient(ctx context.Context) (*nas.Client, error) {
+	if client.configErr != nil {
+		return nil, client.configErr
+	}
+	return nas.NewClient(client.config, client.apiClient(ctx, client.env.BaseUri())), nil
 }
 
-func (client *CheckoutComClient) generateCheckoutTransfersClient() (*transfers.Client, error) {
-	api, err := checkout.Builder().
-		StaticKeys().
-		WithEnvironment(client.env).
-		WithSecretKey(client.apiKey).
-		Build()
-	if err != nil {
-		return nil, err
+func (client *CheckoutComClient) generateCheckoutTransfersClient(ctx context.Context) (*transfers.Client, error) {
+	if client.configErr != nil {
+		return nil, client.configErr
 	}
-	return api.Transfers, nil
+	return transfers.NewClient(client.config, client.apiClient(ctx, client.env.TransfersUri())), nil
 }
 
 // Authorize a transaction for specified amount
@@ -88,9 +102,11 @@ func (client *CheckoutComClient) Authorize(request *sleet.AuthorizationRequest)
 }
 
 // AuthorizeWithContext authorizes a transaction for specified amount
-// NOTE -- checkout's SDK does not support context...
-func (client *CheckoutComClient) AuthorizeWithContext(_ context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	checkoutComClient, err := client.generateCheckoutDCClient()
+func (client *CheckoutComClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	checkoutComClient, err := client.generateCheckoutDCClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -114,31 +130,11 @@ func (client *CheckoutComClient) AuthorizeWithContext(_ context.Context, request
 			CvvResult:            sleet.CVVResponseUnknown,
 			ErrorCode:            err.Error(),
 			StatusCode:           statusCode,
+			ResultType:           translateErrorResultType(err),
 		}, err
 	}
 
-	if response.Approved {
-		return &sleet.AuthorizationResponse{
-			Success:              true,
-			TransactionReference: response.Id,
-			AvsResult:            sleet.AVSresponseZipMatchAddressMatch, // TODO: Use translateAvs(AVSResponseCode(response.Processed.Source.AVSCheck)) to enable avs code handling
-			CvvResult:            sleet.CVVResponseMatch,                // TODO: use translateCvv(CVVResponseCode(response.Processed.Source.CVVCheck)) to enable cvv code handling
-			AvsResultRaw:         response.Source.ResponseCardSource.AvsCheck,
-			CvvResultRaw:         response.Source.ResponseCardSource.CvvCheck,
-			Response:             response.ResponseCode,
-			StatusCode:           statusCode,
-		}, nil
-	} else {
-		return &sleet.AuthorizationResponse{
-			Success:              false,
-			TransactionReference: "",
-			AvsResult:            sleet.AVSResponseUnknown,
-			CvvResult:            sleet.CVVResponseUnknown,
-			Response:             response.ResponseCode,
-			ErrorCode:            response.ResponseCode,
-			StatusCode:           statusCode,
-		}, nil
-	}
+	return translatePaymentResponse(response, request.CreditCard.Network), nil
 }
 
 // Capture an authorized transaction by charge ID
@@ -147,9 +143,8 @@ func (client *CheckoutComClient) Capture(request *sleet.CaptureRequest) (*sleet.
 }
 
 // CaptureWithContext authorizes an authorized transaction by charge ID
-// NOTE -- checkout's SDK does not support context...
 func (client *CheckoutComClient) CaptureWithContext(ctx context.Context, request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
-	checkoutComClient, err := client.generateCheckoutDCClient()
+	checkoutComClient, err := client.generateCheckoutDCClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -182,9 +177,11 @@ func (client *CheckoutComClient) Refund(request *sleet.RefundRequest) (*sleet.Re
 }
 
 // RefundWithContext refunds a captured transaction with amount and charge ID
-// NOTE -- checkout's SDK does not support context...
 func (client *CheckoutComClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
-	checkoutComClient, err := client.generateCheckoutDCClient()
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
+	checkoutComClient, err := client.generateCheckoutDCClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -216,9 +213,11 @@ func (client *CheckoutComClient) Void(request *sleet.VoidRequest) (*sleet.VoidRe
 }
 
 // VoidWithContext voids an authorized transaction with charge ID
-// NOTE -- checkout's SDK does not support context...
 func (client *CheckoutComClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
-	checkoutComClient, err := client.generateCheckoutDCClient()
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
+	checkoutComClient, err := client.generateCheckoutDCClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -251,9 +250,8 @@ func (client *CheckoutComClient) BalanceTransfer(request *BalanceTransferRequest
 }
 
 // BalanceTransferWithContext transfers funds from a source account to a destination account
-// NOTE -- checkout's SDK does not support context...
 func (client *CheckoutComClient) BalanceTransferWithContext(ctx context.Context, request *BalanceTransferRequest) (*BalanceTransferResponse, error) {
-	checkoutComClient, err := client.generateCheckoutTransfersClient()
+	checkoutComClient, err := client.generateCheckoutTransfersClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -283,3 +281,61 @@ func (client *CheckoutComClient) BalanceTransferWithContext(ctx context.Context,
 		}, nil
 	}
 }
+
+// paymentListLimit is the number of payments of each page searched for a payment by its reference
+const paymentListLimit = 100
+
+// paymentListMaxPages bounds the number of pages searched for a payment by its reference
+const paymentListMaxPages = 10
+
+// LookupTransactionWithContext looks up a payment whose outcome is unknown, by its id for captures and by the
+// reference sent for its MerchantOrderReference for authorizations. Payments sharing the reference are told apart by
+// the ClientTransactionReference sent in their metadata, without one the reference must match a single payment.
+// sleet.ErrTransactionLookupIncomplete is returned when the payments with the reference cannot all be searched or
+// the payment is ambiguous.
+func (client *CheckoutComClient) LookupTransactionWithContext(ctx context.Context, request *sleet.TransactionLookupRequest) (*sleet.TransactionLookupResponse, error) {
+	checkoutComClient, err := client.generateCheckoutDCClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.TransactionReference != "" {
+		payment, err := checkoutComClient.GetPaymentDetails(request.TransactionReference)
+		if err != nil {
+			return nil, err
+		}
+		return translatePaymentStatus(payment.Id, payment.Status), nil
+	}
+
+	if request.MerchantOrderReference == "" {
+		return &sleet.TransactionLookupResponse{Found: false}, nil
+	}
+	var candidates []nas.GetPaymentResponse
+	for page := 0; page < paymentListMaxPages; page++ {
+		list, err := checkoutComClient.RequestPaymentList(payments.QueryRequest{
+			Limit:     paymentListLimit,
+			Skip:      page * paymentListLimit,
+			Reference: request.MerchantOrderReference,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, payment := range list.Data {
+			if request.ClientTransactionReference == nil {
+				candidates = append(candidates, payment)
+				if len(candidates) > 1 {
+					return nil, sleet.ErrTransactionLookupIncomplete
+				}
+			} else if payment.Metadata[clientTransactionReferenceMetadata] == *request.ClientTransactionReference {
+				return translatePaymentStatus(payment.Id, payment.Status), nil
+			}
+		}
+		if len(list.Data) < paymentListLimit || (page+1)*paymentListLimit >= list.TotalCount {
+			if len(candidates) == 1 {
+				return translatePaymentStatus(candidates[0].Id, candidates[0].Status), nil
+			}
+			return &sleet.TransactionLookupResponse{Found: false}, nil
+		}
+	}
+	return nil, sleet.ErrTransactionLookupIncomplete
+}
@@ -0,0 +1,285 @@
+package checkoutcom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/checkout/checkout-sdk-go/payments"
+	"github.com/checkout/checkout-sdk-go/payments/nas"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// blockingTransport waits for the context of each request to be done, as a gateway that does not answer would
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestOperationsHonourContextDeadline(t *testing.T) {
+	client := NewWithHTTPClient(common.Sandbox, "sk_sbox_m73dzbpy7cf3gfd46xr4yj5xo4e", nil, &http.Client{Transport: blockingTransport{}})
+	amount := &sleet.Amount{Amount: 100, Currency: "USD"}
+
+	operations := map[string]func(ctx context.Context) error{
+		"Authorize": func(ctx context.Context) error {
+			response, err := client.AuthorizeWithContext(ctx, sleet_testing.BaseAuthorizationRequest())
+			if response == nil || response.ResultType != sleet.ResultTypeServerError {
+				t.Errorf("expected server error response, got %+v", response)
+			}
+			return err
+		},
+		"Capture": func(ctx context.Context) error {
+			_, err := client.CaptureWithContext(ctx, &sleet.CaptureRequest{Amount: amount, TransactionReference: "pay_123"})
+			return err
+		},
+		"Refund": func(ctx context.Context) error {
+			_, err := client.RefundWithContext(ctx, &sleet.RefundRequest{Amount: amount, TransactionReference: "pay_123"})
+			return err
+		},
+		"Void": func(ctx context.Context) error {
+			_, err := client.VoidWithContext(ctx, &sleet.VoidRequest{TransactionReference: "pay_123"})
+			return err
+		},
+		"Lookup": func(ctx context.Context) error {
+			_, err := client.LookupTransactionWithContext(ctx, &sleet.TransactionLookupRequest{TransactionReference: "pay_123"})
+			return err
+		},
+	}
+
+	for label, operation := range operations {
+		t.Run(label, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if err := operation(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected deadline exceeded, got %v", err)
+			}
+		})
+	}
+}
+
+// authorizedTransport answers every request with an authorized payment
+type authorizedTransport struct{}
+
+func (authorizedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"id": "pay_123", "approved": true, "status": "Authorized", "response_code": "10000"}`)),
+		Request:    req,
+	}, nil
+}
+
+// BenchmarkAuthorize compares a client shared by all authorizations with a client built for each one, which pays
+// for building the Checkout.com configuration on every request. Authorizations with a cancellable context, as sent
+// by servers, share the configuration as well.
+func BenchmarkAuthorize(b *testing.B) {
+	httpClient := &http.Client{Transport: authorizedTransport{}}
+	request := sleet_testing.BaseAuthorizationRequest()
+	newClient := func() *CheckoutComClient {
+		return NewWithHTTPClient(common.Sandbox, "sk_sbox_m73dzbpy7cf3gfd46xr4yj5xo4e", nil, httpClient)
+	}
+
+	b.Run("SharedClient", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SharedClientParallel", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := client.Authorize(request); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	b.Run("SharedClientWithContext", func(b *testing.B) {
+		client := newClient()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.AuthorizeWithContext(ctx, request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SharedClientWithContextParallel", func(b *testing.B) {
+		client := newClient()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if _, err := client.AuthorizeWithContext(ctx, request); err != nil {
+					b.Error(err)
+				}
+				cancel()
+			}
+		})
+	})
+
+	b.Run("ClientPerRequest", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := newClient().Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHTTPClient(
+		common.Production,
+		"sk_sbox_m73dzbpy7cf3gfd46xr4yj5xo4e",
+		nil,
+		&http.Client{Transport: transport},
+		common.WithBaseURL("http://localhost:8080"),
+	)
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:8080/payments"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
+
+// paymentListTransport serves total payments sharing a reference, numbered from the most recent, with the client
+// transaction references "client-<number>", and records the skip of each page requested
+type paymentListTransport struct {
+	total int
+	skips []int
+}
+
+func (transport *paymentListTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	skip, _ := strconv.Atoi(query.Get("skip"))
+	transport.skips = append(transport.skips, skip)
+	list := nas.GetPaymentListResponse{Limit: limit, Skip: skip, TotalCount: transport.total}
+	for i := skip; i < skip+limit && i < transport.total; i++ {
+		list.Data = append(list.Data, nas.GetPaymentResponse{
+			Id:       fmt.Sprintf("pay_%d", i),
+			Status:   payments.Authorized,
+			Metadata: map[string]interface{}{clientTransactionReferenceMetadata: fmt.Sprintf("client-%d", i)},
+		})
+	}
+	body, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestLookupTransactionByReference(t *testing.T) {
+	cases := []struct {
+		label           string
+		total           int
+		clientReference *string
+		wantReference   string
+		wantErr         error
+		wantSkips       []int
+	}{
+		{
+			label:           "Found by client reference on a later page",
+			total:           3 * paymentListLimit,
+			clientReference: common.SPtr(fmt.Sprintf("client-%d", paymentListLimit+1)),
+			wantReference:   fmt.Sprintf("pay_%d", paymentListLimit+1),
+			wantSkips:       []int{0, paymentListLimit},
+		},
+		{
+			label:           "Not found after every payment",
+			total:           paymentListLimit + 1,
+			clientReference: common.SPtr("client-missing"),
+			wantSkips:       []int{0, paymentListLimit},
+		},
+		{
+			label:           "Incomplete after the maximum pages",
+			total:           (paymentListMaxPages + 1) * paymentListLimit,
+			clientReference: common.SPtr("client-missing"),
+			wantErr:         sleet.ErrTransactionLookupIncomplete,
+			wantSkips:       []int{0, 100, 200, 300, 400, 500, 600, 700, 800, 900},
+		},
+		{
+			label:         "Single payment without client reference",
+			total:         1,
+			wantReference: "pay_0",
+			wantSkips:     []int{0},
+		},
+		{
+			label:     "Ambiguous without client reference",
+			total:     2,
+			wantErr:   sleet.ErrTransactionLookupIncomplete,
+			wantSkips: []int{0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			transport := &paymentListTransport{total: c.total}
+			client := NewWithHTTPClient(common.Sandbox, "sk_sbox_m73dzbpy7cf3gfd46xr4yj5xo4e", nil, &http.Client{Transport: transport})
+
+			got, err := client.LookupTransactionWithContext(context.Background(), &sleet.TransactionLookupRequest{
+				MerchantOrderReference:     "order-1",
+				ClientTransactionReference: c.clientReference,
+			})
+
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("Got error %v, want %v", err, c.wantErr)
+			}
+			if err == nil && got.TransactionReference != c.wantReference {
+				t.Errorf("Got reference %q, want %q", got.TransactionReference, c.wantReference)
+			}
+			if err == nil && got.Found != (c.wantReference != "") {
+				t.Errorf("Got found %t, want %t", got.Found, c.wantReference != "")
+			}
+			if !reflect.DeepEqual(transport.skips, c.wantSkips) {
+				t.Errorf("Got skips %v, want %v", transport.skips, c.wantSkips)
+			}
+		})
+	}
+}
@@ -12,3 +12,12 @@ func GetEnv(env common.Environment) *configuration.CheckoutEnv {
 	}
 	return configuration.Sandbox()
 }
+
+// withBaseURL sends the API, transfers, files and balances requests of env to baseURL, env is returned as is when
+// baseURL is empty. The OAuth authorization endpoint is kept, clients authenticate with static keys.
+func withBaseURL(env *configuration.CheckoutEnv, baseURL string) *configuration.CheckoutEnv {
+	if baseURL == "" {
+		return env
+	}
+	return configuration.NewEnvironment(baseURL, env.AuthorizationUri(), baseURL, baseURL, baseURL, env.IsSandbox())
+}
@@ -16,6 +16,10 @@ import (
 // Cof specifies the transaction type under the Credential-on-File framework
 const recurringPaymentType = "Recurring"
 
+// clientTransactionReferenceMetadata is the payment metadata key of the ClientTransactionReference, used to tell apart
+// payments sharing a reference when looking one up
+const clientTransactionReferenceMetadata = "client_transaction_reference"
+
 func buildChargeParams(authRequest *sleet.AuthorizationRequest, processingChannelId *string) (*nas.PaymentRequest, error) {
 	var source = sources.NewRequestCardSource()
 	source.Number = authRequest.CreditCard.Number
@@ -42,11 +46,14 @@ func buildChargeParams(authRequest *sleet.AuthorizationRequest, processingChanne
 		Currency:  checkout_com_common.Currency(authRequest.Amount.Currency),
 		Reference: authRequest.MerchantOrderReference,
 		Customer: &checkout_com_common.CustomerRequest{
-			Email: common.SafeStr(authRequest.BillingAddress.Email),
+			Email: common.SafeStr(common.NormalizeEmail(authRequest.BillingAddress.Email)),
 			Name:  authRequest.CreditCard.FirstName + " " + authRequest.CreditCard.LastName,
 		},
 		ProcessingChannelId: common.SafeStr(processingChannelId),
 	}
+	if authRequest.ClientTransactionReference != nil {
+		request.Metadata = map[string]interface{}{clientTransactionReferenceMetadata: *authRequest.ClientTransactionReference}
+	}
 	if authRequest.ProcessingInitiator != nil {
 		// see documentation for instructions on stored credentials, merchant-initiated transactions, and subscriptions:
 		// https://www.checkout.com/docs/four/payments/accept-payments/use-saved-details/about-stored-card-details
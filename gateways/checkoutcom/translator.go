@@ -1,14 +1,23 @@
 package checkoutcom
 
-import "github.com/BoltApp/sleet"
+import (
+	"errors"
+	"strings"
+
+	checkouterrors "github.com/checkout/checkout-sdk-go/errors"
+	"github.com/checkout/checkout-sdk-go/payments"
+	"github.com/checkout/checkout-sdk-go/payments/nas"
+
+	"github.com/BoltApp/sleet"
+)
 
 var cvvMap = map[CVVResponseCode]sleet.CVVResponse{
 	CVVResponseMatched:       sleet.CVVResponseMatch,
-	CVVResponseNotConfigured: sleet.CVVResponseError,
-	CVVResponseCVDMissing:    sleet.CVVResponseError,
+	CVVResponseNotConfigured: sleet.CVVResponseUnsupported,
+	CVVResponseCVDMissing:    sleet.CVVResponseNotProcessed,
 	CVVResponseNotPresent:    sleet.CVVResponseRequiredButMissing,
-	CVVResponseNotValid:      sleet.CVVResponseSkipped,
-	CVVResponseFailed:        sleet.CVVResponseError,
+	CVVResponseNotValid:      sleet.CVVResponseSuspicious,
+	CVVResponseFailed:        sleet.CVVResponseNoMatch,
 }
 
 func translateCvv(code CVVResponseCode) sleet.CVVResponse {
@@ -20,10 +29,10 @@ func translateCvv(code CVVResponseCode) sleet.CVVResponse {
 }
 
 var avsMap = map[AVSResponseCode]sleet.AVSResponse{
-	AVSResponseStreetMatch:                                 sleet.AVSResponseMatch,
+	AVSResponseStreetMatch:                                 sleet.AVSResponseZipNoMatchAddressMatch,
 	AVSResponseStreetMatchPostalUnverified:                 sleet.AVSResponseNonUsZipUnverifiedAddressMatch,
 	AVSResponseStreetAndPostalUnverified:                   sleet.AVSResponseNonUsZipNoMatchAddressNoMatch,
-	AVSResponseStreetAndPostalMatch:                        sleet.AVSResponseNameMatchZipMatchAddressMatch,
+	AVSResponseStreetAndPostalMatch:                        sleet.AVSResponseNonUsZipMatchAddressMatch,
 	AVSResponseAddressMatchError:                           sleet.AVSResponseError,
 	AVSResponseStreetAndPostalMatchUK:                      sleet.AVSResponseNonUsZipMatchAddressMatch,
 	AVSResponseNotVerifiedOrNotSupported:                   sleet.AVSResponseUnsupported,
@@ -40,8 +49,8 @@ var avsMap = map[AVSResponseCode]sleet.AVSResponse{
 	AVSResponseStreetAndFiveDigitPostalMatch:               sleet.AVSResponseZip5MatchAddressMatch,
 	AVSResponseFiveDigitPostalMatch:                        sleet.AVSResponseZip5MatchAddressNoMatch,
 	AVSResponseCardholderNameIncorrectPostalMatch:          sleet.AVSResponseNameNoMatchZipMatch,
-	AVSResponseCardholderNameIncorrectStreetAndPostalMatch: sleet.AVSResponseNameMatchZipMatchAddressMatch,
-	AVSResponseCardholderNameIncorrectStreetMatch:          sleet.AVSResponseNameMatchZipNoMatchAddressMatch,
+	AVSResponseCardholderNameIncorrectStreetAndPostalMatch: sleet.AVSResponseNameNoMatchZipMatchAddressMatch,
+	AVSResponseCardholderNameIncorrectStreetMatch:          sleet.AVSResponseNameNoMatchAddressMatch,
 	AVSResponseCardholderNameMatch:                         sleet.AVSResponseNameMatchZipNoMatchAddressNoMatch,
 	AVSResponseCardholderNameAndPostalMatch:                sleet.AVSResponseNameMatchZipMatchAddressNoMatch,
 	AVSResponseCardholderNameAndStreetAndPostalMatch:       sleet.AVSResponseNameMatchZipMatchAddressMatch,
@@ -55,3 +64,209 @@ func translateAvs(avs AVSResponseCode) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// Codes taken from: https://www.checkout.com/docs/resources/codes/api-response-codes
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"20001": sleet.DeclineReasonCallIssuer,
+	"20002": sleet.DeclineReasonCallIssuer,
+	"20003": sleet.DeclineReasonTransactionNotPermitted,
+	"20005": sleet.DeclineReasonDoNotHonor,
+	"20006": sleet.DeclineReasonProcessingError,
+	"20012": sleet.DeclineReasonTransactionNotPermitted,
+	"20013": sleet.DeclineReasonInvalidAmount,
+	"20014": sleet.DeclineReasonInvalidCardNumber,
+	"20019": sleet.DeclineReasonProcessingError,
+	"20038": sleet.DeclineReasonLimitExceeded,
+	"20046": sleet.DeclineReasonRestrictedCard,
+	"20051": sleet.DeclineReasonInsufficientFunds,
+	"20054": sleet.DeclineReasonExpiredCard,
+	"20057": sleet.DeclineReasonTransactionNotPermitted,
+	"20058": sleet.DeclineReasonTransactionNotPermitted,
+	"20059": sleet.DeclineReasonSuspectedFraud,
+	"20061": sleet.DeclineReasonLimitExceeded,
+	"20062": sleet.DeclineReasonRestrictedCard,
+	"20063": sleet.DeclineReasonSuspectedFraud,
+	"20065": sleet.DeclineReasonLimitExceeded,
+	"20075": sleet.DeclineReasonLimitExceeded,
+	"20087": sleet.DeclineReasonCVVFailure,
+	"20088": sleet.DeclineReasonDoNotHonor,
+	"20091": sleet.DeclineReasonIssuerUnavailable,
+	"20093": sleet.DeclineReasonTransactionNotPermitted,
+	"20096": sleet.DeclineReasonProcessingError,
+	"20100": sleet.DeclineReasonInvalidExpirationDate,
+	"20154": sleet.DeclineReasonAuthenticationRequired,
+	"200N7": sleet.DeclineReasonCVVFailure,
+	"200R1": sleet.DeclineReasonTransactionNotPermitted,
+	"30004": sleet.DeclineReasonPickUpCard,
+	"30007": sleet.DeclineReasonPickUpCard,
+	"30041": sleet.DeclineReasonLostOrStolenCard,
+	"30043": sleet.DeclineReasonLostOrStolenCard,
+	"40101": sleet.DeclineReasonSuspectedFraud,
+}
+
+func translateDeclineReason(responseCode string) sleet.DeclineReason {
+	reason, ok := declineReasonMap[responseCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
+
+// issuerResponseCodePrefixes are the prefixes of the Checkout.com response codes forwarded from the issuer: approvals
+// (100xx), soft declines (200xx) and hard declines (300xx). Their last two characters are the issuer's ISO 8583 code.
+var issuerResponseCodePrefixes = []string{"100", "200", "300"}
+
+// translateNetworkResponseCode returns the ISO 8583 code of response codes forwarded from the issuer. Codes generated
+// by Checkout.com itself, such as risk responses and 3DS errors, carry no network response code.
+func translateNetworkResponseCode(responseCode string) string {
+	if len(responseCode) != 5 {
+		return ""
+	}
+	for _, prefix := range issuerResponseCodePrefixes {
+		if strings.HasPrefix(responseCode, prefix) {
+			return responseCode[3:]
+		}
+	}
+	return ""
+}
+
+// translateRetryAdvice classifies a declined authorization. The Visa decline category of the issuer response code is
+// used when available. Otherwise Checkout.com groups its response codes into soft declines (20xxx), hard declines
+// (30xxx) and risk responses (4xxxx); the group overrides the retry advice of the decline reason.
+func translateRetryAdvice(responseCode string, reason sleet.DeclineReason, network sleet.CreditCardNetwork) *sleet.RetryAdvice {
+	advice := sleet.NewRetryAdvice(reason, network, "", translateNetworkResponseCode(responseCode))
+	if advice == nil || advice.Source != sleet.RetryAdviceSourceDeclineReason {
+		return advice
+	}
+	switch {
+	case strings.HasPrefix(responseCode, "20") && advice.DeclineType != sleet.DeclineTypeSoft:
+		advice.DeclineType = sleet.DeclineTypeSoft
+		advice.Retryable = true
+	case strings.HasPrefix(responseCode, "30") || strings.HasPrefix(responseCode, "4"):
+		advice.DeclineType = sleet.DeclineTypeHard
+		advice.Retryable = false
+		advice.RetryAfter = 0
+	default:
+		return advice
+	}
+	advice.Source = sleet.RetryAdviceSourceGateway
+	advice.Code = responseCode
+	return advice
+}
+
+var paymentStatusMap = map[payments.PaymentStatus]sleet.ResultType{
+	payments.Pending:           sleet.ResultTypePending,
+	payments.Authorized:        sleet.ResultTypeSuccess,
+	payments.CardVerified:      sleet.ResultTypeSuccess,
+	payments.PartiallyCaptured: sleet.ResultTypeSuccess,
+	payments.Captured:          sleet.ResultTypeSuccess,
+	payments.PartiallyRefunded: sleet.ResultTypeSuccess,
+	payments.Refunded:          sleet.ResultTypeSuccess,
+	payments.Paid:              sleet.ResultTypeSuccess,
+	payments.Voided:            sleet.ResultTypeSuccess,
+	payments.Declined:          sleet.ResultTypePaymentError,
+	payments.Canceled:          sleet.ResultTypePaymentError,
+	payments.Expired:           sleet.ResultTypePaymentError,
+}
+
+var capturedPaymentStatuses = map[payments.PaymentStatus]bool{
+	payments.PartiallyCaptured: true,
+	payments.Captured:          true,
+	payments.PartiallyRefunded: true,
+	payments.Refunded:          true,
+	payments.Paid:              true,
+}
+
+// translatePaymentStatus converts the status of a payment found by a lookup. Voided and refunded payments were
+// approved before.
+func translatePaymentStatus(id string, status payments.PaymentStatus) *sleet.TransactionLookupResponse {
+	resultType, ok := paymentStatusMap[status]
+	if !ok {
+		resultType = sleet.ResultTypeUnknownError
+	}
+	pendingReason := sleet.PendingReasonNone
+	if resultType == sleet.ResultTypePending {
+		pendingReason = sleet.PendingReasonAsyncProcessing
+	}
+	return &sleet.TransactionLookupResponse{
+		Found:                true,
+		TransactionReference: id,
+		ResultType:           resultType,
+		PendingReason:        pendingReason,
+		Captured:             capturedPaymentStatuses[status],
+		Status:               string(status),
+	}
+}
+
+// translatePaymentResponse converts the response of an authorization of a card of the given network. Declined payments
+// keep their id, which Checkout.com uses to look them up.
+func translatePaymentResponse(response *nas.PaymentResponse, network sleet.CreditCardNetwork) *sleet.AuthorizationResponse {
+	var avsRaw, cvvRaw string
+	if response.Source != nil && response.Source.ResponseCardSource != nil {
+		avsRaw = response.Source.ResponseCardSource.AvsCheck
+		cvvRaw = response.Source.ResponseCardSource.CvvCheck
+	}
+	result := &sleet.AuthorizationResponse{
+		Success:              response.Approved,
+		TransactionReference: response.Id,
+		AvsResult:            translateAvs(AVSResponseCode(avsRaw)),
+		CvvResult:            translateCvv(CVVResponseCode(cvvRaw)),
+		AvsResultRaw:         avsRaw,
+		CvvResultRaw:         cvvRaw,
+		Response:             response.ResponseCode,
+		Message:              response.ResponseSummary,
+		StatusCode:           response.HttpMetadata.StatusCode,
+		NetworkTransactionID: response.SchemeId,
+		NetworkResponseCode:  translateNetworkResponseCode(response.ResponseCode),
+		Metadata:             buildResponseMetadata(response),
+	}
+
+	switch {
+	case response.Approved:
+		result.ResultType = sleet.ResultTypeSuccess
+	case response.Status == payments.Pending && hasRedirectLink(response):
+		// the payment waits for the shopper to complete 3DS authentication at the redirect link
+		result.ResultType = sleet.ResultTypePaymentError
+		result.DeclineReason = sleet.DeclineReasonAuthenticationRequired
+		result.RetryAdvice = sleet.RetryAdviceFromDeclineReason(result.DeclineReason)
+	case response.Status == payments.Pending:
+		result.ResultType = sleet.ResultTypePending
+		result.PendingReason = sleet.PendingReasonAsyncProcessing
+	default:
+		result.ResultType = sleet.ResultTypePaymentError
+		result.ErrorCode = response.ResponseCode
+		result.DeclineReason = translateDeclineReason(response.ResponseCode)
+		result.RetryAdvice = translateRetryAdvice(response.ResponseCode, result.DeclineReason, network)
+	}
+	return result
+}
+
+// redirectLink is the link of a pending payment to the page where the shopper completes 3DS authentication
+const redirectLink = "redirect"
+
+// hasRedirectLink reports whether the payment response links to a page the shopper must be redirected to
+func hasRedirectLink(response *nas.PaymentResponse) bool {
+	link, ok := response.Links[redirectLink]
+	return ok && link.HRef != nil && *link.HRef != ""
+}
+
+func buildResponseMetadata(response *nas.PaymentResponse) map[string]string {
+	metadata := map[string]string{}
+	if response.AuthCode != "" {
+		metadata[sleet.AuthCodeMetadata] = response.AuthCode
+	}
+	if response.ResponseCode != "" {
+		metadata[sleet.ResponseCodeMetadata] = response.ResponseCode
+	}
+	return metadata
+}
+
+// translateErrorResultType classifies an error returned by the SDK. Requests rejected by Checkout.com are API errors,
+// while server errors, network errors and cancelled or expired contexts are server errors.
+func translateErrorResultType(err error) sleet.ResultType {
+	var apiErr checkouterrors.CheckoutAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+		return sleet.ResultTypeAPIError
+	}
+	return sleet.ResultTypeServerError
+}
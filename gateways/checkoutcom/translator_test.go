@@ -0,0 +1,264 @@
+package checkoutcom
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	checkout_com_common "github.com/checkout/checkout-sdk-go/common"
+	checkouterrors "github.com/checkout/checkout-sdk-go/errors"
+	"github.com/checkout/checkout-sdk-go/payments"
+	"github.com/checkout/checkout-sdk-go/payments/nas"
+	"github.com/go-test/deep"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestTranslateAvs(t *testing.T) {
+	cases := []struct {
+		in   AVSResponseCode
+		want sleet.AVSResponse
+	}{
+		{AVSResponseStreetMatch, sleet.AVSResponseZipNoMatchAddressMatch},
+		{AVSResponseStreetAndFiveDigitPostalMatch, sleet.AVSResponseZip5MatchAddressMatch},
+		{AVSResponseNoAddressMatch, sleet.AVSResponseNoMatch},
+		{AVSResponseCardholderNameIncorrectStreetMatch, sleet.AVSResponseNameNoMatchAddressMatch},
+		{"", sleet.AVSResponseUnknown},
+		{"Fake Result", sleet.AVSResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateAvs(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateCvv(t *testing.T) {
+	cases := []struct {
+		in   CVVResponseCode
+		want sleet.CVVResponse
+	}{
+		{CVVResponseMatched, sleet.CVVResponseMatch},
+		{CVVResponseFailed, sleet.CVVResponseNoMatch},
+		{CVVResponseNotValid, sleet.CVVResponseSuspicious},
+		{CVVResponseCVDMissing, sleet.CVVResponseNotProcessed},
+		{"", sleet.CVVResponseUnknown},
+		{"Fake Result", sleet.CVVResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateCvv(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslatePaymentResponse(t *testing.T) {
+	base := func() *nas.PaymentResponse {
+		response := &nas.PaymentResponse{
+			Id:              "pay_123",
+			Approved:        true,
+			Status:          payments.Authorized,
+			AuthCode:        "770687",
+			ResponseCode:    "10000",
+			ResponseSummary: "Approved",
+			SchemeId:        "638284745624527",
+			Source: &nas.SourceResponse{ResponseCardSource: &nas.ResponseCardSource{
+				AvsCheck: "Y",
+				CvvCheck: "Y",
+			}},
+		}
+		response.HttpMetadata.StatusCode = 201
+		return response
+	}
+
+	t.Run("Approved", func(t *testing.T) {
+		want := &sleet.AuthorizationResponse{
+			Success:              true,
+			TransactionReference: "pay_123",
+			AvsResult:            sleet.AVSResponseZip5MatchAddressMatch,
+			CvvResult:            sleet.CVVResponseMatch,
+			AvsResultRaw:         "Y",
+			CvvResultRaw:         "Y",
+			Response:             "10000",
+			Message:              "Approved",
+			StatusCode:           201,
+			NetworkTransactionID: "638284745624527",
+			NetworkResponseCode:  "00",
+			ResultType:           sleet.ResultTypeSuccess,
+			Metadata: map[string]string{
+				sleet.AuthCodeMetadata:     "770687",
+				sleet.ResponseCodeMetadata: "10000",
+			},
+		}
+		if diff := deep.Equal(translatePaymentResponse(base(), sleet.CreditCardNetworkVisa), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		response := base()
+		response.Approved = false
+		response.Status = payments.Declined
+		response.AuthCode = ""
+		response.ResponseCode = "20051"
+		response.ResponseSummary = "Insufficient Funds"
+		response.Source.ResponseCardSource.CvvCheck = "N"
+
+		want := &sleet.AuthorizationResponse{
+			Success:              false,
+			TransactionReference: "pay_123",
+			AvsResult:            sleet.AVSResponseZip5MatchAddressMatch,
+			CvvResult:            sleet.CVVResponseNoMatch,
+			AvsResultRaw:         "Y",
+			CvvResultRaw:         "N",
+			Response:             "20051",
+			Message:              "Insufficient Funds",
+			ErrorCode:            "20051",
+			StatusCode:           201,
+			NetworkTransactionID: "638284745624527",
+			NetworkResponseCode:  "51",
+			ResultType:           sleet.ResultTypePaymentError,
+			DeclineReason:        sleet.DeclineReasonInsufficientFunds,
+			RetryAdvice:          translateRetryAdvice("20051", sleet.DeclineReasonInsufficientFunds, sleet.CreditCardNetworkVisa),
+			Metadata:             map[string]string{sleet.ResponseCodeMetadata: "20051"},
+		}
+		if diff := deep.Equal(translatePaymentResponse(response, sleet.CreditCardNetworkVisa), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		response := base()
+		response.Approved = false
+		response.Status = payments.Pending
+		response.Source = nil
+
+		got := translatePaymentResponse(response, sleet.CreditCardNetworkVisa)
+		if got.Success || got.ResultType != sleet.ResultTypePending || got.PendingReason != sleet.PendingReasonAsyncProcessing {
+			t.Errorf("expected pending response, got %+v", got)
+		}
+		if got.DeclineReason != sleet.DeclineReasonNone || got.RetryAdvice != nil {
+			t.Errorf("expected no decline data, got %v, %+v", got.DeclineReason, got.RetryAdvice)
+		}
+		if got.AvsResult != sleet.AVSResponseUnknown || got.CvvResult != sleet.CVVResponseUnknown {
+			t.Errorf("expected unknown checks without a card source, got %v, %v", got.AvsResult, got.CvvResult)
+		}
+	})
+
+	t.Run("Pending with 3DS redirect", func(t *testing.T) {
+		response := base()
+		response.Approved = false
+		response.Status = payments.Pending
+		redirect := "https://3ds2-sandbox.ckotech.co/interceptor/3ds_123"
+		response.Links = map[string]checkout_com_common.Link{redirectLink: {HRef: &redirect}}
+
+		got := translatePaymentResponse(response, sleet.CreditCardNetworkVisa)
+		if got.Success || got.ResultType != sleet.ResultTypePaymentError || got.DeclineReason != sleet.DeclineReasonAuthenticationRequired {
+			t.Errorf("expected authentication required decline, got %+v", got)
+		}
+		if got.PendingReason != sleet.PendingReasonNone {
+			t.Errorf("expected no pending reason, got %v", got.PendingReason)
+		}
+	})
+}
+
+func TestTranslateNetworkResponseCode(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"10000", "00"},
+		{"10008", "08"},
+		{"20051", "51"},
+		{"200N7", "N7"},
+		{"30043", "43"},
+		{"20154", ""},
+		{"40101", ""},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := translateNetworkResponseCode(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateRetryAdvice(t *testing.T) {
+	visaAdvice, _ := sleet.RetryAdviceFromVisaResponseCode("51")
+
+	cases := []struct {
+		label        string
+		responseCode string
+		reason       sleet.DeclineReason
+		network      sleet.CreditCardNetwork
+		want         *sleet.RetryAdvice
+	}{
+		{
+			"Visa decline category",
+			"20051",
+			sleet.DeclineReasonInsufficientFunds,
+			sleet.CreditCardNetworkVisa,
+			visaAdvice,
+		},
+		{
+			"Soft decline group",
+			"20051",
+			sleet.DeclineReasonInsufficientFunds,
+			sleet.CreditCardNetworkMastercard,
+			sleet.RetryAdviceFromDeclineReason(sleet.DeclineReasonInsufficientFunds),
+		},
+		{
+			"Hard decline group",
+			"30043",
+			sleet.DeclineReasonLostOrStolenCard,
+			sleet.CreditCardNetworkMastercard,
+			&sleet.RetryAdvice{
+				DeclineType: sleet.DeclineTypeHard,
+				Source:      sleet.RetryAdviceSourceGateway,
+				Code:        "30043",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := translateRetryAdvice(c.responseCode, c.reason, c.network)
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorResultType(t *testing.T) {
+	cases := []struct {
+		label string
+		in    error
+		want  sleet.ResultType
+	}{
+		{"Validation error", checkouterrors.CheckoutAPIError{StatusCode: 422}, sleet.ResultTypeAPIError},
+		{"Server error", checkouterrors.CheckoutAPIError{StatusCode: 502}, sleet.ResultTypeServerError},
+		{"Network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, sleet.ResultTypeServerError},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := translateErrorResultType(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
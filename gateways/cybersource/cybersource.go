@@ -18,16 +18,20 @@ import (
 )
 
 const (
-	authPath = "/pts/v2/payments/"
+	authPath   = "/pts/v2/payments/"
+	searchPath = "/tss/v2/searches"
 )
 
 var (
 	// assert client interface
-	_ sleet.ClientWithContext = &CybersourceClient{}
+	_ sleet.ClientWithContext        = &CybersourceClient{}
+	_ sleet.TransactionDetailsClient = &CybersourceClient{}
+	_ sleet.TransactionLookupClient  = &CybersourceClient{}
 )
 
 // CybersourceClient represents an HTTP client and the associated authentication information required for making an API request.
 type CybersourceClient struct {
+	baseURL           string
 	host              string
 	merchantID        string
 	sharedSecretKeyID string
@@ -36,15 +40,17 @@ type CybersourceClient struct {
 }
 
 // NewClient returns a new client for making CyberSource API requests for a given merchant using a specified authentication key.
-func NewClient(env common.Environment, merchantID string, sharedSecretKeyID string, sharedSecretKey string) *CybersourceClient {
-	return NewWithHttpClient(env, merchantID, sharedSecretKeyID, sharedSecretKey, common.DefaultHttpClient())
+func NewClient(env common.Environment, merchantID string, sharedSecretKeyID string, sharedSecretKey string, options ...common.ClientOption) *CybersourceClient {
+	return NewWithHttpClient(env, merchantID, sharedSecretKeyID, sharedSecretKey, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient returns a client for making CyberSource API requests for a given merchant using a specified authentication key.
 // The given HTTP client will be used to make the requests.
-func NewWithHttpClient(env common.Environment, merchantID string, sharedSecretKeyID string, sharedSecretKey string, httpClient *http.Client) *CybersourceClient {
+func NewWithHttpClient(env common.Environment, merchantID string, sharedSecretKeyID string, sharedSecretKey string, httpClient *http.Client, options ...common.ClientOption) *CybersourceClient {
+	baseURL := common.NewClientOptions(options...).BaseURLOr(cybersourceBaseURL(env))
 	return &CybersourceClient{
-		host:              cybersourceHost(env),
+		baseURL:           baseURL,
+		host:              hostOf(baseURL),
 		merchantID:        merchantID,
 		sharedSecretKeyID: sharedSecretKeyID,
 		sharedSecretKey:   sharedSecretKey,
@@ -65,6 +71,13 @@ func (client *CybersourceClient) Authorize(request *sleet.AuthorizationRequest)
 // a CustomerReference, the ClientReferenceInformation of this request will be overridden in order to to match the
 // level 3 data's CustomerReference.
 func (client *CybersourceClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	request, err := sleet.PrepareLevel3Data(request)
+	if err != nil {
+		return nil, err
+	}
 	cybersourceAuthRequest, err := buildAuthRequest(request)
 	if err != nil {
 		return nil, err
@@ -79,11 +92,13 @@ func (client *CybersourceClient) AuthorizeWithContext(ctx context.Context, reque
 	// Status 400 or 502 - Failed
 	if cybersourceResponse.ErrorReason != nil {
 		response := sleet.AuthorizationResponse{
-			Success:    false,
-			ErrorCode:  *cybersourceResponse.ErrorReason,
-			StatusCode: httpResponse.StatusCode,
-			Header:     responseHeader,
+			Success:       false,
+			ErrorCode:     *cybersourceResponse.ErrorReason,
+			StatusCode:    httpResponse.StatusCode,
+			Header:        responseHeader,
+			DeclineReason: translateDeclineReason(*cybersourceResponse.ErrorReason),
 		}
+		response.RetryAdvice = sleet.RetryAdviceFromDeclineReason(response.DeclineReason)
 		return &response, nil
 	}
 	// Status 401 - during a cybersource outage, most fields were empty and ID was nil
@@ -96,10 +111,9 @@ func (client *CybersourceClient) AuthorizeWithContext(ctx context.Context, reque
 	if cybersourceResponse.ErrorInformation != nil {
 		errorCode = cybersourceResponse.ErrorInformation.Reason
 	}
-	success := false // DECLINED, INVALID_REQUEST
-	if cybersourceResponse.Status == "AUTHORIZED" || cybersourceResponse.Status == "PARTIAL_AUTHORIZED" || cybersourceResponse.Status == "AUTHORIZED_PENDING_REVIEW" || cybersourceResponse.Status == "PENDING_REVIEW" {
-		success = true
-	}
+	resultType, pendingReason := translateStatus(cybersourceResponse.Status)
+	success := resultType == sleet.ResultTypeSuccess // DECLINED, INVALID_REQUEST and pending statuses are not approved
+	declined := !success && resultType != sleet.ResultTypePending
 
 	response := &sleet.AuthorizationResponse{
 		Success:              success,
@@ -109,6 +123,13 @@ func (client *CybersourceClient) AuthorizeWithContext(ctx context.Context, reque
 		StatusCode:           httpResponse.StatusCode,
 		Header:               responseHeader,
 	}
+	if declined {
+		response.DeclineReason = translateDeclineReason(errorCode)
+	}
+	if resultType == sleet.ResultTypePending {
+		response.ResultType = resultType
+		response.PendingReason = pendingReason
+	}
 	if cybersourceResponse.ProcessorInformation != nil {
 		response.AvsResult = translateAvs(cybersourceResponse.ProcessorInformation.AVS.Code)
 		response.AvsResultRaw = cybersourceResponse.ProcessorInformation.AVS.Code
@@ -116,6 +137,17 @@ func (client *CybersourceClient) AuthorizeWithContext(ctx context.Context, reque
 		response.CvvResultRaw = cybersourceResponse.ProcessorInformation.CardVerification.ResultCode
 		response.ExternalTransactionID = cybersourceResponse.ProcessorInformation.TransactionID
 		response.Metadata = buildResponseMetadata(*cybersourceResponse.ProcessorInformation)
+		response.NetworkResponseCode = cybersourceResponse.ProcessorInformation.ResponseCode
+		response.MerchantAdviceCode = translateMerchantAdviceCode(*cybersourceResponse.ProcessorInformation)
+		response.NetworkTransactionID = cybersourceResponse.ProcessorInformation.NetworkTransactionID
+	}
+	if declined {
+		response.RetryAdvice = sleet.NewRetryAdvice(
+			response.DeclineReason,
+			request.CreditCard.Network,
+			response.MerchantAdviceCode,
+			response.NetworkResponseCode,
+		)
 	}
 	if cybersourceResponse.TokenInformation != nil {
 		response.CreatedTokens = buildCreatedTokens(*cybersourceResponse.TokenInformation)
@@ -197,6 +229,9 @@ func (client *CybersourceClient) Void(request *sleet.VoidRequest) (*sleet.VoidRe
 // VoidWithContext cancels a CyberSource payment. If successful, the void response will be returned. A previously voided
 // payment or one that has already been settled cannot be voided.
 func (client *CybersourceClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	if request.TransactionReference == "" {
 		return nil, errors.New("TransactionReference given to void request is empty")
 	}
@@ -233,6 +268,9 @@ func (client *CybersourceClient) Refund(request *sleet.RefundRequest) (*sleet.Re
 // RefundWithContext refunds a CyberSource payment. If successful, the refund response will be returned. Multiple
 // refunds can be made on the same payment, but the total amount refunded should not exceed the payment total.
 func (client *CybersourceClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	if request.TransactionReference == "" {
 		return nil, errors.New("TransactionReference given to refund request is empty")
 	}
@@ -260,6 +298,61 @@ func (client *CybersourceClient) RefundWithContext(ctx context.Context, request
 	return &sleet.RefundResponse{Success: true, TransactionReference: *cybersourceResponse.ID}, nil
 }
 
+// GetTransactionDetails retrieves the status of a payment, used to follow up on authorizations held for review by
+// Decision Manager
+func (client *CybersourceClient) GetTransactionDetails(request *sleet.TransactionDetailsRequest) (*sleet.TransactionDetailsResponse, error) {
+	return client.GetTransactionDetailsWithContext(context.TODO(), request)
+}
+
+// GetTransactionDetailsWithContext retrieves the status of a payment, used to follow up on authorizations held for
+// review by Decision Manager
+func (client *CybersourceClient) GetTransactionDetailsWithContext(ctx context.Context, request *sleet.TransactionDetailsRequest) (*sleet.TransactionDetailsResponse, error) {
+	req, err := client.buildGETRequest(ctx, authPath+request.TransactionReference)
+	if err != nil {
+		return nil, err
+	}
+	var cybersourceResponse Response
+	if _, err = client.doRequest(req, &cybersourceResponse); err != nil {
+		return nil, err
+	}
+	if cybersourceResponse.ErrorReason != nil {
+		return &sleet.TransactionDetailsResponse{ResultCode: *cybersourceResponse.ErrorReason}, nil
+	}
+
+	resultType, pendingReason := translateStatus(cybersourceResponse.Status)
+	return &sleet.TransactionDetailsResponse{
+		ResultCode:    cybersourceResponse.Status,
+		Status:        cybersourceResponse.Status,
+		ResultType:    resultType,
+		PendingReason: pendingReason,
+	}, nil
+}
+
+// LookupTransactionWithContext searches the transaction whose outcome is unknown by the client reference code sent for
+// its MerchantOrderReference. The applications of the most recent transaction tell whether it was authorized and
+// captured.
+func (client *CybersourceClient) LookupTransactionWithContext(ctx context.Context, request *sleet.TransactionLookupRequest) (*sleet.TransactionLookupResponse, error) {
+	if request.MerchantOrderReference == "" {
+		return &sleet.TransactionLookupResponse{Found: false}, nil
+	}
+	payload, err := json.Marshal(buildSearchRequest(request.MerchantOrderReference))
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.buildPOSTRequest(ctx, searchPath, payload)
+	if err != nil {
+		return nil, err
+	}
+	var searchResponse SearchResponse
+	if _, err = client.doRequest(req, &searchResponse); err != nil {
+		return nil, err
+	}
+	if searchResponse.ErrorReason != nil {
+		return nil, fmt.Errorf("cybersource transaction search failed: %s", *searchResponse.ErrorReason)
+	}
+	return translateTransactionSummaries(searchResponse.Embedded.TransactionSummaries), nil
+}
+
 // sendRequest sends an API request with the give payload to the specified CyberSource endpoint.
 // If the request is successfully sent, its response message will be returned.
 func (client *CybersourceClient) sendRequest(ctx context.Context, path string, data *Request) (*Response, *http.Response, error) {
@@ -271,10 +364,20 @@ func (client *CybersourceClient) sendRequest(ctx context.Context, path string, d
 	if err != nil {
 		return nil, nil, err
 	}
+	var cybersourceResponse Response
+	resp, err := client.doRequest(req, &cybersourceResponse)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &cybersourceResponse, resp, nil
+}
+
+// doRequest sends a signed request and decodes the cybersource response into out
+func (client *CybersourceClient) doRequest(req *http.Request, out interface{}) (*http.Response, error) {
 	req.Header.Add("User-Agent", common.UserAgent())
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer func() {
 		err := resp.Body.Close()
@@ -285,21 +388,49 @@ func (client *CybersourceClient) sendRequest(ctx context.Context, path string, d
 
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	var cybersourceResponse Response
-	err = json.Unmarshal(respBody, &cybersourceResponse)
+	if err = json.Unmarshal(respBody, out); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// buildGETRequest creates a signed HTTP request for the specified endpoint. GET requests have no body, so the digest
+// is left out of the signature.
+func (client *CybersourceClient) buildGETRequest(ctx context.Context, path string) (*http.Request, error) {
+	url := client.baseURL + path
+	now := time.Now().UTC().Format(time.RFC1123Z)
+	sig := "host: " + client.host + "\ndate: " + now + "\n(request-target): get " + path + "\nv-c-merchant-id: " + client.merchantID
+	decodedSecret, err := base64.StdEncoding.DecodeString(client.sharedSecretKey)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return &cybersourceResponse, resp, nil
+	hmacSha256 := hmac.New(sha256.New, decodedSecret)
+	hmacSha256.Write([]byte(sig))
+	signature := base64.StdEncoding.EncodeToString(hmacSha256.Sum(nil))
+
+	headers := "host date (request-target) v-c-merchant-id"
+	signatureHeader := fmt.Sprintf(`keyid="%s",algorithm="%s",headers="%s",signature="%s"`, client.sharedSecretKeyID, "HmacSHA256", headers, signature)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("v-c-merchant-id", client.merchantID)
+	req.Header.Add("Host", client.host)
+	req.Header.Add("Date", now)
+	req.Header.Add("Signature", signatureHeader)
+
+	return req, nil
 }
 
 // buildPOSTRequest creates an HTTP request for a given payload destined for a specified endpoint.
 // The HTTP request will be returned signed and ready to send, and its body and existing headers
 // should not be modified.
 func (client *CybersourceClient) buildPOSTRequest(ctx context.Context, path string, data []byte) (*http.Request, error) {
-	url := "https://" + client.host + path // weird thing where we need path to include forward /
+	url := client.baseURL + path // weird thing where we need path to include forward /
 
 	// Create request digest and signature
 	payloadHash := sha256.Sum256(data)
@@ -0,0 +1,84 @@
+package cybersource
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+func TestBaseURLOverride(t *testing.T) {
+	cases := []struct {
+		label    string
+		options  []common.ClientOption
+		wantURL  string
+		wantHost string
+	}{
+		{"Environment", nil, "https://apitest.cybersource.com/pts/v2/payments/", "apitest.cybersource.com"},
+		{"Stand-in", []common.ClientOption{common.WithBaseURL("http://localhost:8080/")}, "http://localhost:8080/pts/v2/payments/", "localhost:8080"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			client := NewClient(common.Sandbox, "merchant", "key", "c2VjcmV0", c.options...)
+			req, err := client.buildPOSTRequest(context.Background(), authPath, []byte("{}"))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := req.URL.String(); got != c.wantURL {
+				t.Errorf("Got %q, want %q", got, c.wantURL)
+			}
+			if got := req.Header.Get("Host"); got != c.wantHost {
+				t.Errorf("Got %q, want %q", got, c.wantHost)
+			}
+		})
+	}
+}
+
+// statusTransport answers every request with a payment in the given status
+type statusTransport struct {
+	status string
+}
+
+func (transport statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{"id": "6134587524586085504004", "status": "` + transport.status + `", "errorInformation": {"reason": ""}}`
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestAuthorizePending(t *testing.T) {
+	cases := []struct {
+		status     string
+		wantReason sleet.PendingReason
+	}{
+		{"PENDING", sleet.PendingReasonAsyncProcessing},
+		{"PENDING_REVIEW", sleet.PendingReasonFraudReview},
+		{"AUTHORIZED_PENDING_REVIEW", sleet.PendingReasonFraudReview},
+	}
+
+	for _, c := range cases {
+		t.Run(c.status, func(t *testing.T) {
+			client := NewWithHttpClient(common.Sandbox, "merchant", "key", "c2VjcmV0", &http.Client{Transport: statusTransport{c.status}})
+			got, err := client.Authorize(sleet_testing.BaseAuthorizationRequest())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			// pending authorizations are not approved yet and carry no decline data
+			if got.Success || got.ResultType != sleet.ResultTypePending || got.PendingReason != c.wantReason {
+				t.Errorf("expected pending response, got %+v", got)
+			}
+			if got.DeclineReason != sleet.DeclineReasonNone || got.RetryAdvice != nil {
+				t.Errorf("expected no decline data, got %v, %+v", got.DeclineReason, got.RetryAdvice)
+			}
+		})
+	}
+}
@@ -1,12 +1,23 @@
 package cybersource
 
 import (
+	"net/url"
+
 	"github.com/BoltApp/sleet/common"
 )
 
-func cybersourceHost(env common.Environment) string {
+func cybersourceBaseURL(env common.Environment) string {
 	if env == common.Production {
-		return "api.cybersource.com"
+		return "https://api.cybersource.com"
+	}
+	return "https://apitest.cybersource.com"
+}
+
+// hostOf returns the host of baseURL, which requests are signed for
+func hostOf(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
 	}
-	return "apitest.cybersource.com"
+	return parsed.Host
 }
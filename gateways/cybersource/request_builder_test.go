@@ -532,6 +532,8 @@ func TestBuildAuthRequest(t *testing.T) {
 func TestBuildCaptureRequest(t *testing.T) {
 	base := sleet_testing.BaseCaptureRequest()
 	base.MerchantOrderReference = common.SPtr("cart_display_id")
+	withLevel2 := sleet_testing.BaseCaptureRequest()
+	withLevel2.Level2Data = sleet_testing.BaseLevel2Data()
 
 	cases := []struct {
 		label string
@@ -559,6 +561,31 @@ func TestBuildCaptureRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Capture Request with Level 2 data",
+			withLevel2,
+			&Request{
+				OrderInformation: &OrderInformation{
+					AmountDetails: AmountDetails{
+						Amount:    "1.00",
+						Currency:  "USD",
+						TaxAmount: "8.25",
+					},
+					InvoiceDetails: &InvoiceDetails{
+						PurchaseOrderNumber: "PO-12345",
+						Taxable:             common.BPtr(true),
+					},
+					ShippingDetails: &ShippingFromInfo{ShipFromPostalCode: "94105"},
+				},
+				MerchantInformation: &MerchantInformation{VATRegistrationNumber: "12-3456789"},
+				MerchantDefinedInformation: []MerchantDefinedInformation{
+					{
+						Key:   "1",
+						Value: *withLevel2.ClientTransactionReference,
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -709,3 +736,34 @@ func TestBuildCaptureRequestWithOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSearchRequest(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"order-123", `clientReferenceInformation.code:"order-123"`},
+		{"order 123 OR *", `clientReferenceInformation.code:"order 123 OR *"`},
+		{`order "123"`, `clientReferenceInformation.code:"order \"123\""`},
+		{`order\`, `clientReferenceInformation.code:"order\\"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := buildSearchRequest(c.in).Query
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBuildAuthRequestUnknownUnitOfMeasure(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Level3Data.LineItems[0].UnitOfMeasure = "bushel of apples"
+
+	if _, err := buildAuthRequest(request); err == nil {
+		t.Error("expected error for an unknown unit of measure")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"strconv"
+	"strings"
 
 	"github.com/BoltApp/sleet"
 	"github.com/BoltApp/sleet/common"
@@ -20,6 +21,8 @@ const (
 	AmexCryptogramSplitLength = 20
 	TransactionTypeInApp      = "1"
 	PaymentSolutionApplepay   = "001"
+
+	maxPurchaseOrderNumberLength = 25
 )
 
 // Options
@@ -104,7 +107,8 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest) (*Request, error)
 				Locality:   *authRequest.BillingAddress.Locality,
 				AdminArea:  *authRequest.BillingAddress.RegionCode,
 				Country:    common.SafeStr(authRequest.BillingAddress.CountryCode),
-				Email:      common.SafeStr(authRequest.BillingAddress.Email),
+				Phone:      common.SafeStr(common.NormalizePhoneNumber(authRequest.BillingAddress.PhoneNumber, authRequest.BillingAddress.CountryCode, common.PhoneNumberFormatE164)),
+				Email:      common.SafeStr(common.NormalizeEmail(authRequest.BillingAddress.Email)),
 				Company:    common.SafeStr(authRequest.BillingAddress.Company),
 			},
 		},
@@ -143,6 +147,10 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest) (*Request, error)
 		request.OrderInformation.AmountDetails.FreightAmount = sleet.AmountToString(&level3.ShippingAmount)
 		request.OrderInformation.AmountDetails.DutyAmount = sleet.AmountToString(&level3.DutyAmount)
 		for _, lineItem := range level3.LineItems {
+			unitOfMeasure, err := common.LookupOptionalUnitOfMeasurementCode(lineItem.UnitOfMeasure)
+			if err != nil {
+				return nil, err
+			}
 			request.OrderInformation.LineItems = append(request.OrderInformation.LineItems, LineItem{
 				ProductCode:    lineItem.ProductCode,
 				ProductName:    lineItem.Description,
@@ -150,13 +158,15 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest) (*Request, error)
 				UnitPrice:      sleet.AmountToString(&lineItem.UnitPrice),
 				TotalAmount:    sleet.AmountToString(&lineItem.TotalAmount),
 				DiscountAmount: sleet.AmountToString(&lineItem.ItemDiscountAmount),
-				UnitOfMeasure:  lineItem.UnitOfMeasure,
+				UnitOfMeasure:  unitOfMeasure,
 				CommodityCode:  lineItem.CommodityCode,
 				TaxAmount:      sleet.AmountToString(&lineItem.ItemTaxAmount),
 			})
 		}
 	}
 
+	addLevel2Data(request, authRequest.Level2Data)
+
 	if authRequest.Options[sleet.CyberSourceTokenizeOption] != nil {
 		// It is on purpose that we set the TokenCreate option even if none of the token types convert successfully
 		// or if there are no token types provided. CyberSource offers a feature where a default token type to create is
@@ -201,6 +211,7 @@ func buildCaptureRequest(captureRequest *sleet.CaptureRequest) (*Request, error)
 			Value: *captureRequest.ClientTransactionReference,
 		})
 	}
+	addLevel2Data(request, captureRequest.Level2Data)
 	captureSeqNum, ok := captureRequest.Options[captureSequenceNumber]
 	if ok {
 		totalCapCount, ok := captureRequest.Options[totalCaptureCount]
@@ -257,6 +268,25 @@ func buildRefundRequest(refundRequest *sleet.RefundRequest) (*Request, error) {
 	return request, nil
 }
 
+// addLevel2Data sets the purchase order, tax and merchant fields used for Level 2 processing. The Level 2 tax amount
+// takes precedence over the one from Level 3 data.
+func addLevel2Data(request *Request, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	request.OrderInformation.AmountDetails.TaxAmount = sleet.AmountToDecimalString(&level2.TaxAmount)
+	request.OrderInformation.InvoiceDetails = &InvoiceDetails{
+		PurchaseOrderNumber: sleet.TruncateString(level2.PurchaseOrderNumber, maxPurchaseOrderNumberLength),
+		Taxable:             common.BPtr(!level2.TaxExempt),
+	}
+	if level2.ShipFromPostalCode != "" {
+		request.OrderInformation.ShippingDetails = &ShippingFromInfo{ShipFromPostalCode: level2.ShipFromPostalCode}
+	}
+	if level2.MerchantTaxID != "" {
+		request.MerchantInformation = &MerchantInformation{VATRegistrationNumber: level2.MerchantTaxID}
+	}
+}
+
 func buildApplepayRequest(authRequest *sleet.AuthorizationRequest, request *Request) error {
 	request.PaymentInformation = &PaymentInformation{
 		TokenizedCard: &TokenizedCard{
@@ -322,3 +352,23 @@ func getAmexConsumerAuthInfo(cryptogram string) (*ConsumerAuthenticationInformat
 		Cavv: cryptogram,
 	}, nil
 }
+
+// searchLimit is the number of most recent transactions returned by a search, enough for an authorization and its
+// captures
+const searchLimit = 10
+
+// searchQueryEscaper escapes the characters that end or escape a quoted value of a search query
+var searchQueryEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// buildSearchRequest searches the transactions of merchantOrderReference. The reference is quoted, so that spaces and
+// query operators in it are matched literally.
+func buildSearchRequest(merchantOrderReference string) *SearchRequest {
+	return &SearchRequest{
+		Save:     false,
+		Timezone: "UTC",
+		Query:    `clientReferenceInformation.code:"` + searchQueryEscaper.Replace(merchantOrderReference) + `"`,
+		Offset:   0,
+		Limit:    searchLimit,
+		Sort:     "submitTimeUtc:desc",
+	}
+}
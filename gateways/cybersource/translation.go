@@ -78,3 +78,150 @@ func translateTokenType(sleetTokenType sleet.TokenType) (ProcessingActionTokenTy
 	cybersourceTokenType, ok := tokenTypeMap[sleetTokenType]
 	return cybersourceTokenType, ok
 }
+
+// declineReasonMap translates the errorInformation.reason of declined payments
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"AVS_FAILED":                       sleet.DeclineReasonAVSFailure,
+	"CONTACT_PROCESSOR":                sleet.DeclineReasonCallIssuer,
+	"EXPIRED_CARD":                     sleet.DeclineReasonExpiredCard,
+	"PROCESSOR_DECLINED":               sleet.DeclineReasonDoNotHonor,
+	"INSUFFICIENT_FUND":                sleet.DeclineReasonInsufficientFunds,
+	"STOLEN_LOST_CARD":                 sleet.DeclineReasonLostOrStolenCard,
+	"ISSUER_UNAVAILABLE":               sleet.DeclineReasonIssuerUnavailable,
+	"UNAUTHORIZED_CARD":                sleet.DeclineReasonRestrictedCard,
+	"CVN_NOT_MATCH":                    sleet.DeclineReasonCVVFailure,
+	"INVALID_CVN":                      sleet.DeclineReasonCVVFailure,
+	"CV_FAILED":                        sleet.DeclineReasonCVVFailure,
+	"EXCEEDS_CREDIT_LIMIT":             sleet.DeclineReasonLimitExceeded,
+	"ALLOWABLE_PIN_RETRIES_EXCEEDED":   sleet.DeclineReasonLimitExceeded,
+	"BLACKLISTED_CUSTOMER":             sleet.DeclineReasonSuspectedFraud,
+	"CUSTOMER_WATCHLIST_MATCH":         sleet.DeclineReasonSuspectedFraud,
+	"DECISION_PROFILE_REJECT":          sleet.DeclineReasonSuspectedFraud,
+	"SCORE_EXCEEDS_THRESHOLD":          sleet.DeclineReasonSuspectedFraud,
+	"SUSPENDED_ACCOUNT":                sleet.DeclineReasonRestrictedCard,
+	"PAYMENT_REFUSED":                  sleet.DeclineReasonDoNotHonor,
+	"GENERAL_DECLINE":                  sleet.DeclineReasonDoNotHonor,
+	"INVALID_ACCOUNT":                  sleet.DeclineReasonInvalidCardNumber,
+	"CARD_TYPE_NOT_ACCEPTED":           sleet.DeclineReasonTransactionNotPermitted,
+	"INVALID_MERCHANT_CONFIGURATION":   sleet.DeclineReasonTransactionNotPermitted,
+	"INVALID_AMOUNT":                   sleet.DeclineReasonInvalidAmount,
+	"DUPLICATE_REQUEST":                sleet.DeclineReasonDuplicateTransaction,
+	"CONSUMER_AUTHENTICATION_REQUIRED": sleet.DeclineReasonAuthenticationRequired,
+	"CONSUMER_AUTHENTICATION_FAILED":   sleet.DeclineReasonAuthenticationRequired,
+	"PENDING_AUTHENTICATION":           sleet.DeclineReasonAuthenticationRequired,
+	"PROCESSOR_ERROR":                  sleet.DeclineReasonProcessingError,
+	"SYSTEM_ERROR":                     sleet.DeclineReasonProcessingError,
+	"SERVER_TIMEOUT":                   sleet.DeclineReasonProcessingError,
+	"SERVICE_TIMEOUT":                  sleet.DeclineReasonProcessingError,
+}
+
+// translateDeclineReason converts a CyberSource error reason to its equivalent Sleet decline reason.
+func translateDeclineReason(reason string) sleet.DeclineReason {
+	sleetReason, ok := declineReasonMap[reason]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return sleetReason
+}
+
+// translateMerchantAdviceCode prefers the code forwarded unchanged from the processor over the code normalized by
+// CyberSource
+func translateMerchantAdviceCode(processorInformation ProcessorInformation) string {
+	if processorInformation.MerchantAdvice.CodeRaw != "" {
+		return processorInformation.MerchantAdvice.CodeRaw
+	}
+	return processorInformation.MerchantAdvice.Code
+}
+
+var statusMap = map[string]sleet.ResultType{
+	"AUTHORIZED":                sleet.ResultTypeSuccess,
+	"PARTIAL_AUTHORIZED":        sleet.ResultTypeSuccess,
+	"AUTHORIZED_PENDING_REVIEW": sleet.ResultTypePending,
+	"PENDING_REVIEW":            sleet.ResultTypePending,
+	"PENDING":                   sleet.ResultTypePending,
+	"DECLINED":                  sleet.ResultTypePaymentError,
+	"AUTHORIZED_RISK_DECLINED":  sleet.ResultTypePaymentError,
+	"INVALID_REQUEST":           sleet.ResultTypeAPIError,
+}
+
+// translateStatus converts a cybersource payment status to a Sleet result type. Payments are pending while
+// Decision Manager holds them for review, except for the PENDING status of asynchronous payments.
+func translateStatus(status string) (sleet.ResultType, sleet.PendingReason) {
+	resultType, ok := statusMap[status]
+	if !ok {
+		return sleet.ResultTypeUnknownError, sleet.PendingReasonNone
+	}
+	switch {
+	case status == "PENDING":
+		return resultType, sleet.PendingReasonAsyncProcessing
+	case resultType == sleet.ResultTypePending:
+		return resultType, sleet.PendingReasonFraudReview
+	}
+	return resultType, sleet.PendingReasonNone
+}
+
+// Transaction Search Service application names and reply codes
+const (
+	applicationAuth     = "ics_auth"
+	applicationCapture  = "ics_bill"
+	applicationDecision = "ics_decision"
+	rCodeSuccess        = "1"
+	rCodeDeclined       = "0"
+	rFlagReview         = "DREVIEW"
+)
+
+// translateTransactionSummaries converts the transactions found by a search, most recent first, to the state of the
+// most recent authorization. The authorization is captured if any of the transactions ran a successful capture.
+func translateTransactionSummaries(summaries []TransactionSummary) *sleet.TransactionLookupResponse {
+	var response *sleet.TransactionLookupResponse
+	captured := false
+	for _, summary := range summaries {
+		for _, application := range summary.ApplicationInformation.Applications {
+			if application.Name == applicationCapture && application.RCode == rCodeSuccess {
+				captured = true
+			}
+		}
+		if response == nil {
+			response = translateAuthApplications(summary)
+		}
+	}
+	if response == nil {
+		return &sleet.TransactionLookupResponse{Found: false}
+	}
+	response.Captured = captured
+	return response
+}
+
+// translateAuthApplications returns the state of the authorization run by the transaction, nil if it ran none
+func translateAuthApplications(summary TransactionSummary) *sleet.TransactionLookupResponse {
+	var auth, decision *Application
+	for i, application := range summary.ApplicationInformation.Applications {
+		switch application.Name {
+		case applicationAuth:
+			auth = &summary.ApplicationInformation.Applications[i]
+		case applicationDecision:
+			decision = &summary.ApplicationInformation.Applications[i]
+		}
+	}
+	if auth == nil {
+		return nil
+	}
+
+	response := &sleet.TransactionLookupResponse{
+		Found:                true,
+		TransactionReference: summary.ID,
+		ResultType:           sleet.ResultTypeUnknownError,
+		Status:               auth.RFlag,
+	}
+	switch {
+	case decision != nil && decision.RFlag == rFlagReview:
+		response.ResultType = sleet.ResultTypePending
+		response.PendingReason = sleet.PendingReasonFraudReview
+		response.Status = decision.RFlag
+	case auth.RCode == rCodeSuccess:
+		response.ResultType = sleet.ResultTypeSuccess
+	case auth.RCode == rCodeDeclined:
+		response.ResultType = sleet.ResultTypePaymentError
+	}
+	return response
+}
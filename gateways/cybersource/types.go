@@ -27,6 +27,7 @@ type Request struct {
 	PaymentInformation                *PaymentInformation                `json:"paymentInformation,omitempty"`
 	MerchantDefinedInformation        []MerchantDefinedInformation       `json:"merchantDefinedInformation,omitempty"`
 	ConsumerAuthenticationInformation *ConsumerAuthenticationInformation `json:"consumerAuthenticationInformation,omitempty"`
+	MerchantInformation               *MerchantInformation               `json:"merchantInformation,omitempty"`
 }
 
 // Response contains all of the fields for all Cybersource API call responses
@@ -46,6 +47,41 @@ type Response struct {
 	Details                    *[]Detail                   `json:"details,omitempty"`
 }
 
+// SearchRequest queries the Transaction Search Service
+type SearchRequest struct {
+	Save     bool   `json:"save"`
+	Timezone string `json:"timezone"`
+	Query    string `json:"query"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Sort     string `json:"sort"`
+}
+
+// SearchResponse contains the transactions matching a search query
+type SearchResponse struct {
+	Embedded struct {
+		TransactionSummaries []TransactionSummary `json:"transactionSummaries"`
+	} `json:"_embedded"`
+	ErrorReason *string `json:"reason,omitempty"`
+}
+
+// TransactionSummary describes a transaction found by a search and the applications (auth, capture, decision) it ran
+type TransactionSummary struct {
+	ID                     string `json:"id"`
+	SubmitTimeUTC          string `json:"submitTimeUtc"`
+	ApplicationInformation struct {
+		Applications []Application `json:"applications"`
+	} `json:"applicationInformation"`
+}
+
+// Application is the result of one service run for a transaction
+type Application struct {
+	Name       string `json:"name"`
+	ReasonCode string `json:"reasonCode"`
+	RCode      string `json:"rCode"`
+	RFlag      string `json:"rFlag"`
+}
+
 // ErrorInformation holds error information from an otherwise successful authorization request.
 type ErrorInformation struct {
 	Reason  string    `json:"reason"`
@@ -81,7 +117,12 @@ type ProcessorInformation struct {
 		Code    string `json:"code"`
 		CodeRaw string `json:"codeRaw"`
 	} `json:"avs"`
-	TransactionID string `json:"transactionId"`
+	TransactionID        string `json:"transactionId"`
+	NetworkTransactionID string `json:"networkTransactionId"`
+	MerchantAdvice       struct {
+		Code    string `json:"code"`
+		CodeRaw string `json:"codeRaw"`
+	} `json:"merchantAdvice"`
 }
 
 // ProcessingInformation specifies various fields for authorize for options (auto-capture, Level3 Data, etc)
@@ -98,10 +139,28 @@ type ProcessingInformation struct {
 
 // OrderInformation is also used for authorize mainly to specify billing details and other Level3 items
 type OrderInformation struct {
-	BillTo        BillingInformation `json:"billTo"`
-	AmountDetails AmountDetails      `json:"amountDetails"`
-	LineItems     []LineItem         `json:"lineItems,omitempty"` // Level 3 field
-	ShipTo        ShippingDetails    `json:"shipTo,omitempty"`    // Level 3 field
+	BillTo          BillingInformation `json:"billTo"`
+	AmountDetails   AmountDetails      `json:"amountDetails"`
+	LineItems       []LineItem         `json:"lineItems,omitempty"`       // Level 3 field
+	ShipTo          ShippingDetails    `json:"shipTo,omitempty"`          // Level 3 field
+	InvoiceDetails  *InvoiceDetails    `json:"invoiceDetails,omitempty"`  // Level 2 field
+	ShippingDetails *ShippingFromInfo  `json:"shippingDetails,omitempty"` // Level 2 field
+}
+
+// InvoiceDetails contains the purchase order information used for Level 2 processing
+type InvoiceDetails struct {
+	PurchaseOrderNumber string `json:"purchaseOrderNumber,omitempty"`
+	Taxable             *bool  `json:"taxable,omitempty"`
+}
+
+// ShippingFromInfo contains the origin of the shipment used for Level 2 processing
+type ShippingFromInfo struct {
+	ShipFromPostalCode string `json:"shipFromPostalCode,omitempty"`
+}
+
+// MerchantInformation contains merchant details that vary per transaction
+type MerchantInformation struct {
+	VATRegistrationNumber string `json:"vatRegistrationNumber,omitempty"` // merchant tax ID
 }
 
 // BillingInformation contains billing address for auth call
@@ -4,9 +4,9 @@ import (
 	"github.com/BoltApp/sleet/common"
 )
 
-func firstdataHost(env common.Environment) string {
+func firstdataBaseURL(env common.Environment) string {
 	if env == common.Production {
-		return "prod.api.firstdata.com/gateway/v2"
+		return "https://prod.api.firstdata.com/gateway/v2"
 	}
-	return "cert.api.firstdata.com/gateway/v2"
+	return "https://cert.api.firstdata.com/gateway/v2"
 }
@@ -23,12 +23,13 @@ const (
 
 var (
 	// assert client interface
-	_ sleet.ClientWithContext = &FirstdataClient{}
+	_ sleet.ClientWithContext        = &FirstdataClient{}
+	_ sleet.TransactionDetailsClient = &FirstdataClient{}
 )
 
 // FirstdataClient contains the endpoint and credentials for the firstdata api as well as a client to send requests
 type FirstdataClient struct {
-	host            string
+	baseURL         string
 	credentials     Credentials
 	clientRequestID string
 	httpClient      *http.Client
@@ -41,9 +42,9 @@ type Credentials struct {
 }
 
 // NewClient creates a new firstdataClient with the given credentials and a default httpClient
-func NewClient(env common.Environment, credentials Credentials) *FirstdataClient {
+func NewClient(env common.Environment, credentials Credentials, options ...common.ClientOption) *FirstdataClient {
 	return &FirstdataClient{
-		host:        firstdataHost(env),
+		baseURL:     common.NewClientOptions(options...).BaseURLOr(firstdataBaseURL(env)),
 		credentials: credentials,
 		httpClient:  common.DefaultHttpClient(),
 	}
@@ -52,13 +53,13 @@ func NewClient(env common.Environment, credentials Credentials) *FirstdataClient
 // primaryURL returns the url used for firstdata Primary Transactions (Auth)
 // https://docs.firstdata.com/org/gateway/docs/api#create-primary-transaction
 func (client *FirstdataClient) primaryURL() string {
-	return "https://" + client.host + endpoint
+	return client.baseURL + endpoint
 }
 
 // secondaryURL composes the url used for firstdata Seconday Transactions (Capture,Void,Refund) given a transaction reference
 // https://docs.firstdata.com/org/gateway/docs/api#secondary-transaction
 func (client *FirstdataClient) secondaryURL(ref string) string {
-	return "https://" + client.host + endpoint + "/" + ref
+	return client.baseURL + endpoint + "/" + ref
 }
 
 // Authorize make a payment authorization request to FirstData for the given payment details. If successful, the
@@ -70,6 +71,9 @@ func (client *FirstdataClient) Authorize(request *sleet.AuthorizationRequest) (*
 // AuthorizeWithContext make a payment authorization request to FirstData for the given payment details. If successful, the
 // authorization response will be returned.
 func (client *FirstdataClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
 	firstdataAuthRequest, err := buildAuthRequest(request)
 	if err != nil {
 		return nil, err
@@ -84,19 +88,34 @@ func (client *FirstdataClient) AuthorizeWithContext(ctx context.Context, request
 	responseHeader := sleet.GetHTTPResponseHeader(request.Options, *httpResponse)
 	if firstdataResponse.Error != nil {
 		response := sleet.AuthorizationResponse{
-			Success:    false,
-			ErrorCode:  firstdataResponse.Error.Code,
-			StatusCode: httpResponse.StatusCode,
-			Header:     responseHeader,
+			Success:              false,
+			ErrorCode:            firstdataResponse.Error.Code,
+			StatusCode:           httpResponse.StatusCode,
+			Header:               responseHeader,
+			NetworkResponseCode:  firstdataResponse.Processor.AssociationResponseCode,
+			MerchantAdviceCode:   firstdataResponse.Processor.MerchantAdviceCode,
+			NetworkTransactionID: firstdataResponse.SchemeTransactionId,
+		}
+		// validation errors are returned without processor data, only declines carry a processor response code
+		if firstdataResponse.Processor.ResponseCode != "" {
+			response.DeclineReason = translateDeclineReason(firstdataResponse.Processor)
 		}
 		return &response, nil
 	}
 
-	if firstdataResponse.TransactionStatus == StatusApproved || firstdataResponse.TransactionStatus == StatusWaiting {
+	avs := firstdataResponse.Processor.AVSResponse
+	var resultType sleet.ResultType
+	pendingReason := sleet.PendingReasonNone
+	if isPending(firstdataResponse) {
+		resultType = sleet.ResultTypePending
+		pendingReason = sleet.PendingReasonAsyncProcessing
+	} else if firstdataResponse.TransactionStatus == StatusApproved {
 		success = true
 	}
-
-	avs := firstdataResponse.Processor.AVSResponse
+	declineReason := sleet.DeclineReasonNone
+	if !success && resultType != sleet.ResultTypePending {
+		declineReason = translateDeclineReason(firstdataResponse.Processor)
+	}
 
 	return &sleet.AuthorizationResponse{
 		Success:              success,
@@ -108,6 +127,12 @@ func (client *FirstdataClient) AuthorizeWithContext(ctx context.Context, request
 		CvvResultRaw:         string(firstdataResponse.Processor.SecurityCodeResponse),
 		StatusCode:           httpResponse.StatusCode,
 		Header:               responseHeader,
+		DeclineReason:        declineReason,
+		NetworkResponseCode:  firstdataResponse.Processor.AssociationResponseCode,
+		MerchantAdviceCode:   firstdataResponse.Processor.MerchantAdviceCode,
+		NetworkTransactionID: firstdataResponse.SchemeTransactionId,
+		ResultType:           resultType,
+		PendingReason:        pendingReason,
 	}, nil
 }
 
@@ -146,6 +171,9 @@ func (client *FirstdataClient) Void(request *sleet.VoidRequest) (*sleet.VoidResp
 // VoidWithContext transforms a sleet void request into a first data VoidTransaction request and makes the request
 // A transaction that has not yet been capture or has already been settled cannot be voided
 func (client *FirstdataClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	firstdataVoidRequest := buildVoidRequest(request)
 
 	firstdataResponse, _, err := client.sendRequest(ctx,
@@ -173,6 +201,9 @@ func (client *FirstdataClient) Refund(request *sleet.RefundRequest) (*sleet.Refu
 // RefundWithContext refunds a Firstdata payment.
 // Multiple refunds can be made on the same payment, but the total amount refunded should not exceed the payment total.
 func (client *FirstdataClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	firstdataRefundRequest := buildRefundRequest(request)
 
 	firstdataResponse, _, err := client.sendRequest(ctx,
@@ -192,6 +223,35 @@ func (client *FirstdataClient) RefundWithContext(ctx context.Context, request *s
 	return &sleet.RefundResponse{Success: true, TransactionReference: firstdataResponse.IPGTransactionId}, nil
 }
 
+// GetTransactionDetails retrieves the state of a transaction, used to follow up on authorizations that are still
+// waiting for the processor.
+func (client *FirstdataClient) GetTransactionDetails(request *sleet.TransactionDetailsRequest) (*sleet.TransactionDetailsResponse, error) {
+	return client.GetTransactionDetailsWithContext(context.TODO(), request)
+}
+
+// GetTransactionDetailsWithContext retrieves the state of a transaction, used to follow up on authorizations that are
+// still waiting for the processor.
+func (client *FirstdataClient) GetTransactionDetailsWithContext(ctx context.Context, request *sleet.TransactionDetailsRequest) (*sleet.TransactionDetailsResponse, error) {
+	// every request needs a unique Client-Request-Id, lookups are not tied to a client transaction reference
+	reqId := fmt.Sprintf("%s-%d", request.TransactionReference, time.Now().UnixNano())
+	firstdataResponse, _, err := client.doRequest(ctx, http.MethodGet, reqId, client.secondaryURL(request.TransactionReference), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if firstdataResponse.Error != nil {
+		return &sleet.TransactionDetailsResponse{ResultCode: firstdataResponse.Error.Code}, nil
+	}
+
+	resultType, pendingReason := translateTransactionState(firstdataResponse)
+	return &sleet.TransactionDetailsResponse{
+		ResultCode:    string(firstdataResponse.TransactionStatus),
+		Status:        string(firstdataResponse.TransactionState),
+		ResultType:    resultType,
+		PendingReason: pendingReason,
+	}, nil
+}
+
 // makeSignature generates a signature in accordance with the first data specification https://docs.firstdata.com/org/gateway/node/394
 func makeSignature(timestamp, apiKey, apiSecret, reqId, body string) string {
 	hashData := apiKey + reqId + timestamp + body
@@ -211,12 +271,16 @@ func (client *FirstdataClient) sendRequest(ctx context.Context, reqId, url strin
 		return nil, nil, err
 	}
 
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	signature := makeSignature(timestamp, client.credentials.ApiKey, client.credentials.ApiSecret, reqId, string(bodyJSON))
+	return client.doRequest(ctx, http.MethodPost, reqId, url, bodyJSON)
+}
 
-	reader := bytes.NewReader(bodyJSON)
+// doRequest signs and sends a request with the given method and body, which is empty for GET requests, and decodes
+// the firstdata response.
+func (client *FirstdataClient) doRequest(ctx context.Context, method, reqId, url string, body []byte) (*Response, *http.Response, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := makeSignature(timestamp, client.credentials.ApiKey, client.credentials.ApiSecret, reqId, string(body))
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
+	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -234,13 +298,13 @@ func (client *FirstdataClient) sendRequest(ctx context.Context, reqId, url strin
 
 	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var firstdataResponse Response
-	err = json.Unmarshal(body, &firstdataResponse)
+	err = json.Unmarshal(respBody, &firstdataResponse)
 	if err != nil {
 		return nil, nil, err
 	}
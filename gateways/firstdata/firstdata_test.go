@@ -26,7 +26,7 @@ const defaultReqId string = "11111"
 func TestNewClient(t *testing.T) {
 	t.Run("Dev environment", func(t *testing.T) {
 		want := &FirstdataClient{
-			host:        "cert.api.firstdata.com/gateway/v2",
+			baseURL:     "https://cert.api.firstdata.com/gateway/v2",
 			credentials: Credentials{defaultApiKey, defaultApiSecret},
 			httpClient:  common.DefaultHttpClient(),
 		}
@@ -41,7 +41,7 @@ func TestNewClient(t *testing.T) {
 
 	t.Run("Production environment", func(t *testing.T) {
 		want := &FirstdataClient{
-			host:        "prod.api.firstdata.com/gateway/v2",
+			baseURL:     "https://prod.api.firstdata.com/gateway/v2",
 			credentials: Credentials{defaultApiKey, defaultApiSecret},
 			httpClient:  common.DefaultHttpClient(),
 		}
@@ -78,6 +78,17 @@ func TestPrimaryURL(t *testing.T) {
 			t.Errorf("Got %q, want %q", got, want)
 		}
 	})
+
+	t.Run("Base URL override", func(t *testing.T) {
+		client := NewClient(common.Production, Credentials{defaultApiKey, defaultApiSecret}, common.WithBaseURL("http://localhost:8080/gateway/v2"))
+
+		want := "http://localhost:8080/gateway/v2/payments"
+		got := client.primaryURL()
+
+		if got != want {
+			t.Errorf("Got %q, want %q", got, want)
+		}
+	})
 }
 
 func TestSecondaryURL(t *testing.T) {
@@ -258,6 +269,8 @@ func TestAuthorize(t *testing.T) {
 			AvsResultRaw:         "NO_INPUT_DATA:NO_INPUT_DATA",
 			CvvResultRaw:         "NOT_CHECKED",
 			StatusCode:           200,
+			NetworkResponseCode:  "000",
+			NetworkTransactionID: "010194321391899",
 		}
 
 		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
@@ -499,3 +512,69 @@ func TestRefund(t *testing.T) {
 		}
 	})
 }
+
+func TestGetTransactionDetails(t *testing.T) {
+	url := "https://cert.api.firstdata.com/gateway/v2/payments/111111"
+	request := &sleet.TransactionDetailsRequest{TransactionReference: "111111"}
+
+	cases := []struct {
+		label string
+		body  string
+		want  *sleet.TransactionDetailsResponse
+	}{
+		{
+			"Waiting",
+			`{"transactionStatus":"WAITING","transactionState":"PENDING"}`,
+			&sleet.TransactionDetailsResponse{
+				ResultCode:    "WAITING",
+				Status:        "PENDING",
+				ResultType:    sleet.ResultTypePending,
+				PendingReason: sleet.PendingReasonAsyncProcessing,
+			},
+		},
+		{
+			"Approved",
+			`{"transactionStatus":"APPROVED","transactionState":"AUTHORIZED"}`,
+			&sleet.TransactionDetailsResponse{
+				ResultCode: "APPROVED",
+				Status:     "AUTHORIZED",
+				ResultType: sleet.ResultTypeSuccess,
+			},
+		},
+		{
+			"Declined",
+			`{"transactionStatus":"DECLINED","transactionState":"DECLINED"}`,
+			&sleet.TransactionDetailsResponse{
+				ResultCode: "DECLINED",
+				Status:     "DECLINED",
+				ResultType: sleet.ResultTypePaymentError,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", url, func(req *http.Request) (*http.Response, error) {
+				if req.Header.Get("Message-Signature") == "" {
+					t.Error("Request is not signed")
+				}
+				return httpmock.NewStringResponse(http.StatusOK, c.body), nil
+			})
+
+			firstDataClient := NewClient(common.Sandbox, Credentials{defaultApiKey, defaultApiSecret})
+
+			got, err := firstDataClient.GetTransactionDetails(request)
+			if err != nil {
+				t.Fatalf("ERROR THROWN: Got %q, after calling GetTransactionDetails", err)
+			}
+
+			if !cmp.Equal(*got, *c.want, sleet_t.CompareUnexported) {
+				t.Error("Response body does not match expected")
+				t.Error(cmp.Diff(*c.want, *got, sleet_t.CompareUnexported))
+			}
+		})
+	}
+}
@@ -49,3 +49,33 @@ func translateAvs(avs AVSResponse) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// translateDeclineReason converts the processor response code of a declined transaction, which follows ISO 8583, to
+// its equivalent Sleet decline reason.
+func translateDeclineReason(processor ProcessorData) sleet.DeclineReason {
+	if processor.ResponseCode == "" {
+		return sleet.DeclineReasonUnknown
+	}
+	return sleet.DeclineReasonFromISO8583(processor.ResponseCode)
+}
+
+// isPending returns true if firstdata accepted the transaction but the processor has not responded yet
+func isPending(response *Response) bool {
+	return response.TransactionStatus == StatusWaiting ||
+		response.TransactionState == StatePending ||
+		response.TransactionState == StateWaiting
+}
+
+// translateTransactionState converts the status and state of a firstdata transaction to a Sleet result type
+func translateTransactionState(response *Response) (sleet.ResultType, sleet.PendingReason) {
+	if isPending(response) {
+		return sleet.ResultTypePending, sleet.PendingReasonAsyncProcessing
+	}
+	switch response.TransactionStatus {
+	case StatusApproved:
+		return sleet.ResultTypeSuccess, sleet.PendingReasonNone
+	case StatusDeclined, StatusProcessingFailed, StatusValidationFailed:
+		return sleet.ResultTypePaymentError, sleet.PendingReasonNone
+	}
+	return sleet.ResultTypeUnknownError, sleet.PendingReasonNone
+}
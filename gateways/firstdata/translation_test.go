@@ -67,3 +67,23 @@ func TestTranslateAvs(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateDeclineReason(t *testing.T) {
+	cases := []struct {
+		in   string
+		want sleet.DeclineReason
+	}{
+		{"05", sleet.DeclineReasonDoNotHonor},
+		{"51", sleet.DeclineReasonInsufficientFunds},
+		{"", sleet.DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := translateDeclineReason(ProcessorData{ResponseCode: c.in})
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -140,6 +140,7 @@ type ProcessorData struct {
 	AssociationResponseCode string          `json:"associationResponseCode"`
 	AVSResponse             AVSResponse     `json:"avsResponse"`
 	SecurityCodeResponse    CVVResponseCode `json:"securityCodeResponse"`
+	MerchantAdviceCode      string          `json:"merchantAdviceCodeIndicator"`
 }
 
 // AVSResponse contains the avs response codes for the provided street and zip code
@@ -14,7 +14,8 @@ import (
 )
 
 const (
-	transactionEndpoint = "https://secure.networkmerchants.com/api/transact.php"
+	defaultBaseURL  = "https://secure.networkmerchants.com"
+	transactionPath = "/api/transact.php"
 )
 
 var (
@@ -24,20 +25,22 @@ var (
 
 // NMIClient represents an HTTP client and the associated authentication information required for making a Direct Post API request.
 type NMIClient struct {
+	url         string
 	testMode    bool
 	securityKey string
 	httpClient  *http.Client
 }
 
 // NewClient returns a new client for making NMI Direct Post API requests for a given merchant using a specified security key.
-func NewClient(env common.Environment, securityKey string) *NMIClient {
-	return NewWithHttpClient(env, securityKey, common.DefaultHttpClient())
+func NewClient(env common.Environment, securityKey string, options ...common.ClientOption) *NMIClient {
+	return NewWithHttpClient(env, securityKey, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient returns a client for making NMI Direct Post API requests for a given merchant using a specified security key.
 // The provided HTTP client will be used to make the requests.
-func NewWithHttpClient(env common.Environment, securityKey string, httpClient *http.Client) *NMIClient {
+func NewWithHttpClient(env common.Environment, securityKey string, httpClient *http.Client, options ...common.ClientOption) *NMIClient {
 	return &NMIClient{
+		url:         common.NewClientOptions(options...).BaseURLOr(defaultBaseURL) + transactionPath,
 		testMode:    nmiTestMode(env),
 		securityKey: securityKey,
 		httpClient:  httpClient,
@@ -53,6 +56,9 @@ func (client *NMIClient) Authorize(request *sleet.AuthorizationRequest) (*sleet.
 // AuthorizeWithContext makes a payment authorization request to NMI for the given payment details. If successful, the
 // authorization response will be returned.
 func (client *NMIClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
 	nmiAuthRequest := buildAuthRequest(client.testMode, client.securityKey, request)
 
 	nmiResponse, httpResponse, err := client.sendRequest(ctx, nmiAuthRequest)
@@ -64,12 +70,13 @@ func (client *NMIClient) AuthorizeWithContext(ctx context.Context, request *slee
 	// "1" means successful, "2" means declined, and "3" means bad request
 	if nmiResponse.Response != "1" {
 		return &sleet.AuthorizationResponse{
-			Success:    false,
-			Response:   nmiResponse.ResponseCode,
-			ErrorCode:  nmiResponse.ResponseCode,
-			Message:    nmiResponse.ResponseText,
-			StatusCode: httpResponse.StatusCode,
-			Header:     responseHeader,
+			Success:       false,
+			Response:      nmiResponse.ResponseCode,
+			ErrorCode:     nmiResponse.ResponseCode,
+			Message:       nmiResponse.ResponseText,
+			StatusCode:    httpResponse.StatusCode,
+			Header:        responseHeader,
+			DeclineReason: translateDeclineReason(nmiResponse.ResponseCode),
 		}, nil
 	}
 
@@ -128,6 +135,9 @@ func (client *NMIClient) Void(request *sleet.VoidRequest) (*sleet.VoidResponse,
 // VoidWithContext cancels a NMI transaction. If successful, the void response will be returned. A previously voided
 // transaction or one that has already been settled cannot be voided.
 func (client *NMIClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	nmiVoidRequest := buildVoidRequest(client.testMode, client.securityKey, request)
 
 	nmiResponse, _, err := client.sendRequest(ctx, nmiVoidRequest)
@@ -162,6 +172,9 @@ func (client *NMIClient) Refund(request *sleet.RefundRequest) (*sleet.RefundResp
 // If successful, the refund response will be returned.
 // Multiple refunds can be made on the same payment, but the total amount refunded should not exceed the payment total.
 func (client *NMIClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	nmiRefundRequest := buildRefundRequest(client.testMode, client.securityKey, request)
 
 	nmiResponse, _, err := client.sendRequest(ctx, nmiRefundRequest)
@@ -194,12 +207,12 @@ func (client *NMIClient) sendRequest(ctx context.Context, data *Request) (*Respo
 		return nil, nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, transactionEndpoint, strings.NewReader(formData.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.url, strings.NewReader(formData.Encode()))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	parsedUrl, err := url.Parse(transactionEndpoint)
+	parsedUrl, err := url.Parse(client.url)
 	if err != nil {
 		return nil, nil, err
 	}
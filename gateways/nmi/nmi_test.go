@@ -0,0 +1,32 @@
+package nmi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient(common.Production, "key", &http.Client{Transport: transport}, common.WithBaseURL("http://localhost:8080"))
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:8080/api/transact.php"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 )
 
+// maxPONumberLength is the longest purchase order number NMI passes on as Level 2 data
+const maxPONumberLength = 17
+
 // NMI transaction types
 const (
 	auth    = "auth"
@@ -29,7 +33,7 @@ func buildAuthRequest(testMode bool, securityKey string, request *sleet.Authoriz
 		strconv.Itoa(request.CreditCard.ExpirationYear)[2:],
 	)
 
-	return &Request{
+	nmiRequest := &Request{
 		Address1:              request.BillingAddress.StreetAddress1,
 		Address2:              request.BillingAddress.StreetAddress2,
 		Amount:                formatAmount(request.Amount.Amount),
@@ -47,18 +51,23 @@ func buildAuthRequest(testMode bool, securityKey string, request *sleet.Authoriz
 		TestMode:              enableTestMode(testMode),
 		TransactionType:       auth,
 		ZipCode:               request.BillingAddress.PostalCode,
-		Email:                 request.BillingAddress.Email,
+		Email:                 common.NormalizeEmail(request.BillingAddress.Email),
+		Phone:                 common.NormalizePhoneNumber(request.BillingAddress.PhoneNumber, request.BillingAddress.CountryCode, common.PhoneNumberFormatE164),
 	}
+	addLevel2Data(nmiRequest, request.Level2Data)
+	return nmiRequest
 }
 
 func buildCaptureRequest(testMode bool, securityKey string, request *sleet.CaptureRequest) *Request {
-	return &Request{
+	nmiRequest := &Request{
 		Amount:          formatAmount(request.Amount.Amount),
 		SecurityKey:     securityKey,
 		TestMode:        enableTestMode(testMode),
 		TransactionID:   &request.TransactionReference,
 		TransactionType: capture,
 	}
+	addLevel2Data(nmiRequest, request.Level2Data)
+	return nmiRequest
 }
 
 func buildVoidRequest(testMode bool, securityKey string, request *sleet.VoidRequest) *Request {
@@ -80,6 +89,25 @@ func buildRefundRequest(testMode bool, securityKey string, request *sleet.Refund
 	}
 }
 
+// addLevel2Data sets the purchasing card fields. Tax exempt transactions are sent with a tax amount of 0.00 as NMI
+// has no separate flag, and there is no field for the merchant tax ID.
+func addLevel2Data(nmiRequest *Request, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	if level2.TaxExempt {
+		nmiRequest.Tax = formatAmount(0)
+	} else {
+		nmiRequest.Tax = formatAmount(level2.TaxAmount.Amount)
+	}
+	if level2.PurchaseOrderNumber != "" {
+		nmiRequest.PONumber = common.SPtr(sleet.TruncateString(level2.PurchaseOrderNumber, maxPONumberLength))
+	}
+	if level2.ShipFromPostalCode != "" {
+		nmiRequest.ShipFromPostal = &level2.ShipFromPostalCode
+	}
+}
+
 func enableTestMode(testMode bool) *string {
 	if testMode {
 		enabled := "enabled"
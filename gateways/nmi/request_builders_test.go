@@ -0,0 +1,26 @@
+package nmi
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+func TestBuildAuthRequestLevel2(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level2Data = sleet_testing.BaseLevel2Data()
+	request.Level2Data.PurchaseOrderNumber = "PO-00000000001111111111222222222233333333334444444444"
+
+	got := buildAuthRequest(false, "key", request)
+	want := []*string{
+		common.SPtr("PO-00000000001111"),
+		common.SPtr("8.25"),
+		common.SPtr("94105"),
+	}
+	if diff := deep.Equal([]*string{got.PONumber, got.Tax, got.ShipFromPostal}, want); diff != nil {
+		t.Error(diff)
+	}
+}
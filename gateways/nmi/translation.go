@@ -0,0 +1,46 @@
+package nmi
+
+import "github.com/BoltApp/sleet"
+
+// Codes taken from: https://secure.nmi.com/merchants/resources/integration/integration_portal.php#transaction_response_variables
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"200": sleet.DeclineReasonDoNotHonor,
+	"201": sleet.DeclineReasonDoNotHonor,
+	"202": sleet.DeclineReasonInsufficientFunds,
+	"203": sleet.DeclineReasonLimitExceeded,
+	"204": sleet.DeclineReasonTransactionNotPermitted,
+	"220": sleet.DeclineReasonInvalidCardNumber,
+	"221": sleet.DeclineReasonInvalidCardNumber,
+	"222": sleet.DeclineReasonInvalidCardNumber,
+	"223": sleet.DeclineReasonExpiredCard,
+	"224": sleet.DeclineReasonInvalidExpirationDate,
+	"225": sleet.DeclineReasonCVVFailure,
+	"240": sleet.DeclineReasonCallIssuer,
+	"250": sleet.DeclineReasonPickUpCard,
+	"251": sleet.DeclineReasonLostOrStolenCard,
+	"252": sleet.DeclineReasonLostOrStolenCard,
+	"253": sleet.DeclineReasonSuspectedFraud,
+	"260": sleet.DeclineReasonDoNotHonor,
+	"261": sleet.DeclineReasonTransactionNotPermitted,
+	"262": sleet.DeclineReasonTransactionNotPermitted,
+	"264": sleet.DeclineReasonDoNotHonor,
+	"300": sleet.DeclineReasonTransactionNotPermitted,
+	"400": sleet.DeclineReasonProcessingError,
+	"410": sleet.DeclineReasonTransactionNotPermitted,
+	"411": sleet.DeclineReasonTransactionNotPermitted,
+	"420": sleet.DeclineReasonProcessingError,
+	"421": sleet.DeclineReasonIssuerUnavailable,
+	"430": sleet.DeclineReasonDuplicateTransaction,
+	"440": sleet.DeclineReasonProcessingError,
+	"441": sleet.DeclineReasonProcessingError,
+	"461": sleet.DeclineReasonTransactionNotPermitted,
+}
+
+// translateDeclineReason converts an NMI response_code to its equivalent Sleet decline reason.
+func translateDeclineReason(responseCode string) sleet.DeclineReason {
+	reason, ok := declineReasonMap[responseCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
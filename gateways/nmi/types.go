@@ -21,6 +21,10 @@ type Request struct {
 	TransactionType       string  `form:"type"`
 	ZipCode               *string `form:"zip,omitempty"`
 	Email                 *string `form:"email,omitempty"`
+	Phone                 *string `form:"phone,omitempty"`
+	PONumber              *string `form:"ponumber,omitempty"`
+	Tax                   *string `form:"tax,omitempty"`
+	ShipFromPostal        *string `form:"ship_from_postal,omitempty"`
 }
 
 // Response contains all of the fields for all Cybersource API call responses
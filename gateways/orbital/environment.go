@@ -4,9 +4,20 @@ import (
 	"github.com/BoltApp/sleet/common"
 )
 
-func orbitalHost(env common.Environment) string {
+// authorizePath is the path of the XML API, relative to the base URL
+const authorizePath = "/authorize"
+
+func orbitalBaseURL(env common.Environment) string {
+	if env == common.Production {
+		return "https://orbital1.chasepaymentech.com"
+	}
+	return "https://orbitalvar1.chasepaymentech.com"
+}
+
+// orbitalSecondaryBaseURL is the failover data center of the environment
+func orbitalSecondaryBaseURL(env common.Environment) string {
 	if env == common.Production {
-		return "https://orbital1.chasepaymentech.com/authorize"
+		return "https://orbital2.chasepaymentech.com"
 	}
-	return "https://orbitalvar1.chasepaymentech.com/authorize"
+	return "https://orbitalvar2.chasepaymentech.com"
 }
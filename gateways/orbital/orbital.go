@@ -1,12 +1,14 @@
 package orbital
 
 import (
-	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/xml"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/BoltApp/sleet"
 	"github.com/BoltApp/sleet/common"
@@ -14,7 +16,8 @@ import (
 
 var (
 	// assert client interface
-	_ sleet.Client = &OrbitalClient{}
+	_ sleet.Client                  = &OrbitalClient{}
+	_ sleet.TransactionLookupClient = &OrbitalClient{}
 )
 
 type Credentials struct {
@@ -24,20 +27,34 @@ type Credentials struct {
 }
 
 type OrbitalClient struct {
-	host        string
-	credentials Credentials
-	httpClient  *http.Client
+	host string
+	// secondaryHost is the failover data center of host
+	secondaryHost string
+	credentials   Credentials
+	httpClient    *http.Client
+	traceNumbers  *traceNumberCache
 }
 
-func NewClient(env common.Environment, credentials Credentials) *OrbitalClient {
-	return NewWithHttpClient(env, credentials, common.DefaultHttpClient())
+func NewClient(env common.Environment, credentials Credentials, options ...common.ClientOption) *OrbitalClient {
+	return NewWithHttpClient(env, credentials, common.DefaultHttpClient(), options...)
 }
 
-func NewWithHttpClient(env common.Environment, credentials Credentials, httpClient *http.Client) *OrbitalClient {
+// NewWithHttpClient creates an Orbital client with a custom http client. The base URLs of the environment can be
+// replaced with common.WithBaseURL and, for the failover data center, common.WithSecondaryBaseURL. A client with only
+// a base URL override fails over to that base URL, never to the data centers of the environment.
+func NewWithHttpClient(env common.Environment, credentials Credentials, httpClient *http.Client, options ...common.ClientOption) *OrbitalClient {
+	clientOptions := common.NewClientOptions(options...)
+	baseURL := clientOptions.BaseURLOr(orbitalBaseURL(env))
+	secondaryBaseURL := orbitalSecondaryBaseURL(env)
+	if clientOptions.BaseURL != "" {
+		secondaryBaseURL = clientOptions.BaseURL
+	}
 	return &OrbitalClient{
-		host:        orbitalHost(env),
-		credentials: credentials,
-		httpClient:  httpClient,
+		host:          baseURL + authorizePath,
+		secondaryHost: clientOptions.SecondaryBaseURLOr(secondaryBaseURL) + authorizePath,
+		credentials:   credentials,
+		httpClient:    httpClient,
+		traceNumbers:  newTraceNumberCache(),
 	}
 }
 
@@ -54,27 +71,37 @@ func (client *OrbitalClient) AuthorizeWithContext(ctx context.Context, request *
 	}
 
 	responseHeader := sleet.GetHTTPResponseHeader(request.Options, *httpResponse)
+	metadata := map[string]string{
+		sleet.HostMetadata:        orbitalResponse.Host,
+		sleet.TraceNumberMetadata: orbitalResponse.TraceNumber,
+	}
 	if orbitalResponse.Body.ProcStatus != ProcStatusSuccess {
 		if orbitalResponse.Body.RespCode != "" {
 			return &sleet.AuthorizationResponse{
-				ErrorCode:  orbitalResponse.Body.RespCode,
-				StatusCode: httpResponse.StatusCode,
-				Header:     responseHeader,
+				ErrorCode:     orbitalResponse.Body.RespCode,
+				StatusCode:    httpResponse.StatusCode,
+				Header:        responseHeader,
+				Metadata:      metadata,
+				DeclineReason: translateDeclineReason(orbitalResponse.Body.RespCode),
 			}, nil
 		}
 
 		return &sleet.AuthorizationResponse{
-			ErrorCode:  RespCodeNotPresent,
-			StatusCode: httpResponse.StatusCode,
-			Header:     responseHeader,
+			ErrorCode:     RespCodeNotPresent,
+			StatusCode:    httpResponse.StatusCode,
+			Header:        responseHeader,
+			Metadata:      metadata,
+			DeclineReason: translateDeclineReason(RespCodeNotPresent),
 		}, nil
 	}
 
 	if orbitalResponse.Body.RespCode != RespCodeApproved {
 		return &sleet.AuthorizationResponse{
-			ErrorCode:  orbitalResponse.Body.RespCode,
-			StatusCode: httpResponse.StatusCode,
-			Header:     responseHeader,
+			ErrorCode:     orbitalResponse.Body.RespCode,
+			StatusCode:    httpResponse.StatusCode,
+			Header:        responseHeader,
+			Metadata:      metadata,
+			DeclineReason: translateDeclineReason(orbitalResponse.Body.RespCode),
 		}, nil
 	}
 
@@ -88,6 +115,7 @@ func (client *OrbitalClient) AuthorizeWithContext(ctx context.Context, request *
 		CvvResultRaw:         string(orbitalResponse.Body.CVV2RespCode),
 		StatusCode:           httpResponse.StatusCode,
 		Header:               responseHeader,
+		Metadata:             metadata,
 	}, nil
 }
 
@@ -172,27 +200,52 @@ func (client *OrbitalClient) RefundWithContext(ctx context.Context, request *sle
 	}, nil
 }
 
+// LookupTransactionWithContext retrieves the last response for the OrderID sent for the ClientTransactionReference
+// through an Inquiry. When the client sent a request for the OrderID, the Inquiry retrieves the response of that
+// request by its Trace-Number. Inquiries fail with a ProcStatus error if Orbital has no transaction for the OrderID.
+func (client *OrbitalClient) LookupTransactionWithContext(ctx context.Context, request *sleet.TransactionLookupRequest) (*sleet.TransactionLookupResponse, error) {
+	if request.ClientTransactionReference == nil {
+		return &sleet.TransactionLookupResponse{Found: false}, nil
+	}
+
+	orbitalResponse, _, err := client.sendRequest(ctx, buildInquiryRequest(
+		*request.ClientTransactionReference,
+		client.traceNumbers.get(*request.ClientTransactionReference),
+		client.credentials,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return translateInquiryResponse(orbitalResponse.Body), nil
+}
+
+// sendRequest posts data to the primary host and fails over to the secondary host when the primary can not be
+// reached or answers with a server error. Both attempts carry the same Trace-Number and Merchant-ID headers, so that
+// Orbital processes a transaction at most once when it is retried on the secondary host.
 func (client *OrbitalClient) sendRequest(ctx context.Context, data Request) (*Response, *http.Response, error) {
 	bodyXML, err := xml.Marshal(data)
 	if err != nil {
 		return nil, nil, err
 	}
-
 	bodyWithHeader := xml.Header + string(bodyXML)
-	reader := bytes.NewReader([]byte(bodyWithHeader))
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, client.host, reader)
+
+	traceNumber, err := newTraceNumber()
 	if err != nil {
 		return nil, nil, err
 	}
+	if data.Body.XMLName.Local != RequestTypeInquiry && data.Body.OrderID != "" {
+		client.traceNumbers.set(data.Body.OrderID, traceNumber)
+	}
 
-	request.Header.Add("MIME-Version", MIMEVersion)
-	request.Header.Add("Content-Type", ContentType)
-	request.Header.Add("Content-length", strconv.Itoa(len(bodyWithHeader)))
-	request.Header.Add("Content-transfer-encoding", ContentTransferEncoding)
-	request.Header.Add("Request-number", RequestNumber)
-	request.Header.Add("Document-type", DocumentType)
-
-	resp, err := client.httpClient.Do(request)
+	host := client.host
+	resp, err := client.post(ctx, host, bodyWithHeader, traceNumber)
+	if shouldFailOver(ctx, resp, err) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		host = client.secondaryHost
+		resp, err = client.post(ctx, host, bodyWithHeader, traceNumber)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -210,6 +263,51 @@ func (client *OrbitalClient) sendRequest(ctx context.Context, data Request) (*Re
 	if err != nil {
 		return nil, nil, err
 	}
+	orbitalResponse.Host = resp.Request.URL.Host
+	orbitalResponse.TraceNumber = traceNumber
 
 	return &orbitalResponse, resp, nil
 }
+
+// post sends a request with the given body and trace number to host
+func (client *OrbitalClient) post(ctx context.Context, host string, body string, traceNumber string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, host, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("MIME-Version", MIMEVersion)
+	request.Header.Add("Content-Type", ContentType)
+	request.Header.Add("Content-length", strconv.Itoa(len(body)))
+	request.Header.Add("Content-transfer-encoding", ContentTransferEncoding)
+	request.Header.Add("Request-number", RequestNumber)
+	request.Header.Add("Document-type", DocumentType)
+	request.Header.Add("Trace-Number", traceNumber)
+	request.Header.Add("Merchant-ID", strconv.Itoa(client.credentials.MerchantID))
+
+	resp, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	// custom transports may leave the request out of the response, it tells which host served the request
+	resp.Request = request
+	return resp, nil
+}
+
+// shouldFailOver reports whether a request to the primary host is retried on the secondary host: the primary could
+// not be reached or answered with a server error, and the caller has not given up on the request
+func shouldFailOver(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// newTraceNumber returns a random Trace-Number between 1 and MaxTraceNumber
+func newTraceNumber() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(MaxTraceNumber))
+	if err != nil {
+		return "", err
+	}
+	return n.Add(n, big.NewInt(1)).String(), nil
+}
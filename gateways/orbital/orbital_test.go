@@ -6,6 +6,7 @@ package orbital
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -27,9 +28,11 @@ var credentials Credentials = Credentials{"username", "password", 1}
 func TestNewClient(t *testing.T) {
 	t.Run("Dev environment", func(t *testing.T) {
 		want := &OrbitalClient{
-			host:        "https://orbitalvar1.chasepaymentech.com/authorize",
-			httpClient:  common.DefaultHttpClient(),
-			credentials: Credentials{"username", "password", 1},
+			host:          "https://orbitalvar1.chasepaymentech.com/authorize",
+			secondaryHost: "https://orbitalvar2.chasepaymentech.com/authorize",
+			httpClient:    common.DefaultHttpClient(),
+			credentials:   Credentials{"username", "password", 1},
+			traceNumbers:  newTraceNumberCache(),
 		}
 
 		got := NewClient(common.Sandbox, Credentials{"username", "password", 1})
@@ -42,9 +45,11 @@ func TestNewClient(t *testing.T) {
 
 	t.Run("Production environment", func(t *testing.T) {
 		want := &OrbitalClient{
-			host:        "https://orbital1.chasepaymentech.com/authorize",
-			httpClient:  common.DefaultHttpClient(),
-			credentials: Credentials{"username", "password", 1},
+			host:          "https://orbital1.chasepaymentech.com/authorize",
+			secondaryHost: "https://orbital2.chasepaymentech.com/authorize",
+			httpClient:    common.DefaultHttpClient(),
+			credentials:   Credentials{"username", "password", 1},
+			traceNumbers:  newTraceNumberCache(),
 		}
 
 		got := NewClient(common.Production, Credentials{"username", "password", 1})
@@ -54,6 +59,45 @@ func TestNewClient(t *testing.T) {
 			t.Error(cmp.Diff(want, got, sleet_t.CompareUnexported))
 		}
 	})
+
+	t.Run("Base URL override without secondary", func(t *testing.T) {
+		want := &OrbitalClient{
+			host:          "http://localhost:8080/authorize",
+			secondaryHost: "http://localhost:8080/authorize",
+			httpClient:    common.DefaultHttpClient(),
+			credentials:   Credentials{"username", "password", 1},
+			traceNumbers:  newTraceNumberCache(),
+		}
+
+		got := NewClient(common.Production, Credentials{"username", "password", 1}, common.WithBaseURL("http://localhost:8080"))
+
+		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
+			t.Error("Client does not match expected")
+			t.Error(cmp.Diff(want, got, sleet_t.CompareUnexported))
+		}
+	})
+
+	t.Run("Base URL override", func(t *testing.T) {
+		want := &OrbitalClient{
+			host:          "http://localhost:8080/authorize",
+			secondaryHost: "http://localhost:8081/authorize",
+			httpClient:    common.DefaultHttpClient(),
+			credentials:   Credentials{"username", "password", 1},
+			traceNumbers:  newTraceNumberCache(),
+		}
+
+		got := NewClient(
+			common.Production,
+			Credentials{"username", "password", 1},
+			common.WithBaseURL("http://localhost:8080"),
+			common.WithSecondaryBaseURL("http://localhost:8081"),
+		)
+
+		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
+			t.Error("Client does not match expected")
+			t.Error(cmp.Diff(want, got, sleet_t.CompareUnexported))
+		}
+	})
 }
 
 func TestSend(t *testing.T) {
@@ -100,6 +144,7 @@ func TestSend(t *testing.T) {
 
 		var want *Response = new(Response)
 		helper.XmlUnmarshal(responseRaw, want)
+		want.Host = "orbitalvar1.chasepaymentech.com"
 
 		got, _, err := client.sendRequest(context.TODO(), request)
 
@@ -107,6 +152,7 @@ func TestSend(t *testing.T) {
 			t.Fatalf("Error thrown after sending request %q", err)
 		}
 
+		want.TraceNumber = headerReceived.Get("Trace-Number")
 		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
 			t.Error("Response body does not match expected")
 			t.Error(cmp.Diff(*want, *got, sleet_t.CompareUnexported))
@@ -125,6 +171,7 @@ func TestSend(t *testing.T) {
 				{"Content-transfer-encoding", "text", headerReceived.Get("Content-transfer-encoding")},
 				{"Request-number", "1", headerReceived.Get("Request-Number")},
 				{"Document-type", "Request", headerReceived.Get("Document-type")},
+				{"Merchant-ID", "1", headerReceived.Get("Merchant-ID")},
 			}
 
 			for _, c := range header_cases {
@@ -134,6 +181,13 @@ func TestSend(t *testing.T) {
 					}
 				})
 			}
+
+			t.Run("Trace-Number", func(t *testing.T) {
+				traceNumber, err := strconv.ParseInt(headerReceived.Get("Trace-Number"), 10, 64)
+				if err != nil || traceNumber < 1 || traceNumber > MaxTraceNumber {
+					t.Errorf("Got Trace-Number %q, want a number between 1 and %d", headerReceived.Get("Trace-Number"), int64(MaxTraceNumber))
+				}
+			})
 		})
 	})
 }
@@ -156,7 +210,9 @@ func TestAuthorize(t *testing.T) {
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
 
+		var traceNumber string
 		httpmock.RegisterResponder("POST", url, func(req *http.Request) (*http.Response, error) {
+			traceNumber = req.Header.Get("Trace-Number")
 			body, _ := ioutil.ReadAll(req.Body)
 
 			gotFmt := xmlfmt.FormatXML(string(body), "", "  ")
@@ -190,6 +246,11 @@ func TestAuthorize(t *testing.T) {
 			t.Fatalf("Error thrown after sending request %q", err)
 		}
 
+		want.Metadata = map[string]string{
+			sleet.HostMetadata:        "orbitalvar1.chasepaymentech.com",
+			sleet.TraceNumberMetadata: traceNumber,
+		}
+
 		if !cmp.Equal(*got, *want, sleet_t.CompareUnexported) {
 			t.Error("Response body does not match expected")
 			t.Error(cmp.Diff(*want, *got, sleet_t.CompareUnexported))
@@ -197,6 +258,116 @@ func TestAuthorize(t *testing.T) {
 	})
 }
 
+func TestFailover(t *testing.T) {
+	helper := sleet_t.NewTestHelper(t)
+
+	primaryURL := "https://orbitalvar1.chasepaymentech.com/authorize"
+	secondaryURL := "https://orbitalvar2.chasepaymentech.com/authorize"
+	authResponseRaw := helper.ReadFile("test_data/authResponse.xml")
+
+	request := sleet_t.BaseAuthorizationRequest()
+	request.ClientTransactionReference = common.SPtr("22222")
+
+	cases := []struct {
+		label        string
+		primary      httpmock.Responder
+		wantHost     string
+		wantFailover bool
+	}{
+		{"Connection error", httpmock.NewErrorResponder(errors.New("connection refused")), "orbitalvar2.chasepaymentech.com", true},
+		{"Server error", httpmock.NewStringResponder(http.StatusServiceUnavailable, "Service Unavailable"), "orbitalvar2.chasepaymentech.com", true},
+		{"Primary available", httpmock.NewBytesResponder(http.StatusOK, authResponseRaw), "orbitalvar1.chasepaymentech.com", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			var traceNumbers []string
+			recordTraceNumber := func(responder httpmock.Responder) httpmock.Responder {
+				return func(req *http.Request) (*http.Response, error) {
+					traceNumbers = append(traceNumbers, req.Header.Get("Trace-Number"))
+					return responder(req)
+				}
+			}
+			httpmock.RegisterResponder("POST", primaryURL, recordTraceNumber(c.primary))
+			httpmock.RegisterResponder("POST", secondaryURL, recordTraceNumber(httpmock.NewBytesResponder(http.StatusOK, authResponseRaw)))
+
+			client := NewClient(common.Sandbox, credentials)
+			got, err := client.Authorize(request)
+			if err != nil {
+				t.Fatalf("Error thrown after sending request %q", err)
+			}
+
+			if !got.Success || got.Metadata[sleet.HostMetadata] != c.wantHost {
+				t.Errorf("Got %+v, want an approval served by %q", got, c.wantHost)
+			}
+			if c.wantFailover && (len(traceNumbers) != 2 || traceNumbers[0] != traceNumbers[1]) {
+				t.Errorf("Got Trace-Numbers %q, want the same Trace-Number sent to both hosts", traceNumbers)
+			}
+			if !c.wantFailover && len(traceNumbers) != 1 {
+				t.Errorf("Got %d requests, want 1", len(traceNumbers))
+			}
+		})
+	}
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", primaryURL, httpmock.NewErrorResponder(errors.New("connection refused")))
+		httpmock.RegisterResponder("POST", secondaryURL, httpmock.NewBytesResponder(http.StatusOK, authResponseRaw))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client := NewClient(common.Sandbox, credentials)
+		if _, err := client.AuthorizeWithContext(ctx, request); err == nil {
+			t.Error("expected an error for a cancelled context")
+		}
+		if calls := httpmock.GetCallCountInfo()["POST "+secondaryURL]; calls != 0 {
+			t.Errorf("Got %d requests to the secondary host, want 0", calls)
+		}
+	})
+}
+
+func TestLookupTransactionAfterTimeout(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	url := "https://orbitalvar1.chasepaymentech.com/authorize"
+	request := sleet_t.BaseAuthorizationRequest()
+	request.ClientTransactionReference = common.SPtr("22222")
+
+	var authTraceNumber string
+	var inquiry []byte
+	httpmock.RegisterResponder("POST", url, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if !bytes.Contains(body, []byte("<Inquiry>")) {
+			authTraceNumber = req.Header.Get("Trace-Number")
+			return nil, errors.New("timeout")
+		}
+		inquiry = body
+		return httpmock.NewStringResponse(http.StatusOK, "<Response><QuickResp><ProcStatus>882</ProcStatus></QuickResp></Response>"), nil
+	})
+
+	client := NewClient(common.Sandbox, credentials)
+	if _, err := client.Authorize(request); err == nil {
+		t.Fatal("expected an error for the timed out authorization")
+	}
+	if _, err := client.LookupTransactionWithContext(context.Background(), &sleet.TransactionLookupRequest{
+		ClientTransactionReference: request.ClientTransactionReference,
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := "<OrderID>22222</OrderID><InquiryRetryNumber>" + authTraceNumber + "</InquiryRetryNumber>"
+	if authTraceNumber == "" || !bytes.Contains(inquiry, []byte(want)) {
+		t.Errorf("Got Inquiry %s, want the Trace-Number of the authorization %q as InquiryRetryNumber", inquiry, authTraceNumber)
+	}
+}
+
 func TestCapture(t *testing.T) {
 	helper := sleet_t.NewTestHelper(t)
 
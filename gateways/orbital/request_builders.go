@@ -2,16 +2,29 @@ package orbital
 
 import (
 	"encoding/xml"
-	"strconv"
+	"fmt"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 )
 
+// addressProfile follows the AVS field limits of the Orbital XML interface specification
+var addressProfile = common.AddressProfile{
+	StreetAddress1MaxLength: 30,
+	StreetAddress2MaxLength: 30,
+	LocalityMaxLength:       20,
+	RegionCodeMaxLength:     2,
+	PostalCodeMaxLength:     10,
+	CountryCodeMaxLength:    2,
+	ASCIIOnly:               true,
+	DisallowedCharacters:    `%|^\/`,
+}
+
 func buildAuthRequest(authRequest *sleet.AuthorizationRequest, credentials Credentials) Request {
 
 	amount := authRequest.Amount.Amount
-	exp := strconv.Itoa(authRequest.CreditCard.ExpirationYear) + strconv.Itoa(authRequest.CreditCard.ExpirationMonth)
 	code := currencyMap[authRequest.Amount.Currency]
+	billingAddress := common.NormalizeAddress(authRequest.BillingAddress, addressProfile)
 
 	body := RequestBody{
 		OrbitalConnectionUsername: credentials.Username,
@@ -22,18 +35,19 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest, credentials Crede
 		BIN:                       BINStratus,
 		TerminalID:                TerminalIDStratus,
 		AccountNum:                authRequest.CreditCard.Number,
-		Exp:                       exp,
+		Exp:                       expirationDate(authRequest.CreditCard),
 		CurrencyCode:              code,
 		CurrencyExponent:          CurrencyExponentDefault,
 		CardSecVal:                authRequest.CreditCard.CVV,
 		OrderID:                   *authRequest.ClientTransactionReference,
 		Amount:                    amount,
-		AVSzip:                    *authRequest.BillingAddress.PostalCode,
-		AVSaddress1:               *authRequest.BillingAddress.StreetAddress1,
-		AVSaddress2:               authRequest.BillingAddress.StreetAddress2,
-		AVSstate:                  *authRequest.BillingAddress.RegionCode,
-		AVScity:                   *authRequest.BillingAddress.Locality,
-		AVScountryCode:            *authRequest.BillingAddress.CountryCode,
+		AVSzip:                    common.SafeStr(billingAddress.PostalCode),
+		AVSaddress1:               common.SafeStr(billingAddress.StreetAddress1),
+		AVSaddress2:               billingAddress.StreetAddress2,
+		AVSstate:                  common.SafeStr(billingAddress.RegionCode),
+		AVScity:                   common.SafeStr(billingAddress.Locality),
+		AVScountryCode:            common.SafeStr(billingAddress.CountryCode),
+		AVSphoneNum:               common.SafeStr(common.NormalizePhoneNumber(billingAddress.PhoneNumber, billingAddress.CountryCode, common.PhoneNumberFormatNational)),
 	}
 
 	if authRequest.CreditCard.Network == sleet.CreditCardNetworkVisa || authRequest.CreditCard.Network == sleet.CreditCardNetworkDiscover {
@@ -45,6 +59,16 @@ func buildAuthRequest(authRequest *sleet.AuthorizationRequest, credentials Crede
 		body.DigitalTokenCryptogram = authRequest.Cryptogram
 	}
 
+	switch {
+	case authRequest.OfflineApprovalCode != "":
+		body.MessageType = MessageTypeForceAndCapture
+		body.PriorAuthID = authRequest.OfflineApprovalCode
+	case authRequest.AutoCapture:
+		body.MessageType = MessageTypeAuthAndCapture
+	}
+
+	addLevel2Data(&body, authRequest.Level2Data)
+
 	body.XMLName = xml.Name{Local: RequestTypeNewOrder}
 	return Request{Body: body}
 }
@@ -60,11 +84,29 @@ func buildCaptureRequest(captureRequest *sleet.CaptureRequest, credentials Crede
 		TxRefNum:                  captureRequest.TransactionReference,
 		OrderID:                   *captureRequest.ClientTransactionReference,
 	}
+	addLevel2Data(&body, captureRequest.Level2Data)
 
 	body.XMLName = xml.Name{Local: RequestTypeCapture}
 	return Request{Body: body}
 }
 
+// buildInquiryRequest retrieves the response of the request sent with the Trace-Number retryNumber, or the last
+// response for orderID if retryNumber is empty
+func buildInquiryRequest(orderID string, retryNumber string, credentials Credentials) Request {
+	body := RequestBody{
+		OrbitalConnectionUsername: credentials.Username,
+		OrbitalConnectionPassword: credentials.Password,
+		BIN:                       BINStratus,
+		MerchantID:                credentials.MerchantID,
+		TerminalID:                TerminalIDStratus,
+		OrderID:                   orderID,
+		InquiryRetryNumber:        retryNumber,
+	}
+
+	body.XMLName = xml.Name{Local: RequestTypeInquiry}
+	return Request{Body: body}
+}
+
 func buildVoidRequest(voidRequest *sleet.VoidRequest, credentials Credentials) Request {
 	body := RequestBody{
 		OrbitalConnectionUsername: credentials.Username,
@@ -75,6 +117,9 @@ func buildVoidRequest(voidRequest *sleet.VoidRequest, credentials Credentials) R
 		TxRefNum:                  voidRequest.TransactionReference,
 		OrderID:                   *voidRequest.ClientTransactionReference,
 	}
+	if voidRequest.Amount != nil {
+		body.AdjustedAmt = voidRequest.Amount.Amount
+	}
 
 	body.XMLName = xml.Name{Local: RequestTypeVoid}
 	return Request{Body: body}
@@ -99,6 +144,35 @@ func buildRefundRequest(refundRequest *sleet.RefundRequest, credentials Credenti
 		TxRefNum:                  refundRequest.TransactionReference,
 	}
 
+	// a refund without a prior transaction credits the card instead of the transaction
+	if refundRequest.TransactionReference == "" && refundRequest.CreditCard != nil {
+		body.AccountNum = refundRequest.CreditCard.Number
+		body.Exp = expirationDate(refundRequest.CreditCard)
+	}
+
 	body.XMLName = xml.Name{Local: RequestTypeNewOrder}
 	return Request{Body: body}
 }
+
+// expirationDate formats the card expiration as the CCYYMM Orbital expects
+func expirationDate(card *sleet.CreditCard) string {
+	return fmt.Sprintf("%04d%02d", card.ExpirationYear, card.ExpirationMonth)
+}
+
+// addLevel2Data sets the purchasing card fields. Orbital has no Level 2 field for the merchant tax ID or the
+// ship-from postal code, so those are not sent.
+func addLevel2Data(body *RequestBody, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	body.PCOrderNum = sleet.TruncateString(level2.PurchaseOrderNumber, PCOrderNumMaxLength)
+	switch {
+	case level2.TaxExempt:
+		body.TaxInd = TaxIndExempt
+	case level2.TaxAmount.Amount > 0:
+		body.TaxInd = TaxIndProvided
+		body.Tax = level2.TaxAmount.Amount
+	default:
+		body.TaxInd = TaxIndNotProvided
+	}
+}
@@ -15,7 +15,7 @@ import (
 )
 
 func TestBuildAuthRequest(t *testing.T) {
-	var visaBase, discoverBase, mastercardBase, applepayBase sleet.AuthorizationRequest
+	var visaBase, discoverBase, mastercardBase, singleDigitMonthBase, applepayBase, saleBase, forceBase sleet.AuthorizationRequest
 
 	visaBase = *sleet_testing.BaseAuthorizationRequest()
 	visaBase.CreditCard.Network = sleet.CreditCardNetworkVisa
@@ -26,10 +26,24 @@ func TestBuildAuthRequest(t *testing.T) {
 	mastercardBase = *sleet_testing.BaseAuthorizationRequest()
 	mastercardBase.CreditCard.Network = sleet.CreditCardNetworkMastercard
 
+	singleDigitMonthBase = *sleet_testing.BaseAuthorizationRequest()
+	singleDigitMonthBase.CreditCard.Network = sleet.CreditCardNetworkMastercard
+	singleDigitMonthBase.CreditCard.ExpirationMonth = 5
+	singleDigitMonthBase.CreditCard.ExpirationYear = 2030
+
 	applepayBase = *sleet_testing.BaseAuthorizationRequest()
 	applepayBase.ECI = "5"
 	applepayBase.Cryptogram = "crypto"
 
+	saleBase = *sleet_testing.BaseAuthorizationRequest()
+	saleBase.CreditCard.Network = sleet.CreditCardNetworkMastercard
+	saleBase.AutoCapture = true
+
+	forceBase = *sleet_testing.BaseAuthorizationRequest()
+	forceBase.CreditCard.Network = sleet.CreditCardNetworkMastercard
+	forceBase.AutoCapture = true
+	forceBase.OfflineApprovalCode = "123456"
+
 	credentials := Credentials{"username", "password", 1}
 
 	cases := []struct {
@@ -126,6 +140,35 @@ func TestBuildAuthRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Auth with single digit expiration month",
+			&singleDigitMonthBase,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					IndustryType:              IndustryTypeEcomm,
+					MessageType:               MessageTypeAuth,
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					XMLName:                   xml.Name{Local: RequestTypeNewOrder},
+					AccountNum:                singleDigitMonthBase.CreditCard.Number,
+					Exp:                       "203005",
+					CardSecVal:                singleDigitMonthBase.CreditCard.CVV,
+					CurrencyCode:              CurrencyCodeUSD,
+					CurrencyExponent:          CurrencyExponentDefault,
+					Amount:                    100,
+					OrderID:                   *singleDigitMonthBase.ClientTransactionReference,
+					AVSzip:                    *singleDigitMonthBase.BillingAddress.PostalCode,
+					AVSaddress1:               *singleDigitMonthBase.BillingAddress.StreetAddress1,
+					AVSaddress2:               singleDigitMonthBase.BillingAddress.StreetAddress2,
+					AVSstate:                  *singleDigitMonthBase.BillingAddress.RegionCode,
+					AVScity:                   *singleDigitMonthBase.BillingAddress.Locality,
+					AVScountryCode:            *singleDigitMonthBase.BillingAddress.CountryCode,
+				},
+			},
+		},
 		{
 			"Auth with applepay",
 			&applepayBase,
@@ -157,6 +200,65 @@ func TestBuildAuthRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Sale",
+			&saleBase,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					IndustryType:              IndustryTypeEcomm,
+					MessageType:               MessageTypeAuthAndCapture,
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					XMLName:                   xml.Name{Local: RequestTypeNewOrder},
+					AccountNum:                saleBase.CreditCard.Number,
+					Exp:                       "202510",
+					CardSecVal:                saleBase.CreditCard.CVV,
+					CurrencyCode:              CurrencyCodeUSD,
+					CurrencyExponent:          CurrencyExponentDefault,
+					Amount:                    100,
+					OrderID:                   *saleBase.ClientTransactionReference,
+					AVSzip:                    *saleBase.BillingAddress.PostalCode,
+					AVSaddress1:               *saleBase.BillingAddress.StreetAddress1,
+					AVSaddress2:               saleBase.BillingAddress.StreetAddress2,
+					AVSstate:                  *saleBase.BillingAddress.RegionCode,
+					AVScity:                   *saleBase.BillingAddress.Locality,
+					AVScountryCode:            *saleBase.BillingAddress.CountryCode,
+				},
+			},
+		},
+		{
+			"Force with offline approval code",
+			&forceBase,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					IndustryType:              IndustryTypeEcomm,
+					MessageType:               MessageTypeForceAndCapture,
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					XMLName:                   xml.Name{Local: RequestTypeNewOrder},
+					AccountNum:                forceBase.CreditCard.Number,
+					Exp:                       "202510",
+					CardSecVal:                forceBase.CreditCard.CVV,
+					CurrencyCode:              CurrencyCodeUSD,
+					CurrencyExponent:          CurrencyExponentDefault,
+					Amount:                    100,
+					OrderID:                   *forceBase.ClientTransactionReference,
+					AVSzip:                    *forceBase.BillingAddress.PostalCode,
+					AVSaddress1:               *forceBase.BillingAddress.StreetAddress1,
+					AVSaddress2:               forceBase.BillingAddress.StreetAddress2,
+					AVSstate:                  *forceBase.BillingAddress.RegionCode,
+					AVScity:                   *forceBase.BillingAddress.Locality,
+					AVScountryCode:            *forceBase.BillingAddress.CountryCode,
+					PriorAuthID:               "123456",
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -171,6 +273,10 @@ func TestBuildAuthRequest(t *testing.T) {
 
 func TestBuildCaptureRequest(t *testing.T) {
 	base := sleet_testing.BaseCaptureRequest()
+	withLevel2 := sleet_testing.BaseCaptureRequest()
+	withLevel2.Level2Data = sleet_testing.BaseLevel2Data()
+	taxExempt := sleet_testing.BaseCaptureRequest()
+	taxExempt.Level2Data = &sleet.Level2Data{PurchaseOrderNumber: "PO-123456789012345678", TaxExempt: true}
 	credentials := Credentials{"username", "password", 1}
 
 	cases := []struct {
@@ -195,6 +301,45 @@ func TestBuildCaptureRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Capture Request with Level 2 data",
+			withLevel2,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					XMLName:                   xml.Name{Local: RequestTypeCapture},
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					Amount:                    100,
+					TxRefNum:                  base.TransactionReference,
+					OrderID:                   *base.ClientTransactionReference,
+					TaxInd:                    TaxIndProvided,
+					Tax:                       825,
+					PCOrderNum:                "PO-12345",
+				},
+			},
+		},
+		{
+			"Capture Request with tax exempt Level 2 data",
+			taxExempt,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					XMLName:                   xml.Name{Local: RequestTypeCapture},
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					Amount:                    100,
+					TxRefNum:                  base.TransactionReference,
+					OrderID:                   *base.ClientTransactionReference,
+					TaxInd:                    TaxIndExempt,
+					PCOrderNum:                "PO-12345678901234",
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -209,6 +354,8 @@ func TestBuildCaptureRequest(t *testing.T) {
 
 func TestBuildVoidRequest(t *testing.T) {
 	base := sleet_testing.BaseVoidRequest()
+	partial := sleet_testing.BaseVoidRequest()
+	partial.Amount = &sleet.Amount{Amount: 40, Currency: "USD"}
 	credentials := Credentials{"username", "password", 1}
 
 	cases := []struct {
@@ -232,6 +379,23 @@ func TestBuildVoidRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Partial Void Request",
+			partial,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					XMLName:                   xml.Name{Local: RequestTypeVoid},
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					TxRefNum:                  base.TransactionReference,
+					OrderID:                   *base.ClientTransactionReference,
+					AdjustedAmt:               40,
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -246,6 +410,9 @@ func TestBuildVoidRequest(t *testing.T) {
 
 func TestBuildRefundRequest(t *testing.T) {
 	base := sleet_testing.BaseRefundRequest()
+	standalone := sleet_testing.BaseRefundRequest()
+	standalone.TransactionReference = ""
+	standalone.CreditCard = sleet_testing.BaseAuthorizationRequest().CreditCard
 
 	credentials := Credentials{"username", "password", 1}
 
@@ -275,6 +442,28 @@ func TestBuildRefundRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Refund Request without a prior transaction",
+			standalone,
+			Request{
+				Body: RequestBody{
+					OrbitalConnectionUsername: "username",
+					OrbitalConnectionPassword: "password",
+					MerchantID:                1,
+					XMLName:                   xml.Name{Local: RequestTypeNewOrder},
+					BIN:                       BINStratus,
+					TerminalID:                TerminalIDStratus,
+					IndustryType:              IndustryTypeEcomm,
+					MessageType:               MessageTypeRefund,
+					CurrencyCode:              CurrencyCodeUSD,
+					CurrencyExponent:          CurrencyExponentDefault,
+					Amount:                    100,
+					AccountNum:                standalone.CreditCard.Number,
+					Exp:                       "202510",
+					OrderID:                   *base.ClientTransactionReference,
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
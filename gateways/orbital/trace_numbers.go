@@ -0,0 +1,40 @@
+package orbital
+
+import "sync"
+
+// maxTraceNumbers bounds the number of OrderIDs whose last Trace-Number is remembered
+const maxTraceNumbers = 4096
+
+// traceNumberCache remembers the Trace-Number of the last request sent for each OrderID, so that an Inquiry made after
+// a request timed out retrieves the response of that request through InquiryRetryNumber. The oldest OrderIDs are
+// forgotten once maxTraceNumbers is reached.
+type traceNumberCache struct {
+	mutex        sync.Mutex
+	traceNumbers map[string]string
+	orderIDs     []string
+}
+
+func newTraceNumberCache() *traceNumberCache {
+	return &traceNumberCache{traceNumbers: make(map[string]string)}
+}
+
+// set records traceNumber as the last Trace-Number sent for orderID
+func (cache *traceNumberCache) set(orderID string, traceNumber string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.traceNumbers[orderID]; !ok {
+		if len(cache.orderIDs) == maxTraceNumbers {
+			delete(cache.traceNumbers, cache.orderIDs[0])
+			cache.orderIDs = cache.orderIDs[1:]
+		}
+		cache.orderIDs = append(cache.orderIDs, orderID)
+	}
+	cache.traceNumbers[orderID] = traceNumber
+}
+
+// get returns the last Trace-Number sent for orderID, or an empty string if it is not known
+func (cache *traceNumberCache) get(orderID string) string {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.traceNumbers[orderID]
+}
@@ -44,3 +44,52 @@ func translateAvs(avs AVSResponseCode) sleet.AVSResponse {
 	}
 	return sleetCode
 }
+
+// declineReasonMap holds the Chase Paymentech response codes that differ from ISO 8583
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"33": sleet.DeclineReasonExpiredCard,
+	"68": sleet.DeclineReasonIssuerUnavailable,
+}
+
+// translateDeclineReason converts an Orbital RespCode to its equivalent Sleet decline reason, falling back to the
+// ISO 8583 meaning of the code.
+func translateDeclineReason(respCode string) sleet.DeclineReason {
+	if respCode == RespCodeNotPresent {
+		return sleet.DeclineReasonProcessingError
+	}
+	reason, ok := declineReasonMap[respCode]
+	if !ok {
+		return sleet.DeclineReasonFromISO8583(respCode)
+	}
+	return reason
+}
+
+// translateInquiryResponse converts the original response replayed by an Inquiry. The authorization is captured if the
+// replayed response is the one of a capture or of an authorization with capture.
+func translateInquiryResponse(body ResponseBody) *sleet.TransactionLookupResponse {
+	if body.ProcStatus != ProcStatusSuccess {
+		return &sleet.TransactionLookupResponse{Found: false}
+	}
+
+	response := &sleet.TransactionLookupResponse{
+		Found:                true,
+		TransactionReference: body.TxRefNum,
+		ResultType:           sleet.ResultTypeUnknownError,
+		Status:               body.RespCode,
+	}
+	switch body.XMLName.Local {
+	case ResponseTypeCapture:
+		response.ResultType = sleet.ResultTypeSuccess
+		response.Captured = true
+	case ResponseTypeNewOrder:
+		switch {
+		case body.ApprovalStatus == ApprovalStatusApproved && body.RespCode == RespCodeApproved:
+			response.ResultType = sleet.ResultTypeSuccess
+			response.Captured = body.MessageType == string(MessageTypeAuthAndCapture) ||
+				body.MessageType == string(MessageTypeForceAndCapture)
+		case body.ApprovalStatus == ApprovalStatusDeclined:
+			response.ResultType = sleet.ResultTypePaymentError
+		}
+	}
+	return response
+}
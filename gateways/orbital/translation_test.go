@@ -80,3 +80,26 @@ func TestTranslateAvs(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateDeclineReason(t *testing.T) {
+	cases := []struct {
+		in   string
+		want sleet.DeclineReason
+	}{
+		{"05", sleet.DeclineReasonDoNotHonor},
+		{"33", sleet.DeclineReasonExpiredCard},
+		{"51", sleet.DeclineReasonInsufficientFunds},
+		{"68", sleet.DeclineReasonIssuerUnavailable},
+		{RespCodeNotPresent, sleet.DeclineReasonProcessingError},
+		{"Fake Code", sleet.DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := translateDeclineReason(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
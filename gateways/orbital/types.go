@@ -8,6 +8,13 @@ const (
 	RequestTypeNewOrder = "NewOrder"
 	RequestTypeCapture  = "MarkForCapture"
 	RequestTypeVoid     = "Reversal"
+	RequestTypeInquiry  = "Inquiry"
+)
+
+// Response element names
+const (
+	ResponseTypeNewOrder = "NewOrderResp"
+	ResponseTypeCapture  = "MarkForCaptureResp"
 )
 
 type MessageType string
@@ -46,6 +53,17 @@ const (
 	CardSecNotAvailable CardSecValInd = 9 // Cardholder states data not available
 )
 
+type TaxInd string // Level 2 tax indicator
+
+const (
+	TaxIndNotProvided TaxInd = "0"
+	TaxIndProvided    TaxInd = "1"
+	TaxIndExempt      TaxInd = "2"
+)
+
+// PCOrderNumMaxLength is the maximum length of the Level 2 purchase order number
+const PCOrderNumMaxLength = 17
+
 type ApprovalStatus int
 
 const (
@@ -98,6 +116,9 @@ const (
 	ContentTransferEncoding = "text"
 	RequestNumber           = "1"
 	DocumentType            = "Request"
+	// MaxTraceNumber is the largest Trace-Number. Orbital answers a request resent with the Trace-Number of an earlier
+	// one with the response of the earlier request instead of processing it again.
+	MaxTraceNumber = 9999999999999999
 )
 
 type Request struct {
@@ -115,6 +136,10 @@ const (
 type Response struct {
 	XMLName xml.Name     `xml:"Response"`
 	Body    ResponseBody `xml:",any"`
+	// Host is the host that answered the request, the primary or the secondary one
+	Host string `xml:"-"`
+	// TraceNumber is the Trace-Number the request was sent with
+	TraceNumber string `xml:"-"`
 }
 
 type RequestBody struct {
@@ -144,8 +169,13 @@ type RequestBody struct {
 	AVSphoneNum               string           `xml:"AVSphoneNum,omitempty"`
 	OrderID                   string           `xml:"OrderID,omitempty"`                // generated id, max 22 chars
 	Amount                    int64            `xml:"Amount,omitempty"`                 //int with the last 2 digits being implied decimals ie 100.25 is sent as 10025, 90 is sent as 9000
+	TaxInd                    TaxInd           `xml:"TaxInd,omitempty"`                 // Level 2 tax indicator
+	Tax                       int64            `xml:"Tax,omitempty"`                    // Level 2 tax amount with implied decimals like Amount
 	DPANInd                   string           `xml:"DPANInd,omitempty"`                // does this token represent a device based Primary Account Number (DPAN). Y if yes, omit if not. Pan goes in AccountNum
 	DigitalTokenCryptogram    string           `xml:"DigitalTokenCryptogram,omitempty"` // cryptogram for network tokenized cards (i.e. ApplePay)
+	PCOrderNum                string           `xml:"PCOrderNum,omitempty"`             // Level 2 purchase order number
+	PriorAuthID               string           `xml:"PriorAuthID,omitempty"`            // approval code of an offline authorization, sent with MessageTypeForceAndCapture
+	InquiryRetryNumber        string           `xml:"InquiryRetryNumber,omitempty"`     // Trace-Number of the transaction an Inquiry retrieves
 }
 
 type ResponseBody struct {
@@ -0,0 +1,81 @@
+package paypalpayflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nameValue is a field of a Payflow name-value request
+type nameValue struct {
+	name  string
+	value string
+}
+
+// encodeNameValues encodes fields in the Payflow name-value format. Every value is sent with a length tag,
+// NAME[length]=value, so that values may contain the '&', '=' and '"' delimiters. The length is in bytes.
+func encodeNameValues(fields []nameValue) string {
+	var builder strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			builder.WriteByte('&')
+		}
+		builder.WriteString(field.name)
+		builder.WriteByte('[')
+		builder.WriteString(strconv.Itoa(len(field.value)))
+		builder.WriteString("]=")
+		builder.WriteString(field.value)
+	}
+	return builder.String()
+}
+
+// parseNameValues decodes a Payflow name-value response. Values with a length tag are read by length, other values
+// end at the next '&' and may contain '='.
+func parseNameValues(body string) (Response, error) {
+	response := make(Response)
+	body = strings.TrimRight(body, "\r\n")
+	for position := 0; position < len(body); {
+		end := strings.IndexAny(body[position:], "[=")
+		if end < 0 {
+			return nil, fmt.Errorf("paypalpayflow: field without value at %d", position)
+		}
+		name := strings.TrimSpace(body[position : position+end])
+		if name == "" {
+			return nil, fmt.Errorf("paypalpayflow: field without name at %d", position)
+		}
+		position += end
+
+		var value string
+		if body[position] == '[' {
+			closing := strings.Index(body[position:], "]=")
+			if closing < 0 {
+				return nil, fmt.Errorf("paypalpayflow: unterminated length tag for %s", name)
+			}
+			length, err := strconv.Atoi(body[position+1 : position+closing])
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("paypalpayflow: invalid length tag for %s", name)
+			}
+			position += closing + len("]=")
+			if position+length > len(body) {
+				return nil, fmt.Errorf("paypalpayflow: value of %s is shorter than its length tag", name)
+			}
+			value = body[position : position+length]
+			position += length
+			if position < len(body) && body[position] != '&' {
+				return nil, fmt.Errorf("paypalpayflow: value of %s is longer than its length tag", name)
+			}
+		} else {
+			position++
+			end := strings.IndexByte(body[position:], '&')
+			if end < 0 {
+				end = len(body) - position
+			}
+			value = strings.TrimSpace(body[position : position+end])
+			position += end
+		}
+
+		response[name] = value
+		position++ // skip the '&' separating fields
+	}
+	return response, nil
+}
@@ -0,0 +1,37 @@
+//go:build go1.18
+// +build go1.18
+
+package paypalpayflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzNameValuesRoundTrip(f *testing.F) {
+	f.Add("BILLTOSTREET", `1 "A&B" St=5`)
+	f.Add("COMMENT1", "order[1]=&")
+	f.Add("RESPMSG", "")
+	f.Fuzz(func(t *testing.T, name string, value string) {
+		if name == "" || strings.ContainsAny(name, "[]=&") || strings.TrimSpace(name) != name {
+			t.Skip("not a Payflow field name")
+		}
+		got, err := parseNameValues(encodeNameValues([]nameValue{{name, value}, {"RESULT", "0"}}))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got[name] != value || got["RESULT"] != "0" {
+			t.Errorf("Got %q, want %q", got, map[string]string{name: value, "RESULT": "0"})
+		}
+	})
+}
+
+func FuzzParseNameValues(f *testing.F) {
+	f.Add("RESULT=0&PNREF=A10A0A0A0A0A&RESPMSG=Approved")
+	f.Add("RESULT[1]=0&RESPMSG[12]=Declined&A=B")
+	f.Add("RESPMSG[10]=abc")
+	f.Fuzz(func(t *testing.T, body string) {
+		// malformed responses are rejected, never panic
+		parseNameValues(body)
+	})
+}
@@ -0,0 +1,102 @@
+package paypalpayflow
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestEncodeNameValues(t *testing.T) {
+	cases := []struct {
+		label string
+		in    []nameValue
+		want  string
+	}{
+		{"No fields", nil, ""},
+		{"Plain values", []nameValue{{"TRXTYPE", "A"}, {"AMT", "1.00"}}, "TRXTYPE[1]=A&AMT[4]=1.00"},
+		{"Empty value", []nameValue{{"COMMENT1", ""}}, "COMMENT1[0]="},
+		{"Delimiters", []nameValue{{"BILLTOSTREET", `1 "A&B" St=5`}, {"TENDER", "C"}}, `BILLTOSTREET[12]=1 "A&B" St=5&TENDER[1]=C`},
+		{"Multibyte value", []nameValue{{"BILLTOLASTNAME", "Zoë"}}, "BILLTOLASTNAME[4]=Zoë"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := encodeNameValues(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseNameValues(t *testing.T) {
+	cases := []struct {
+		label string
+		in    string
+		want  Response
+	}{
+		{"Empty", "", Response{}},
+		{"Plain values", "RESULT=0&PNREF=A10A0A0A0A0A&RESPMSG=Approved", Response{"RESULT": "0", "PNREF": "A10A0A0A0A0A", "RESPMSG": "Approved"}},
+		{"Value containing equals", "RESULT=0&RESPMSG=Approved: AVSADDR=Y", Response{"RESULT": "0", "RESPMSG": "Approved: AVSADDR=Y"}},
+		{"Length tags", "RESULT[1]=0&RESPMSG[12]=Declined&A=B&PNREF=1", Response{"RESULT": "0", "RESPMSG": "Declined&A=B", "PNREF": "1"}},
+		{"Empty values", "RESPMSG=&PREFPSMSG[0]=", Response{"RESPMSG": "", "PREFPSMSG": ""}},
+		{"Trailing newline", "RESULT=0&PNREF=1\r\n", Response{"RESULT": "0", "PNREF": "1"}},
+		{"Trailing separator", "RESULT=0&", Response{"RESULT": "0"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got, err := parseNameValues(c.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseNameValuesErrors(t *testing.T) {
+	cases := []struct {
+		label string
+		in    string
+	}{
+		{"Missing value", "RESULT"},
+		{"Missing name", "=0"},
+		{"Unterminated length tag", "RESPMSG[5"},
+		{"Invalid length tag", "RESPMSG[x]=abc"},
+		{"Negative length tag", "RESPMSG[-1]=abc"},
+		{"Value shorter than tag", "RESPMSG[10]=abc"},
+		{"Value longer than tag", "RESPMSG[2]=abc&RESULT=0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if got, err := parseNameValues(c.in); err == nil {
+				t.Errorf("expected error, got %v", got)
+			}
+		})
+	}
+}
+
+func TestNameValuesRoundTrip(t *testing.T) {
+	fields := []nameValue{
+		{"BILLTOFIRSTNAME", `Bolt "&" Checkout`},
+		{"BILLTOSTREET", "7683 Railroad Street, Apt=2&3"},
+		{"COMMENT1", "order[1]=&"},
+		{"EMPTY", ""},
+	}
+
+	got, err := parseNameValues(encodeNameValues(fields))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := Response{}
+	for _, field := range fields {
+		want[field.name] = field.value
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -2,9 +2,9 @@ package paypalpayflow
 
 import (
 	"context"
-	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/BoltApp/sleet"
@@ -16,19 +16,19 @@ var (
 	_ sleet.ClientWithContext = &PaypalPayflowClient{}
 )
 
-func NewClient(partner string, password string, vendor string, user string, environment common.Environment) *PaypalPayflowClient {
-	return NewWithHttpClient(partner, password, vendor, user, environment, common.DefaultHttpClient())
+func NewClient(partner string, password string, vendor string, user string, environment common.Environment, options ...common.ClientOption) *PaypalPayflowClient {
+	return NewWithHttpClient(partner, password, vendor, user, environment, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient uses authentication with custom http client
-func NewWithHttpClient(partner string, password string, vendor string, user string, environment common.Environment, httpClient *http.Client) *PaypalPayflowClient {
+func NewWithHttpClient(partner string, password string, vendor string, user string, environment common.Environment, httpClient *http.Client, options ...common.ClientOption) *PaypalPayflowClient {
 	return &PaypalPayflowClient{
 		httpClient: httpClient,
 		partner:    partner,
 		password:   password,
 		vendor:     vendor,
 		user:       user,
-		url:        paypalURL(environment),
+		url:        common.NewClientOptions(options...).BaseURLOr(paypalURL(environment)),
 	}
 }
 
@@ -39,46 +39,100 @@ func paypalURL(env common.Environment) string {
 	return "https://payflowpro.paypal.com"
 }
 
-func (client *PaypalPayflowClient) sendRequest(ctx context.Context, request *Request) (*Response, *http.Response, error) {
-	data := ""
-	fields := map[string]interface{}{
-		"PARTNER":         client.partner,
-		"PWD":             client.password,
-		"VENDOR":          client.vendor,
-		"USER":            client.user,
-		"TRXTYPE":         request.TrxType,
-		"AMT":             request.Amount,
-		"CURRENCY":        request.Currency,
-		"VERBOSITY":       request.Verbosity,
-		"TENDER":          request.Tender,
-		"ACCT":            request.CreditCardNumber,
-		"EXPDATE":         request.CardExpirationDate,
-		"ORIGID":          request.OriginalID,
-		"BILLTOFIRSTNAME": request.BillToFirstName,
-		"BILLTOLASTNAME":  request.BillToLastName,
-		"BILLTOZIP":       request.BillToZIP,
-		"BILLTOSTATE":     request.BillToState,
-		"BILLTOSTREET":    request.BillToStreet,
-		"BILLTOSTREET2":   request.BillToStreet2,
-		"BILLTOCOUNTRY":   request.BillToCountry,
-		"CARDONFILE":      request.CardOnFile,
-		"TXID":            request.TxID,
-		"COMMENT1":        request.Comment1,
-	}
-	for k, v := range fields {
-		switch v := v.(type) {
-		case string:
-			data = data + fmt.Sprintf("&%s=%s", k, v)
-		case *string:
-			if v != nil {
-				data = data + fmt.Sprintf("&%s=%s", k, *v)
-			}
-		default:
-			continue
+// requestFields returns the fields of a request, with the credentials of the client first. Unset fields are omitted.
+func (client *PaypalPayflowClient) requestFields(request *Request) []nameValue {
+	fields := []nameValue{
+		{"PARTNER", client.partner},
+		{"PWD", client.password},
+		{"VENDOR", client.vendor},
+		{"USER", client.user},
+		{"TRXTYPE", request.TrxType},
+	}
+	optionalFields := []struct {
+		name  string
+		value *string
+	}{
+		{"AMT", request.Amount},
+		{"CURRENCY", request.Currency},
+		{"VERBOSITY", request.Verbosity},
+		{"TENDER", request.Tender},
+		{"ACCT", request.CreditCardNumber},
+		{"EXPDATE", request.CardExpirationDate},
+		{"ORIGID", request.OriginalID},
+		{"BILLTOFIRSTNAME", request.BillToFirstName},
+		{"BILLTOLASTNAME", request.BillToLastName},
+		{"BILLTOZIP", request.BillToZIP},
+		{"BILLTOSTATE", request.BillToState},
+		{"BILLTOSTREET", request.BillToStreet},
+		{"BILLTOSTREET2", request.BillToStreet2},
+		{"BILLTOCOUNTRY", request.BillToCountry},
+		{"CARDONFILE", request.CardOnFile},
+		{"TXID", request.TxID},
+		{"COMMENT1", request.Comment1},
+		{"PONUM", request.PONumber},
+		{"TAXAMT", request.TaxAmount},
+		{"TAXEXEMPT", request.TaxExempt},
+		{"SHIPFROMZIP", request.ShipFromZIP},
+		{"CVV2", request.CVV2},
+		{"BILLTOCITY", request.BillToCity},
+		{"BILLTOPHONENUM", request.BillToPhoneNumber},
+		{"EMAIL", request.Email},
+		{"SHIPTOFIRSTNAME", request.ShipToFirstName},
+		{"SHIPTOLASTNAME", request.ShipToLastName},
+		{"SHIPTOSTREET", request.ShipToStreet},
+		{"SHIPTOSTREET2", request.ShipToStreet2},
+		{"SHIPTOCITY", request.ShipToCity},
+		{"SHIPTOSTATE", request.ShipToState},
+		{"SHIPTOZIP", request.ShipToZIP},
+		{"SHIPTOCOUNTRY", request.ShipToCountry},
+		{"SHIPTOPHONENUM", request.ShipToPhoneNumber},
+		{"SHIPTOEMAIL", request.ShipToEmail},
+		{"RECURRING", request.Recurring},
+		{"AUTHSTATUS3DS", request.AuthStatus3DS},
+		{"CAVV", request.CAVV},
+		{"ECI", request.ECI},
+		{"XID", request.XID},
+		{"THREEDSVERSION", request.ThreeDSVersion},
+		{"DSTRANSACTIONID", request.DSTransactionID},
+		{"CUSTREF", request.CustomerReference},
+		{"DISCOUNT", request.DiscountAmount},
+		{"FREIGHTAMT", request.FreightAmount},
+		{"DUTYAMT", request.DutyAmount},
+	}
+	for _, field := range optionalFields {
+		if field.value != nil {
+			fields = append(fields, nameValue{field.name, *field.value})
+		}
+	}
+	for i, item := range request.LineItems {
+		fields = append(fields, lineItemFields(i+1, item)...)
+	}
+	return fields
+}
+
+// lineItemFields returns the L_ fields of a line item, suffixed with its number
+func lineItemFields(number int, item LineItem) []nameValue {
+	var fields []nameValue
+	for _, field := range []nameValue{
+		{"L_DESC", item.Description},
+		{"L_PRODCODE", item.ProductCode},
+		{"L_COST", item.UnitPrice},
+		{"L_QTY", item.Quantity},
+		{"L_AMT", item.Amount},
+		{"L_TAXAMT", item.TaxAmount},
+		{"L_DISCOUNT", item.DiscountAmount},
+		{"L_UOM", item.UnitOfMeasure},
+		{"L_COMMCODE", item.CommodityCode},
+	} {
+		if field.value != "" {
+			fields = append(fields, nameValue{field.name + strconv.Itoa(number), field.value})
 		}
 	}
+	return fields
+}
 
-	data = strings.TrimLeft(data, "&")
+func (client *PaypalPayflowClient) sendRequest(ctx context.Context, request *Request) (*Response, *http.Response, error) {
+	data := encodeNameValues(client.requestFields(request))
 
 	req, err := http.NewRequestWithContext(ctx, "POST", client.url, strings.NewReader(data))
 	if err != nil {
@@ -100,13 +154,9 @@ func (client *PaypalPayflowClient) sendRequest(ctx context.Context, request *Req
 		return nil, nil, err
 	}
 
-	response := make(Response)
-	for _, line := range strings.Split(string(bodyText), "&") {
-		line := strings.Split(strings.TrimSpace(line), "=")
-		if len(line) != 2 {
-			continue
-		}
-		response[line[0]] = line[1]
+	response, err := parseNameValues(string(bodyText))
+	if err != nil {
+		return nil, nil, err
 	}
 
 	return &response, resp, nil
@@ -119,28 +169,22 @@ func (client *PaypalPayflowClient) Authorize(request *sleet.AuthorizationRequest
 
 // AuthorizeWithContext a transaction. This transaction must be captured to receive funds
 func (client *PaypalPayflowClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	response, httpResponse, err := client.sendRequest(ctx, buildAuthorizeParams(request))
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	request, err := sleet.PrepareLevel3Data(request)
 	if err != nil {
 		return nil, err
 	}
-
-	responseHeader := sleet.GetHTTPResponseHeader(request.Options, *httpResponse)
-	transactionID, ok1 := (*response)[transactionFieldName]
-	result, ok2 := (*response)[resultFieldName]
-	if ok1 && ok2 && result == successResponse {
-		return &sleet.AuthorizationResponse{
-			Success:              true,
-			TransactionReference: transactionID,
-			StatusCode:           httpResponse.StatusCode,
-			Header:               responseHeader,
-		}, nil
+	response, httpResponse, err := client.sendRequest(ctx, buildAuthorizeParams(request))
+	if err != nil {
+		return nil, err
 	}
 
-	return &sleet.AuthorizationResponse{
-		ErrorCode:  result,
-		StatusCode: httpResponse.StatusCode,
-		Header:     responseHeader,
-	}, nil
+	authResponse := translateAuthorizeResponse(*response)
+	authResponse.StatusCode = httpResponse.StatusCode
+	authResponse.Header = sleet.GetHTTPResponseHeader(request.Options, *httpResponse)
+	return authResponse, nil
 }
 
 // Capture an authorized transaction
@@ -176,6 +220,9 @@ func (client *PaypalPayflowClient) Void(request *sleet.VoidRequest) (*sleet.Void
 
 // VoidWithContext an authorized transaction
 func (client *PaypalPayflowClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
 	response, _, err := client.sendRequest(ctx, buildVoidParams(request))
 	if err != nil {
 		return nil, err
@@ -200,6 +247,9 @@ func (client *PaypalPayflowClient) Refund(request *sleet.RefundRequest) (*sleet.
 
 // RefundWithContext a captured transaction
 func (client *PaypalPayflowClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
 	response, _, err := client.sendRequest(ctx, buildRefundParams(request))
 	if err != nil {
 		return nil, err
@@ -0,0 +1,52 @@
+package paypalpayflow
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient("partner", "pass", "vendor", "user", common.Production, &http.Client{Transport: transport},
+		common.WithBaseURL("http://localhost:8080"))
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:8080"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
+
+func TestAuthorizeValidatesLevel3Data(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient("partner", "pass", "vendor", "user", common.Sandbox, &http.Client{Transport: transport})
+
+	request := sleet_testing.BaseAuthorizationRequest()
+	request.Level3Data = sleet_testing.BaseLevel3Data()
+	request.Options = map[string]interface{}{sleet.Level3ValidationOption: sleet.Level3ValidationOptions{}}
+	_, err := client.Authorize(request)
+
+	var validationErr *sleet.Level3ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Got error %v, want a Level 3 validation error", err)
+	}
+	if len(transport.urls) != 0 {
+		t.Errorf("Got requests to %q, want none", transport.urls)
+	}
+}
@@ -2,14 +2,41 @@ package paypalpayflow
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 )
 
+const (
+	maxNameLength                = 30
+	maxPONumberLength            = 25
+	maxEmailLength               = 127
+	maxPhoneNumberLength         = 20
+	maxCustomerReferenceLength   = 12
+	maxLineItemDescriptionLength = 35
+	maxLineItemCodeLength        = 12
+)
+
+// addressProfile follows the Payflow Pro field limits. Values are sent with length tags, so the '&', '=' and '"'
+// delimiters of the name-value format are kept. Payflow ZIP codes are 5 to 9 digits without dashes or spaces, so a
+// ZIP+4 code such as "94103-1234" is sent as "941031234".
+var addressProfile = common.AddressProfile{
+	StreetAddress1MaxLength:     150,
+	StreetAddress2MaxLength:     150,
+	LocalityMaxLength:           45,
+	RegionCodeMaxLength:         10,
+	PostalCodeMaxLength:         9,
+	CountryCodeMaxLength:        3,
+	ASCIIOnly:                   true,
+	PostalCodeRemovedCharacters: "- ",
+}
+
 var (
 	defaultVerbosity    string = "HIGH"
 	defaultTender       string = "C"
 	defaultMIT          string = "MIT"
+	recurringFlag       string = "Y"
 	MITUnscheduled      string = "MITR"
 	CITUnscheduled      string = "CITU"
 	CITInitial          string = "CITI"
@@ -20,14 +47,20 @@ var (
 func buildAuthorizeParams(request *sleet.AuthorizationRequest) *Request {
 	expirationDate := fmt.Sprintf("%02d%02d", request.CreditCard.ExpirationMonth, request.CreditCard.ExpirationYear%100)
 	amount := sleet.AmountToDecimalString(&request.Amount)
+	billingAddress := common.NormalizeAddress(request.BillingAddress, addressProfile)
+	firstName := common.NormalizeAddressValue(request.CreditCard.FirstName, addressProfile, maxNameLength)
+	lastName := common.NormalizeAddressValue(request.CreditCard.LastName, addressProfile, maxNameLength)
 	var CardOnFile *string = nil
+	var recurring *string = nil
 
 	if request.ProcessingInitiator != nil {
 		switch *request.ProcessingInitiator {
 		case sleet.ProcessingInitiatorTypeInitialRecurring:
 			CardOnFile = &CITInitialRecurring
+			recurring = &recurringFlag
 		case sleet.ProcessingInitiatorTypeFollowingRecurring:
 			CardOnFile = &MITRecurring
+			recurring = &recurringFlag
 		case sleet.ProcessingInitiatorTypeStoredMerchantInitiated:
 			CardOnFile = &MITUnscheduled
 		case sleet.ProcessingInitiatorTypeStoredCardholderInitiated:
@@ -37,7 +70,7 @@ func buildAuthorizeParams(request *sleet.AuthorizationRequest) *Request {
 		}
 	}
 
-	return &Request{
+	params := &Request{
 		TrxType:            AUTHORIZATION,
 		Amount:             &amount,
 		Currency:           &request.Amount.Currency,
@@ -45,22 +78,123 @@ func buildAuthorizeParams(request *sleet.AuthorizationRequest) *Request {
 		CardExpirationDate: &expirationDate,
 		Verbosity:          &defaultVerbosity,
 		Tender:             &defaultTender,
-		BillToFirstName:    &request.CreditCard.FirstName,
-		BillToLastName:     &request.CreditCard.LastName,
-		BillToZIP:          request.BillingAddress.PostalCode,
-		BillToState:        request.BillingAddress.RegionCode,
-		BillToStreet:       request.BillingAddress.StreetAddress1,
-		BillToStreet2:      request.BillingAddress.StreetAddress2,
-		BillToCountry:      request.BillingAddress.CountryCode,
+		BillToFirstName:    &firstName,
+		BillToLastName:     &lastName,
+		BillToZIP:          billingAddress.PostalCode,
+		BillToState:        billingAddress.RegionCode,
+		BillToStreet:       billingAddress.StreetAddress1,
+		BillToStreet2:      billingAddress.StreetAddress2,
+		BillToCountry:      billingAddress.CountryCode,
+		BillToCity:         billingAddress.Locality,
 		CardOnFile:         CardOnFile,
+		Recurring:          recurring,
 		TxID:               request.PreviousExternalTransactionID,
 		Comment1:           &request.MerchantOrderReference,
 	}
+	if request.CreditCard.CVV != "" {
+		params.CVV2 = &request.CreditCard.CVV
+	}
+	if request.BillingAddress != nil {
+		params.BillToPhoneNumber = normalizeOptionalValue(request.BillingAddress.PhoneNumber, maxPhoneNumberLength)
+		params.Email = normalizeOptionalValue(request.BillingAddress.Email, maxEmailLength)
+	}
+	if request.ShippingAddress != nil {
+		addShipTo(params, request.ShippingAddress, firstName, lastName)
+	}
+	if request.ThreeDS != nil {
+		addThreeDS(params, request.ThreeDS, request.ECI)
+	}
+	addLevel3Data(params, request.Level3Data)
+	addLevel2Data(params, request.Level2Data)
+	return params
+}
+
+// normalizeOptionalValue applies the address profile to a value that may be missing
+func normalizeOptionalValue(value *string, maxLength int) *string {
+	if value == nil {
+		return nil
+	}
+	normalized := common.NormalizeAddressValue(*value, addressProfile, maxLength)
+	return &normalized
+}
+
+// addShipTo sets the ship-to fields. Addresses have no name, so the cardholder name is used.
+func addShipTo(params *Request, shippingAddress *sleet.Address, firstName string, lastName string) {
+	address := common.NormalizeAddress(shippingAddress, addressProfile)
+	params.ShipToFirstName = &firstName
+	params.ShipToLastName = &lastName
+	params.ShipToStreet = address.StreetAddress1
+	params.ShipToStreet2 = address.StreetAddress2
+	params.ShipToCity = address.Locality
+	params.ShipToState = address.RegionCode
+	params.ShipToZIP = address.PostalCode
+	params.ShipToCountry = address.CountryCode
+	params.ShipToPhoneNumber = normalizeOptionalValue(shippingAddress.PhoneNumber, maxPhoneNumberLength)
+	params.ShipToEmail = normalizeOptionalValue(shippingAddress.Email, maxEmailLength)
+}
+
+// addThreeDS sends the result of a 3DS authentication performed outside of Payflow. The XID is only set by 3DS 1,
+// 3DS 2 identifies the authentication by its directory server transaction ID.
+func addThreeDS(params *Request, threeDS *sleet.ThreeDS, eci string) {
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	params.AuthStatus3DS = optional(threeDS.PAResStatus)
+	params.CAVV = optional(threeDS.CAVV)
+	params.ECI = optional(eci)
+	params.XID = optional(threeDS.XID)
+	params.ThreeDSVersion = optional(threeDS.Version)
+	params.DSTransactionID = optional(threeDS.DSTransactionID)
+}
+
+// addLevel3Data sets the purchasing card line items and order amounts. The destination is only used when no shipping
+// address was sent, and the tax amount is overridden by the Level 2 data.
+func addLevel3Data(params *Request, level3 *sleet.Level3Data) {
+	if level3 == nil {
+		return
+	}
+	discountAmount := sleet.AmountToDecimalString(&level3.DiscountAmount)
+	freightAmount := sleet.AmountToDecimalString(&level3.ShippingAmount)
+	dutyAmount := sleet.AmountToDecimalString(&level3.DutyAmount)
+	taxAmount := sleet.AmountToDecimalString(&level3.TaxAmount)
+	params.DiscountAmount = &discountAmount
+	params.FreightAmount = &freightAmount
+	params.DutyAmount = &dutyAmount
+	params.TaxAmount = &taxAmount
+	if level3.CustomerReference != "" {
+		customerReference := common.NormalizeAddressValue(level3.CustomerReference, addressProfile, maxCustomerReferenceLength)
+		params.CustomerReference = &customerReference
+	}
+	if params.ShipToZIP == nil && level3.DestinationPostalCode != "" {
+		shipToZIP := common.NormalizePostalCode(level3.DestinationPostalCode, addressProfile)
+		params.ShipToZIP = &shipToZIP
+	}
+	if params.ShipToCountry == nil && level3.DestinationCountryCode != "" {
+		shipToCountry := common.NormalizeAddressValue(level3.DestinationCountryCode, addressProfile, addressProfile.CountryCodeMaxLength)
+		params.ShipToCountry = &shipToCountry
+	}
+
+	for _, item := range level3.LineItems {
+		params.LineItems = append(params.LineItems, LineItem{
+			Description:    common.NormalizeAddressValue(item.Description, addressProfile, maxLineItemDescriptionLength),
+			ProductCode:    common.NormalizeAddressValue(item.ProductCode, addressProfile, maxLineItemCodeLength),
+			UnitPrice:      sleet.AmountToDecimalString(&item.UnitPrice),
+			Quantity:       strconv.FormatInt(item.Quantity, 10),
+			Amount:         sleet.AmountToDecimalString(&item.TotalAmount),
+			TaxAmount:      sleet.AmountToDecimalString(&item.ItemTaxAmount),
+			DiscountAmount: sleet.AmountToDecimalString(&item.ItemDiscountAmount),
+			UnitOfMeasure:  common.NormalizeAddressValue(item.UnitOfMeasure, addressProfile, maxLineItemCodeLength),
+			CommodityCode:  common.NormalizeAddressValue(item.CommodityCode, addressProfile, maxLineItemCodeLength),
+		})
+	}
 }
 
 func buildCaptureParams(request *sleet.CaptureRequest) *Request {
 	amount := sleet.AmountToDecimalString(request.Amount)
-	return &Request{
+	params := &Request{
 		TrxType:    CAPTURE,
 		OriginalID: &request.TransactionReference,
 		Verbosity:  &defaultVerbosity,
@@ -68,6 +202,8 @@ func buildCaptureParams(request *sleet.CaptureRequest) *Request {
 		Amount:     &amount,
 		Currency:   &request.Amount.Currency,
 	}
+	addLevel2Data(params, request.Level2Data)
+	return params
 }
 
 func buildVoidParams(request *sleet.VoidRequest) *Request {
@@ -98,3 +234,25 @@ func buildRefundParams(request *sleet.RefundRequest) *Request {
 		Currency:   currency,
 	}
 }
+
+// addLevel2Data sets the purchasing card fields. Payflow has no field for the merchant tax ID.
+func addLevel2Data(params *Request, level2 *sleet.Level2Data) {
+	if level2 == nil {
+		return
+	}
+	taxAmount := sleet.AmountToDecimalString(&level2.TaxAmount)
+	taxExempt := "N"
+	if level2.TaxExempt {
+		taxExempt = "Y"
+	}
+	params.TaxAmount = &taxAmount
+	params.TaxExempt = &taxExempt
+	if level2.PurchaseOrderNumber != "" {
+		poNumber := common.NormalizeAddressValue(level2.PurchaseOrderNumber, addressProfile, maxPONumberLength)
+		params.PONumber = &poNumber
+	}
+	if level2.ShipFromPostalCode != "" {
+		shipFromZIP := common.NormalizePostalCode(level2.ShipFromPostalCode, addressProfile)
+		params.ShipFromZIP = &shipFromZIP
+	}
+}
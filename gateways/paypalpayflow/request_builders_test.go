@@ -6,6 +6,7 @@ import (
 	"github.com/go-test/deep"
 
 	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
 
 	sleet_testing "github.com/BoltApp/sleet/testing"
 )
@@ -58,6 +59,8 @@ func TestBuildAuthRequest(t *testing.T) {
 				BillToStreet:       visaBase.BillingAddress.StreetAddress1,
 				BillToStreet2:      visaBase.BillingAddress.StreetAddress2,
 				BillToCountry:      visaBase.BillingAddress.CountryCode,
+				BillToCity:         visaBase.BillingAddress.Locality,
+				CVV2:               &visaBase.CreditCard.CVV,
 				Comment1:           &visaBase.MerchantOrderReference,
 			},
 		},
@@ -79,6 +82,8 @@ func TestBuildAuthRequest(t *testing.T) {
 				BillToStreet:       visaBase.BillingAddress.StreetAddress1,
 				BillToStreet2:      visaBase.BillingAddress.StreetAddress2,
 				BillToCountry:      visaBase.BillingAddress.CountryCode,
+				BillToCity:         visaBase.BillingAddress.Locality,
+				CVV2:               &visaBase.CreditCard.CVV,
 				Comment1:           &visaBase.MerchantOrderReference,
 			},
 		},
@@ -100,6 +105,8 @@ func TestBuildAuthRequest(t *testing.T) {
 				BillToStreet:       visaBase.BillingAddress.StreetAddress1,
 				BillToStreet2:      visaBase.BillingAddress.StreetAddress2,
 				BillToCountry:      visaBase.BillingAddress.CountryCode,
+				BillToCity:         visaBase.BillingAddress.Locality,
+				CVV2:               &visaBase.CreditCard.CVV,
 				Comment1:           &visaBase.MerchantOrderReference,
 			},
 		},
@@ -121,6 +128,8 @@ func TestBuildAuthRequest(t *testing.T) {
 				BillToStreet:       visaBase.BillingAddress.StreetAddress1,
 				BillToStreet2:      visaBase.BillingAddress.StreetAddress2,
 				BillToCountry:      visaBase.BillingAddress.CountryCode,
+				BillToCity:         visaBase.BillingAddress.Locality,
+				CVV2:               &visaBase.CreditCard.CVV,
 				Comment1:           &visaBase.MerchantOrderReference,
 			},
 		},
@@ -136,8 +145,143 @@ func TestBuildAuthRequest(t *testing.T) {
 	}
 }
 
+func TestBuildAuthRequestOptionalFields(t *testing.T) {
+	t.Run("Shipping and contact", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequestWithEmailPhoneNumber()
+		request.ShippingAddress = &sleet.Address{
+			StreetAddress1: common.SPtr("1 Market St & Main"),
+			Locality:       common.SPtr("San Francisco"),
+			RegionCode:     common.SPtr("CA"),
+			PostalCode:     common.SPtr("94105"),
+			CountryCode:    common.SPtr("US"),
+		}
+
+		got := buildAuthorizeParams(request)
+		want := map[string]*string{
+			"Email":             common.SPtr("test@bolt.com"),
+			"BillToPhoneNumber": common.SPtr("555-555-5555"),
+			"ShipToFirstName":   common.SPtr("Bolt"),
+			"ShipToLastName":    common.SPtr("Checkout"),
+			"ShipToStreet":      common.SPtr("1 Market St & Main"),
+			"ShipToCity":        common.SPtr("San Francisco"),
+			"ShipToState":       common.SPtr("CA"),
+			"ShipToZIP":         common.SPtr("94105"),
+			"ShipToCountry":     common.SPtr("US"),
+		}
+		gotFields := map[string]*string{
+			"Email":             got.Email,
+			"BillToPhoneNumber": got.BillToPhoneNumber,
+			"ShipToFirstName":   got.ShipToFirstName,
+			"ShipToLastName":    got.ShipToLastName,
+			"ShipToStreet":      got.ShipToStreet,
+			"ShipToCity":        got.ShipToCity,
+			"ShipToState":       got.ShipToState,
+			"ShipToZIP":         got.ShipToZIP,
+			"ShipToCountry":     got.ShipToCountry,
+		}
+		if diff := deep.Equal(gotFields, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("ZIP+4 postal code", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.BillingAddress.PostalCode = common.SPtr("94103-1234")
+
+		got := buildAuthorizeParams(request)
+		if want := "941031234"; common.SafeStr(got.BillToZIP) != want {
+			t.Errorf("Got %q, want %q", common.SafeStr(got.BillToZIP), want)
+		}
+	})
+
+	t.Run("3DS", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.ThreeDS = sleet_testing.Base3DS()
+		request.ECI = "05"
+
+		got := buildAuthorizeParams(request)
+		want := []*string{common.SPtr("pares-status"), common.SPtr("cavv"), common.SPtr("05"), common.SPtr("xid"), common.SPtr("version"), common.SPtr("ds-transaction-id")}
+		if diff := deep.Equal([]*string{got.AuthStatus3DS, got.CAVV, got.ECI, got.XID, got.ThreeDSVersion, got.DSTransactionID}, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Recurring", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		initiator := sleet.ProcessingInitiatorTypeFollowingRecurring
+		request.ProcessingInitiator = &initiator
+		request.PreviousExternalTransactionID = common.SPtr("000000000000001")
+
+		got := buildAuthorizeParams(request)
+		if diff := deep.Equal([]*string{got.CardOnFile, got.Recurring, got.TxID}, []*string{common.SPtr("MITR"), common.SPtr("Y"), common.SPtr("000000000000001")}); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Level 3", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.Level3Data = sleet_testing.BaseLevel3Data()
+
+		got := buildAuthorizeParams(request)
+		want := &Request{
+			CustomerReference: common.SPtr("customer"),
+			DiscountAmount:    common.SPtr("2.00"),
+			FreightAmount:     common.SPtr("3.00"),
+			DutyAmount:        common.SPtr("4.00"),
+			TaxAmount:         common.SPtr("1.00"),
+			ShipToZIP:         common.SPtr("94105"),
+			ShipToCountry:     common.SPtr("US"),
+			LineItems: []LineItem{{
+				Description:    "pot",
+				ProductCode:    "abc",
+				UnitPrice:      "5.00",
+				Quantity:       "2",
+				Amount:         "10.00",
+				TaxAmount:      "0.00",
+				DiscountAmount: "0.00",
+				UnitOfMeasure:  "count",
+				CommodityCode:  "cmd",
+			}},
+		}
+		gotLevel3 := &Request{
+			CustomerReference: got.CustomerReference,
+			DiscountAmount:    got.DiscountAmount,
+			FreightAmount:     got.FreightAmount,
+			DutyAmount:        got.DutyAmount,
+			TaxAmount:         got.TaxAmount,
+			ShipToZIP:         got.ShipToZIP,
+			ShipToCountry:     got.ShipToCountry,
+			LineItems:         got.LineItems,
+		}
+		if diff := deep.Equal(gotLevel3, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
+
+func TestRequestFieldsLineItems(t *testing.T) {
+	client := NewClient("partner", "password", "vendor", "user", common.Sandbox)
+	request := &Request{
+		TrxType: AUTHORIZATION,
+		LineItems: []LineItem{
+			{Description: "pot", Quantity: "2", Amount: "10.00"},
+			{Description: "vase & lid", Quantity: "1", Amount: "5.00"},
+		},
+	}
+
+	got := encodeNameValues(client.requestFields(request))
+	want := "PARTNER[7]=partner&PWD[8]=password&VENDOR[6]=vendor&USER[4]=user&TRXTYPE[1]=A" +
+		"&L_DESC1[3]=pot&L_QTY1[1]=2&L_AMT1[5]=10.00&L_DESC2[10]=vase & lid&L_QTY2[1]=1&L_AMT2[4]=5.00"
+	if got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
 func TestBuildCaptureRequest(t *testing.T) {
 	base := sleet_testing.BaseCaptureRequest()
+	withLevel2 := sleet_testing.BaseCaptureRequest()
+	withLevel2.Level2Data = sleet_testing.BaseLevel2Data()
+	withLevel2.Level2Data.TaxExempt = true
 	cases := []struct {
 		label string
 		in    *sleet.CaptureRequest
@@ -155,6 +299,22 @@ func TestBuildCaptureRequest(t *testing.T) {
 				Currency:   &defaultTestCurrency,
 			},
 		},
+		{
+			"Capture Request with Level 2 data",
+			withLevel2,
+			Request{
+				TrxType:     CAPTURE,
+				OriginalID:  &OriginalID,
+				Verbosity:   &defaultTestVerbosity,
+				Tender:      &defaultTestTender,
+				Amount:      &defaultTestAmount,
+				Currency:    &defaultTestCurrency,
+				PONumber:    common.SPtr("PO-12345"),
+				TaxAmount:   common.SPtr("8.25"),
+				TaxExempt:   common.SPtr("Y"),
+				ShipFromZIP: common.SPtr("94105"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -1 +1,134 @@
 package paypalpayflow
+
+import (
+	"strings"
+
+	"github.com/BoltApp/sleet"
+)
+
+// Codes taken from: https://developer.paypal.com/api/nvp-soap/payflow/integration-guide/transaction-responses/
+var declineReasonMap = map[string]sleet.DeclineReason{
+	"1":    sleet.DeclineReasonProcessingError,
+	"2":    sleet.DeclineReasonTransactionNotPermitted,
+	"3":    sleet.DeclineReasonTransactionNotPermitted,
+	"4":    sleet.DeclineReasonInvalidAmount,
+	"5":    sleet.DeclineReasonTransactionNotPermitted,
+	"7":    sleet.DeclineReasonProcessingError,
+	"12":   sleet.DeclineReasonDoNotHonor,
+	"13":   sleet.DeclineReasonCallIssuer,
+	"23":   sleet.DeclineReasonInvalidCardNumber,
+	"24":   sleet.DeclineReasonInvalidExpirationDate,
+	"30":   sleet.DeclineReasonDuplicateTransaction,
+	"50":   sleet.DeclineReasonInsufficientFunds,
+	"51":   sleet.DeclineReasonLimitExceeded,
+	"104":  sleet.DeclineReasonIssuerUnavailable,
+	"112":  sleet.DeclineReasonAVSFailure,
+	"114":  sleet.DeclineReasonCVVFailure,
+	"125":  sleet.DeclineReasonSuspectedFraud,
+	"128":  sleet.DeclineReasonSuspectedFraud,
+	"150":  sleet.DeclineReasonIssuerUnavailable,
+	"151":  sleet.DeclineReasonIssuerUnavailable,
+	"1000": sleet.DeclineReasonProcessingError,
+}
+
+// translateDeclineReason converts a Payflow RESULT to its equivalent Sleet decline reason.
+func translateDeclineReason(result string) sleet.DeclineReason {
+	reason, ok := declineReasonMap[result]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
+
+// avsChecks are the address and ZIP results of AVSADDR and AVSZIP: Y for a match, N for no match and X when the
+// issuer does not support AVS
+type avsChecks struct {
+	address string
+	zip     string
+}
+
+var avsMap = map[avsChecks]sleet.AVSResponse{
+	{"Y", "Y"}: sleet.AVSresponseZipMatchAddressMatch,
+	{"Y", "N"}: sleet.AVSResponseZipNoMatchAddressMatch,
+	{"N", "Y"}: sleet.AVSResponseZip5MatchAddressNoMatch,
+	{"N", "N"}: sleet.AVSResponseNoMatch,
+	{"Y", "X"}: sleet.AVSResponseZipUnverifiedAddressMatch,
+	{"X", "Y"}: sleet.AVSResponseZipMatchAddressUnverified,
+	{"N", "X"}: sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{"X", "N"}: sleet.AVSResponseZipNoMatchAddressUnverified,
+	{"X", "X"}: sleet.AVSResponseUnsupported,
+	{"", ""}:   sleet.AVSResponseSkipped,
+}
+
+// internationalAvsMap overrides avsMap for cards issued outside the U.S., reported with IAVS=Y
+var internationalAvsMap = map[avsChecks]sleet.AVSResponse{
+	{"Y", "Y"}: sleet.AVSResponseNonUsZipMatchAddressMatch,
+	{"N", "N"}: sleet.AVSResponseNonUsZipNoMatchAddressNoMatch,
+	{"Y", "X"}: sleet.AVSResponseNonUsZipUnverifiedAddressMatch,
+}
+
+// translateAvs converts the AVSADDR, AVSZIP and IAVS results to their equivalent Sleet standard code
+func translateAvs(address string, zip string, international string) sleet.AVSResponse {
+	checks := avsChecks{address: address, zip: zip}
+	if international == "Y" {
+		if sleetCode, ok := internationalAvsMap[checks]; ok {
+			return sleetCode
+		}
+	}
+	sleetCode, ok := avsMap[checks]
+	if !ok {
+		return sleet.AVSResponseUnknown
+	}
+	return sleetCode
+}
+
+var cvvMap = map[string]sleet.CVVResponse{
+	"Y": sleet.CVVResponseMatch,
+	"N": sleet.CVVResponseNoMatch,
+	"X": sleet.CVVResponseUnsupported,
+	"":  sleet.CVVResponseSkipped,
+}
+
+// translateCvv converts a CVV2MATCH result to its equivalent Sleet standard code
+func translateCvv(cvv2Match string) sleet.CVVResponse {
+	sleetCode, ok := cvvMap[cvv2Match]
+	if !ok {
+		return sleet.CVVResponseUnknown
+	}
+	return sleetCode
+}
+
+// translateAuthorizeResponse converts the response of an authorization. The raw AVS result is AVSADDR:AVSZIP:IAVS, or
+// empty when no AVS result was returned.
+func translateAuthorizeResponse(response Response) *sleet.AuthorizationResponse {
+	result := response[resultFieldName]
+	avsAddress := response[avsAddressFieldName]
+	avsZIP := response[avsZIPFieldName]
+	internationalAVS := response[internationalAVSFieldName]
+	cvv2Match := response[cvvFieldName]
+
+	authResponse := &sleet.AuthorizationResponse{
+		Response:              result,
+		Message:               response[messageFieldName],
+		AvsResult:             translateAvs(avsAddress, avsZIP, internationalAVS),
+		CvvResult:             translateCvv(cvv2Match),
+		CvvResultRaw:          cvv2Match,
+		ExternalTransactionID: response[networkTransactionIDFieldName],
+	}
+	if avsAddress != "" || avsZIP != "" || internationalAVS != "" {
+		authResponse.AvsResultRaw = strings.Join([]string{avsAddress, avsZIP, internationalAVS}, ":")
+	}
+	if authCode := response[authCodeFieldName]; authCode != "" {
+		authResponse.Metadata = map[string]string{sleet.AuthCodeMetadata: authCode}
+	}
+
+	transactionID, ok := response[transactionFieldName]
+	if ok && result == successResponse {
+		authResponse.Success = true
+		authResponse.TransactionReference = transactionID
+		return authResponse
+	}
+	authResponse.ErrorCode = result
+	authResponse.DeclineReason = translateDeclineReason(result)
+	return authResponse
+}
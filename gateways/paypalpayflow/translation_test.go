@@ -0,0 +1,118 @@
+package paypalpayflow
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestTranslateAvs(t *testing.T) {
+	cases := []struct {
+		address       string
+		zip           string
+		international string
+		want          sleet.AVSResponse
+	}{
+		{"Y", "Y", "N", sleet.AVSresponseZipMatchAddressMatch},
+		{"Y", "N", "N", sleet.AVSResponseZipNoMatchAddressMatch},
+		{"N", "Y", "N", sleet.AVSResponseZip5MatchAddressNoMatch},
+		{"N", "N", "N", sleet.AVSResponseNoMatch},
+		{"Y", "X", "N", sleet.AVSResponseZipUnverifiedAddressMatch},
+		{"X", "Y", "N", sleet.AVSResponseZipMatchAddressUnverified},
+		{"N", "X", "N", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"X", "N", "N", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"X", "X", "X", sleet.AVSResponseUnsupported},
+		{"", "", "", sleet.AVSResponseSkipped},
+		{"Y", "Y", "Y", sleet.AVSResponseNonUsZipMatchAddressMatch},
+		{"N", "N", "Y", sleet.AVSResponseNonUsZipNoMatchAddressNoMatch},
+		{"Y", "X", "Y", sleet.AVSResponseNonUsZipUnverifiedAddressMatch},
+		{"Y", "N", "Y", sleet.AVSResponseZipNoMatchAddressMatch},
+		{"Y", "", "N", sleet.AVSResponseUnknown},
+		{"Fake", "Y", "N", sleet.AVSResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.address+c.zip+c.international, func(t *testing.T) {
+			got := translateAvs(c.address, c.zip, c.international)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateCvv(t *testing.T) {
+	cases := []struct {
+		in   string
+		want sleet.CVVResponse
+	}{
+		{"Y", sleet.CVVResponseMatch},
+		{"N", sleet.CVVResponseNoMatch},
+		{"X", sleet.CVVResponseUnsupported},
+		{"", sleet.CVVResponseSkipped},
+		{"Fake Result", sleet.CVVResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := translateCvv(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateAuthorizeResponse(t *testing.T) {
+	t.Run("Approved", func(t *testing.T) {
+		response := Response{
+			"RESULT":    "0",
+			"PNREF":     "A70A0B3C4D5E",
+			"RESPMSG":   "Approved",
+			"AUTHCODE":  "010101",
+			"AVSADDR":   "Y",
+			"AVSZIP":    "N",
+			"IAVS":      "N",
+			"CVV2MATCH": "Y",
+			"TXID":      "012345678901234",
+		}
+		want := &sleet.AuthorizationResponse{
+			Success:               true,
+			TransactionReference:  "A70A0B3C4D5E",
+			ExternalTransactionID: "012345678901234",
+			Response:              "0",
+			Message:               "Approved",
+			AvsResult:             sleet.AVSResponseZipNoMatchAddressMatch,
+			AvsResultRaw:          "Y:N:N",
+			CvvResult:             sleet.CVVResponseMatch,
+			CvvResultRaw:          "Y",
+			Metadata:              map[string]string{sleet.AuthCodeMetadata: "010101"},
+		}
+		if diff := deep.Equal(translateAuthorizeResponse(response), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		response := Response{
+			"RESULT":    "114",
+			"PNREF":     "A70A0B3C4D5F",
+			"RESPMSG":   "CVV2 Mismatch: 15004-This transaction cannot be processed.",
+			"CVV2MATCH": "N",
+		}
+		want := &sleet.AuthorizationResponse{
+			Response:      "114",
+			Message:       "CVV2 Mismatch: 15004-This transaction cannot be processed.",
+			ErrorCode:     "114",
+			DeclineReason: sleet.DeclineReasonCVVFailure,
+			AvsResult:     sleet.AVSResponseSkipped,
+			CvvResult:     sleet.CVVResponseNoMatch,
+			CvvResultRaw:  "N",
+		}
+		if diff := deep.Equal(translateAuthorizeResponse(response), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
@@ -19,9 +19,16 @@ const (
 )
 
 const (
-	successResponse      = "0"
-	transactionFieldName = "PNREF"
-	resultFieldName      = "RESULT"
+	successResponse               = "0"
+	transactionFieldName          = "PNREF"
+	resultFieldName               = "RESULT"
+	messageFieldName              = "RESPMSG"
+	authCodeFieldName             = "AUTHCODE"
+	networkTransactionIDFieldName = "TXID"
+	avsAddressFieldName           = "AVSADDR"
+	avsZIPFieldName               = "AVSZIP"
+	internationalAVSFieldName     = "IAVS"
+	cvvFieldName                  = "CVV2MATCH"
 )
 
 type Request struct {
@@ -43,6 +50,49 @@ type Request struct {
 	CardOnFile         *string
 	TxID               *string
 	Comment1           *string // merchant order reference
+	PONumber           *string
+	TaxAmount          *string
+	TaxExempt          *string // Y or N
+	ShipFromZIP        *string
+	CVV2               *string
+	BillToCity         *string
+	BillToPhoneNumber  *string
+	Email              *string
+	ShipToFirstName    *string
+	ShipToLastName     *string
+	ShipToStreet       *string
+	ShipToStreet2      *string
+	ShipToCity         *string
+	ShipToState        *string
+	ShipToZIP          *string
+	ShipToCountry      *string // country code
+	ShipToPhoneNumber  *string
+	ShipToEmail        *string
+	Recurring          *string // Y for recurring card on file transactions
+	AuthStatus3DS      *string // status of the 3DS authentication
+	CAVV               *string
+	ECI                *string
+	XID                *string // 3DS 1 transaction ID
+	ThreeDSVersion     *string
+	DSTransactionID    *string // 3DS 2 directory server transaction ID
+	CustomerReference  *string
+	DiscountAmount     *string
+	FreightAmount      *string
+	DutyAmount         *string
+	LineItems          []LineItem
+}
+
+// LineItem is a Level 3 line item, sent as L_ fields numbered from 1. Empty fields are omitted.
+type LineItem struct {
+	Description    string
+	ProductCode    string
+	UnitPrice      string
+	Quantity       string
+	Amount         string
+	TaxAmount      string
+	DiscountAmount string
+	UnitOfMeasure  string
+	CommodityCode  string
 }
 
 type Response map[string]string
@@ -1,6 +1,14 @@
 package rocketgate
 
-import "github.com/BoltApp/sleet/common"
+import (
+	"net"
+	"net/url"
+
+	"github.com/BoltApp/sleet/common"
+)
+
+// servletPath is the path of the gateway, relative to the base URL
+const servletPath = "/gateway/servlet/ServiceDispatcherAccess"
 
 func rocketgateTestMode(env common.Environment) bool {
 	if env == common.Production {
@@ -8,3 +16,24 @@ func rocketgateTestMode(env common.Environment) bool {
 	}
 	return true
 }
+
+// rocketgateGatewayURL returns the URL that replaces the hosts of the environment, or "" when baseURL is empty. The
+// SDK takes the port of the environment when the URL has none, so the port of its scheme is set.
+func rocketgateGatewayURL(baseURL string) string {
+	if baseURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(baseURL + servletPath)
+	if err != nil {
+		// the SDK answers requests to a URL it can not parse with an invalid URL reason
+		return baseURL + servletPath
+	}
+	if parsed.Port() == "" {
+		port := "443"
+		if parsed.Scheme == "http" {
+			port = "80"
+		}
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), port)
+	}
+	return parsed.String()
+}
@@ -41,6 +41,11 @@ var initatorTypeToBillingType = map[sleet.ProcessingInitiatorType]string{
 	sleet.ProcessingInitiatorTypeFollowingRecurring:        standardRebillOfMembership,
 }
 
+// setGatewayURL sends gatewayRequest to gatewayURL, or to the hosts of the environment when it is empty
+func setGatewayURL(gatewayRequest *request.GatewayRequest, gatewayURL string) {
+	gatewayRequest.Set(request.GATEWAY_URL, gatewayURL)
+}
+
 func buildAuthRequest(
 	merchantID string,
 	merchantPassword string,
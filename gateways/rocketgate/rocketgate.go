@@ -24,6 +24,8 @@ type RocketgateClient struct {
 	merchantPassword string
 	merchantAccount  *string
 	httpClient       *http.Client
+	// gatewayURL replaces the hosts of the environment when it is set
+	gatewayURL string
 }
 
 // NewClient creates a Rocketgate client
@@ -32,8 +34,9 @@ func NewClient(
 	merchantID string,
 	merchantPassword string,
 	merchantAccount *string,
+	options ...common.ClientOption,
 ) *RocketgateClient {
-	return NewWithHttpClient(env, merchantID, merchantPassword, merchantAccount, common.DefaultHttpClient())
+	return NewWithHttpClient(env, merchantID, merchantPassword, merchantAccount, common.DefaultHttpClient(), options...)
 }
 
 // NewWithHttpClient creates a Rocketgate client for custom behavior
@@ -43,6 +46,7 @@ func NewWithHttpClient(
 	merchantPassword string,
 	merchantAccount *string,
 	httpClient *http.Client,
+	options ...common.ClientOption,
 ) *RocketgateClient {
 	return &RocketgateClient{
 		testMode:         rocketgateTestMode(env),
@@ -50,40 +54,46 @@ func NewWithHttpClient(
 		merchantPassword: merchantPassword,
 		merchantAccount:  merchantAccount,
 		httpClient:       httpClient,
+		gatewayURL:       rocketgateGatewayURL(common.NewClientOptions(options...).BaseURL),
 	}
 }
 
+// newGatewayService returns a gateway service whose requests honour the cancellation and deadline of ctx. The SDK
+// builds its requests without a context and sets the timeout of the http client it is given, so every call sends its
+// requests with its own copy of the http client.
+func (client *RocketgateClient) newGatewayService(ctx context.Context) *service.GatewayService {
+	gatewayService := service.NewGatewayService()
+	gatewayService.SetTestMode(client.testMode)
+	gatewayService.SetHttpClient(common.HttpClientWithContext(ctx, client.httpClient))
+	return gatewayService
+}
+
 // Authorize a transaction. This transaction must be captured to receive funds
 func (client *RocketgateClient) Authorize(request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
 	return client.AuthorizeWithContext(context.TODO(), request)
 }
 
 // AuthorizeWithContext a transaction. This transaction must be captured to receive funds
-// NOTE -- RocketGate's SDK does not support context, this method exists to fulfill the ClientWithContext interface
-func (client *RocketgateClient) AuthorizeWithContext(_ context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	gatewayService := service.NewGatewayService()
+func (client *RocketgateClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	gatewayService := client.newGatewayService(ctx)
 	gatewayResponse := response.NewGatewayResponse()
 	gatewayRequest := buildAuthRequest(client.merchantID, client.merchantPassword, client.merchantAccount, request)
+	setGatewayURL(gatewayRequest, client.gatewayURL)
 
-	gatewayService.SetTestMode(client.testMode)
-	gatewayService.SetHttpClient(client.httpClient)
-
-	if !gatewayService.PerformAuthOnly(gatewayRequest, gatewayResponse) {
+	success := gatewayService.PerformAuthOnly(gatewayRequest, gatewayResponse)
+	if !success && ctx.Err() != nil {
 		return &sleet.AuthorizationResponse{
-			Success:              false,
-			Response:             gatewayResponse.Get(response.RESPONSE_CODE),
-			ErrorCode:            gatewayResponse.Get(response.REASON_CODE),
-			TransactionReference: "",
-			AvsResult:            sleet.AVSResponseUnknown,
-			CvvResult:            sleet.CVVResponseUnknown,
-		}, nil
+			Success:    false,
+			AvsResult:  sleet.AVSResponseUnknown,
+			CvvResult:  sleet.CVVResponseUnknown,
+			ErrorCode:  gatewayResponse.Get(response.REASON_CODE),
+			ResultType: sleet.ResultTypeServerError,
+		}, &ContextError{Operation: "authorization", Err: ctx.Err()}
 	}
-
-	return &sleet.AuthorizationResponse{
-		Success:              true,
-		TransactionReference: gatewayResponse.Get(response.TRANSACT_ID),
-		Response:             gatewayResponse.Get(response.RESPONSE_CODE),
-	}, nil
+	return buildAuthResponse(success, gatewayResponse), nil
 }
 
 // Capture an authorized transaction
@@ -92,16 +102,17 @@ func (client *RocketgateClient) Capture(request *sleet.CaptureRequest) (*sleet.C
 }
 
 // CaptureWithContext an authorized transaction
-// NOTE -- RocketGate's SDK does not support context, this method exists to fulfill the ClientWithContext interface
-func (client *RocketgateClient) CaptureWithContext(_ context.Context, request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
-	gatewayService := service.NewGatewayService()
+func (client *RocketgateClient) CaptureWithContext(ctx context.Context, request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
+	gatewayService := client.newGatewayService(ctx)
 	gatewayResponse := response.NewGatewayResponse()
 	gatewayRequest := buildCaptureRequest(client.merchantID, client.merchantPassword, request)
-
-	gatewayService.SetTestMode(client.testMode)
+	setGatewayURL(gatewayRequest, client.gatewayURL)
 
 	if !gatewayService.PerformTicket(gatewayRequest, gatewayResponse) {
 		errCode := gatewayResponse.Get(response.REASON_CODE)
+		if ctx.Err() != nil {
+			return &sleet.CaptureResponse{Success: false, ErrorCode: &errCode}, &ContextError{Operation: "capture", Err: ctx.Err()}
+		}
 		return &sleet.CaptureResponse{
 			Success:              false,
 			ErrorCode:            &errCode,
@@ -121,16 +132,20 @@ func (client *RocketgateClient) Void(request *sleet.VoidRequest) (*sleet.VoidRes
 }
 
 // VoidWithContext an authorized transaction
-// NOTE -- RocketGate's SDK does not support context, this method exists to fulfill the ClientWithContext interface
-func (client *RocketgateClient) VoidWithContext(_ context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
-	gatewayService := service.NewGatewayService()
+func (client *RocketgateClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
+	gatewayService := client.newGatewayService(ctx)
 	gatewayResponse := response.NewGatewayResponse()
 	gatewayRequest := buildVoidRequest(client.merchantID, client.merchantPassword, request)
-
-	gatewayService.SetTestMode(client.testMode)
+	setGatewayURL(gatewayRequest, client.gatewayURL)
 
 	if !gatewayService.PerformVoid(gatewayRequest, gatewayResponse) {
 		errCode := gatewayResponse.Get(response.REASON_CODE)
+		if ctx.Err() != nil {
+			return &sleet.VoidResponse{Success: false, ErrorCode: &errCode}, &ContextError{Operation: "void", Err: ctx.Err()}
+		}
 		return &sleet.VoidResponse{
 			Success:   false,
 			ErrorCode: &errCode,
@@ -149,16 +164,20 @@ func (client *RocketgateClient) Refund(request *sleet.RefundRequest) (*sleet.Ref
 }
 
 // RefundWithContext a captured transaction
-// NOTE -- RocketGate's SDK does not support context, this method exists to fulfill the ClientWithContext interface
-func (client *RocketgateClient) RefundWithContext(_ context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
-	gatewayService := service.NewGatewayService()
+func (client *RocketgateClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
+	gatewayService := client.newGatewayService(ctx)
 	gatewayResponse := response.NewGatewayResponse()
 	gatewayRequest := buildRefundRequest(client.merchantID, client.merchantPassword, request)
-
-	gatewayService.SetTestMode(client.testMode)
+	setGatewayURL(gatewayRequest, client.gatewayURL)
 
 	if !gatewayService.PerformCredit(gatewayRequest, gatewayResponse) {
 		errCode := gatewayResponse.Get(response.REASON_CODE)
+		if ctx.Err() != nil {
+			return &sleet.RefundResponse{Success: false, ErrorCode: &errCode}, &ContextError{Operation: "refund", Err: ctx.Err()}
+		}
 		return &sleet.RefundResponse{
 			Success:   false,
 			ErrorCode: &errCode,
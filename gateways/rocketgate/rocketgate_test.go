@@ -0,0 +1,134 @@
+package rocketgate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+// blockingTransport waits for the context of each request to be done, as a gateway that does not answer would
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestOperationsHonourContextDeadline(t *testing.T) {
+	httpClient := &http.Client{Transport: blockingTransport{}, Timeout: time.Minute}
+	client := NewWithHttpClient(common.Sandbox, "1", "testpassword", nil, httpClient)
+	amount := &sleet.Amount{Amount: 100, Currency: "USD"}
+
+	operations := map[string]func(ctx context.Context) error{
+		"Authorize": func(ctx context.Context) error {
+			response, err := client.AuthorizeWithContext(ctx, sleet_testing.BaseAuthorizationRequest())
+			if response == nil || response.Success || response.ResultType != sleet.ResultTypeServerError {
+				t.Errorf("expected server error response, got %+v", response)
+			}
+			return err
+		},
+		"Capture": func(ctx context.Context) error {
+			response, err := client.CaptureWithContext(ctx, &sleet.CaptureRequest{Amount: amount, TransactionReference: "1000"})
+			if response == nil || response.Success {
+				t.Errorf("expected failed capture, got %+v", response)
+			}
+			return err
+		},
+		"Void": func(ctx context.Context) error {
+			response, err := client.VoidWithContext(ctx, &sleet.VoidRequest{TransactionReference: "1000"})
+			if response == nil || response.Success {
+				t.Errorf("expected failed void, got %+v", response)
+			}
+			return err
+		},
+		"Refund": func(ctx context.Context) error {
+			response, err := client.RefundWithContext(ctx, &sleet.RefundRequest{Amount: amount, TransactionReference: "1000"})
+			if response == nil || response.Success {
+				t.Errorf("expected failed refund, got %+v", response)
+			}
+			return err
+		},
+	}
+
+	for label, operation := range operations {
+		t.Run(label, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err := operation(ctx)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("call returned after %s, past the deadline of its context", elapsed)
+			}
+			var contextErr *ContextError
+			if !errors.As(err, &contextErr) || !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected ContextError wrapping deadline exceeded, got %v", err)
+			}
+		})
+	}
+
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Got timeout %s on the http client of the client, want %s", httpClient.Timeout, time.Minute)
+	}
+}
+
+func TestOperationsWithCancelledContext(t *testing.T) {
+	client := NewWithHttpClient(common.Sandbox, "1", "testpassword", nil, &http.Client{Transport: blockingTransport{}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.AuthorizeWithContext(ctx, sleet_testing.BaseAuthorizationRequest())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHttpClient(common.Production, "1", "testpassword", nil, &http.Client{Transport: transport},
+		common.WithBaseURL("http://localhost:8080"))
+
+	_, _ = client.Authorize(sleet_testing.BaseAuthorizationRequest())
+
+	want := "http://localhost:8080/gateway/servlet/ServiceDispatcherAccess"
+	if len(transport.urls) != 1 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
+
+func TestRocketgateGatewayURL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"http://localhost:8080", "http://localhost:8080/gateway/servlet/ServiceDispatcherAccess"},
+		{"http://localhost", "http://localhost:80/gateway/servlet/ServiceDispatcherAccess"},
+		{"https://proxy.example.com", "https://proxy.example.com:443/gateway/servlet/ServiceDispatcherAccess"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := rocketgateGatewayURL(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package rocketgate
+
+import (
+	"strconv"
+
+	"github.com/rocketgate/rocketgate-go-sdk/response"
+
+	"github.com/BoltApp/sleet"
+)
+
+var avsMap = map[AVSResponseCode]sleet.AVSResponse{
+	AVSResponseZipNoMatchAddressMatch:          sleet.AVSResponseZipNoMatchAddressMatch,
+	AVSResponseInternationalZipUnverified:      sleet.AVSResponseNonUsZipUnverifiedAddressMatch,
+	AVSResponseInternationalUnverified:         sleet.AVSResponseSkipped,
+	AVSResponseInternationalMatch:              sleet.AVSResponseNonUsZipMatchAddressMatch,
+	AVSResponseError:                           sleet.AVSResponseError,
+	AVSResponseInternationalUnsupported:        sleet.AVSResponseUnsupported,
+	AVSResponseInternationalAddressNotVerified: sleet.AVSResponseSkipped,
+	AVSResponseInternationalZipAndAddressMatch: sleet.AVSResponseNonUsZipMatchAddressMatch,
+	AVSResponseNoMatch:                         sleet.AVSResponseNoMatch,
+	AVSResponseInternationalAddressUnverified:  sleet.AVSResponseZipMatchAddressUnverified,
+	AVSResponseRetry:                           sleet.AVSResponseError,
+	AVSResponseUnsupported:                     sleet.AVSResponseUnsupported,
+	AVSResponseUnavailable:                     sleet.AVSResponseSkipped,
+	AVSResponseZip9MatchAddressNoMatch:         sleet.AVSResponseZip9MatchAddressNoMatch,
+	AVSResponseZip9MatchAddressMatch:           sleet.AVSResponseZip9MatchAddressMatch,
+	AVSResponseZip5MatchAddressMatch:           sleet.AVSResponseZip5MatchAddressMatch,
+	AVSResponseZip5MatchAddressNoMatch:         sleet.AVSResponseZip5MatchAddressNoMatch,
+}
+
+// translateAvs converts a RocketGate AVS response code to its equivalent Sleet standard code.
+func translateAvs(code AVSResponseCode) sleet.AVSResponse {
+	sleetCode, ok := avsMap[code]
+	if !ok {
+		return sleet.AVSResponseUnknown
+	}
+	return sleetCode
+}
+
+var cvvMap = map[CVV2ResponseCode]sleet.CVVResponse{
+	CVV2ResponseMatch:        sleet.CVVResponseMatch,
+	CVV2ResponseNoMatch:      sleet.CVVResponseNoMatch,
+	CVV2ResponseNotProcessed: sleet.CVVResponseNotProcessed,
+	CVV2ResponseNotPresent:   sleet.CVVResponseRequiredButMissing,
+	CVV2ResponseUnsupported:  sleet.CVVResponseUnsupported,
+}
+
+// translateCvv converts a RocketGate CVV2 response code to its equivalent Sleet standard code.
+func translateCvv(code CVV2ResponseCode) sleet.CVVResponse {
+	sleetCode, ok := cvvMap[code]
+	if !ok {
+		return sleet.CVVResponseUnknown
+	}
+	return sleetCode
+}
+
+// reasonCodeMessages describes the reason codes that are most commonly returned on authorizations
+var reasonCodeMessages = map[int]string{
+	response.REASON_SUCCESS:                    "Success",
+	response.REASON_DECLINED:                   "Declined",
+	response.REASON_DECLINED_OVERLIMIT:         "Declined, over limit",
+	response.REASON_DECLINED_CVV2:              "Declined, CVV2 mismatch",
+	response.REASON_DECLINED_EXPIRED:           "Declined, card expired",
+	response.REASON_DECLINED_CALL:              "Declined, call issuer",
+	response.REASON_DECLINED_PICKUP:            "Declined, pick up card",
+	response.REASON_DECLINED_EXCESSIVEUSE:      "Declined, excessive use",
+	response.REASON_DECLINE_INVALID_CARDNO:     "Declined, invalid card number",
+	response.REASON_DECLINE_INVALID_EXPIRATION: "Declined, invalid expiration date",
+	response.REASON_BANK_UNAVAILABLE:           "Bank unavailable",
+	response.REASON_DECLINED_AVS:               "Declined, AVS mismatch",
+	response.REASON_INTEGRATION_ERROR:          "Integration error",
+	response.REASON_DECLINED_RISK:              "Declined by risk",
+	response.REASON_PREVIOUS_HARD_DECLINE:      "Previous hard decline",
+	response.REASON_DECLINED_STOLEN:            "Declined, stolen card",
+	response.REASON_CVV2_REQUIRED:              "CVV2 required",
+	response.REASON_RISK_FAIL:                  "Risk failure",
+	response.REASON_CUSTOMER_BLOCKED:           "Customer blocked",
+	response.REASON_3DSECURE_SCA_REQUIRED:      "3D Secure authentication required",
+	response.REASON_UNABLE_TO_CONNECT:          "Unable to connect",
+	response.REASON_RESPONSE_READ_TIMEOUT:      "Response read timeout",
+	response.REASON_SERVICE_UNAVAILABLE:        "Service unavailable",
+	response.REASON_BANK_TIMEOUT_ERROR:         "Bank timeout",
+	response.REASON_XML_ERROR:                  "Invalid XML",
+	response.REASON_INVALID_TRANSACTION:        "Invalid transaction",
+	response.REASON_INVALID_CARDNO:             "Invalid card number",
+	response.REASON_INVALID_EXPIRATION:         "Invalid expiration date",
+	response.REASON_INVALID_AMOUNT:             "Invalid amount",
+	response.REASON_INVALID_MERCHANT_ID:        "Invalid merchant ID",
+	response.REASON_INVALID_MERCHANT_ACCOUNT:   "Invalid merchant account",
+}
+
+var declineReasonMap = map[int]sleet.DeclineReason{
+	response.REASON_DECLINED:                   sleet.DeclineReasonDoNotHonor,
+	response.REASON_DECLINED_OVERLIMIT:         sleet.DeclineReasonLimitExceeded,
+	response.REASON_DECLINED_CVV2:              sleet.DeclineReasonCVVFailure,
+	response.REASON_DECLINED_EXPIRED:           sleet.DeclineReasonExpiredCard,
+	response.REASON_DECLINED_CALL:              sleet.DeclineReasonCallIssuer,
+	response.REASON_DECLINED_PICKUP:            sleet.DeclineReasonPickUpCard,
+	response.REASON_DECLINED_EXCESSIVEUSE:      sleet.DeclineReasonLimitExceeded,
+	response.REASON_DECLINE_INVALID_CARDNO:     sleet.DeclineReasonInvalidCardNumber,
+	response.REASON_DECLINE_INVALID_EXPIRATION: sleet.DeclineReasonInvalidExpirationDate,
+	response.REASON_BANK_UNAVAILABLE:           sleet.DeclineReasonIssuerUnavailable,
+	response.REASON_DECLINED_AVS:               sleet.DeclineReasonAVSFailure,
+	response.REASON_USER_DECLINED:              sleet.DeclineReasonTransactionNotPermitted,
+	response.REASON_DECLINED_RISK:              sleet.DeclineReasonSuspectedFraud,
+	response.REASON_PREVIOUS_HARD_DECLINE:      sleet.DeclineReasonDoNotHonor,
+	response.REASON_MERCHACCT_LIMIT:            sleet.DeclineReasonLimitExceeded,
+	response.REASON_DECLINED_STOLEN:            sleet.DeclineReasonLostOrStolenCard,
+	response.REASON_BANK_INVALID_TRANSACTION:   sleet.DeclineReasonTransactionNotPermitted,
+	response.REASON_CVV2_REQUIRED:              sleet.DeclineReasonCVVFailure,
+	response.REASON_RISK_FAIL:                  sleet.DeclineReasonSuspectedFraud,
+	response.REASON_CUSTOMER_BLOCKED:           sleet.DeclineReasonSuspectedFraud,
+	response.REASON_3DSECURE_INITIATION:        sleet.DeclineReasonAuthenticationRequired,
+	response.REASON_3DSECURE_SCA_REQUIRED:      sleet.DeclineReasonAuthenticationRequired,
+	response.REASON_INVALID_CARDNO:             sleet.DeclineReasonInvalidCardNumber,
+	response.REASON_INVALID_EXPIRATION:         sleet.DeclineReasonInvalidExpirationDate,
+	response.REASON_INVALID_AMOUNT:             sleet.DeclineReasonInvalidAmount,
+	response.REASON_INCOMPATABLE_CARDTYPE:      sleet.DeclineReasonTransactionNotPermitted,
+}
+
+// translateDeclineReason converts a RocketGate reason code to its equivalent Sleet decline reason. System errors are
+// reported as processing errors.
+func translateDeclineReason(reasonCode string) sleet.DeclineReason {
+	code, err := strconv.Atoi(reasonCode)
+	if err != nil {
+		return sleet.DeclineReasonUnknown
+	}
+	if reason, ok := declineReasonMap[code]; ok {
+		return reason
+	}
+	if code >= 300 && code < 400 {
+		return sleet.DeclineReasonProcessingError
+	}
+	return sleet.DeclineReasonUnknown
+}
+
+// translateReasonCode returns the result type and message of a RocketGate reason code. Reason codes are grouped by
+// range: 1xx are bank declines, 2xx risk declines, 3xx system errors and 4xx invalid requests.
+func translateReasonCode(reasonCode string) (sleet.ResultType, string) {
+	code, err := strconv.Atoi(reasonCode)
+	if err != nil {
+		return sleet.ResultTypeUnknownError, ""
+	}
+
+	message := reasonCodeMessages[code]
+	switch {
+	case code == response.REASON_SUCCESS:
+		return sleet.ResultTypeSuccess, message
+	case code == response.REASON_INTEGRATION_ERROR:
+		return sleet.ResultTypeAPIError, message
+	case code >= 100 && code < 300:
+		return sleet.ResultTypePaymentError, message
+	case code >= 300 && code < 400:
+		return sleet.ResultTypeServerError, message
+	case code >= 400 && code < 500:
+		return sleet.ResultTypeAPIError, message
+	default:
+		return sleet.ResultTypeUnknownError, message
+	}
+}
+
+// buildAuthResponse translates a RocketGate gateway response into a Sleet authorization response
+func buildAuthResponse(success bool, gatewayResponse *response.GatewayResponse) *sleet.AuthorizationResponse {
+	reasonCode := gatewayResponse.Get(response.REASON_CODE)
+	resultType, message := translateReasonCode(reasonCode)
+	avsResult := gatewayResponse.Get(response.AVS_RESPONSE)
+	cvvResult := gatewayResponse.Get(response.CVV2_CODE)
+
+	authResponse := &sleet.AuthorizationResponse{
+		Success:      success,
+		Response:     gatewayResponse.Get(response.RESPONSE_CODE),
+		Message:      message,
+		ResultType:   resultType,
+		AvsResult:    translateAvs(AVSResponseCode(avsResult)),
+		CvvResult:    translateCvv(CVV2ResponseCode(cvvResult)),
+		AvsResultRaw: avsResult,
+		CvvResultRaw: cvvResult,
+		Metadata:     buildResponseMetadata(gatewayResponse),
+	}
+	if success {
+		authResponse.TransactionReference = gatewayResponse.Get(response.TRANSACT_ID)
+		authResponse.ResultType = sleet.ResultTypeSuccess
+	} else {
+		authResponse.ErrorCode = reasonCode
+		authResponse.DeclineReason = translateDeclineReason(reasonCode)
+	}
+	return authResponse
+}
+
+func buildResponseMetadata(gatewayResponse *response.GatewayResponse) map[string]string {
+	metadata := make(map[string]string)
+	if authNo := gatewayResponse.Get(response.AUTH_NO); authNo != "" {
+		metadata[sleet.AuthCodeMetadata] = authNo
+	}
+	if cardHash := gatewayResponse.Get(response.CARD_HASH); cardHash != "" {
+		metadata[sleet.CardHashMetadata] = cardHash
+	}
+	if cardBin := gatewayResponse.Get(response.CARD_BIN); cardBin != "" {
+		metadata[sleet.CardBINMetadata] = cardBin
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
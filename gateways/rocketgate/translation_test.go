@@ -0,0 +1,181 @@
+package rocketgate
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/rocketgate/rocketgate-go-sdk/response"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestTranslateAvs(t *testing.T) {
+	cases := []struct {
+		in   AVSResponseCode
+		want sleet.AVSResponse
+	}{
+		{AVSResponseZipNoMatchAddressMatch, sleet.AVSResponseZipNoMatchAddressMatch},
+		{AVSResponseInternationalZipUnverified, sleet.AVSResponseNonUsZipUnverifiedAddressMatch},
+		{AVSResponseInternationalUnverified, sleet.AVSResponseSkipped},
+		{AVSResponseInternationalMatch, sleet.AVSResponseNonUsZipMatchAddressMatch},
+		{AVSResponseError, sleet.AVSResponseError},
+		{AVSResponseInternationalUnsupported, sleet.AVSResponseUnsupported},
+		{AVSResponseInternationalAddressNotVerified, sleet.AVSResponseSkipped},
+		{AVSResponseInternationalZipAndAddressMatch, sleet.AVSResponseNonUsZipMatchAddressMatch},
+		{AVSResponseNoMatch, sleet.AVSResponseNoMatch},
+		{AVSResponseInternationalAddressUnverified, sleet.AVSResponseZipMatchAddressUnverified},
+		{AVSResponseRetry, sleet.AVSResponseError},
+		{AVSResponseUnsupported, sleet.AVSResponseUnsupported},
+		{AVSResponseUnavailable, sleet.AVSResponseSkipped},
+		{AVSResponseZip9MatchAddressNoMatch, sleet.AVSResponseZip9MatchAddressNoMatch},
+		{AVSResponseZip9MatchAddressMatch, sleet.AVSResponseZip9MatchAddressMatch},
+		{AVSResponseZip5MatchAddressMatch, sleet.AVSResponseZip5MatchAddressMatch},
+		{AVSResponseZip5MatchAddressNoMatch, sleet.AVSResponseZip5MatchAddressNoMatch},
+		{"", sleet.AVSResponseUnknown},
+		{"Fake Result", sleet.AVSResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateAvs(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateCvv(t *testing.T) {
+	cases := []struct {
+		in   CVV2ResponseCode
+		want sleet.CVVResponse
+	}{
+		{CVV2ResponseMatch, sleet.CVVResponseMatch},
+		{CVV2ResponseNoMatch, sleet.CVVResponseNoMatch},
+		{CVV2ResponseNotProcessed, sleet.CVVResponseNotProcessed},
+		{CVV2ResponseNotPresent, sleet.CVVResponseRequiredButMissing},
+		{CVV2ResponseUnsupported, sleet.CVVResponseUnsupported},
+		{"", sleet.CVVResponseUnknown},
+		{"Fake Result", sleet.CVVResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateCvv(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateReasonCode(t *testing.T) {
+	cases := []struct {
+		in          string
+		wantType    sleet.ResultType
+		wantMessage string
+	}{
+		{"0", sleet.ResultTypeSuccess, "Success"},
+		{"104", sleet.ResultTypePaymentError, "Declined"},
+		{"106", sleet.ResultTypePaymentError, "Declined, CVV2 mismatch"},
+		{"154", sleet.ResultTypeAPIError, "Integration error"},
+		{"200", sleet.ResultTypePaymentError, "Risk failure"},
+		{"303", sleet.ResultTypeServerError, "Response read timeout"},
+		{"399", sleet.ResultTypeServerError, ""},
+		{"406", sleet.ResultTypeAPIError, "Invalid merchant ID"},
+		{"999", sleet.ResultTypeUnknownError, ""},
+		{"", sleet.ResultTypeUnknownError, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			gotType, gotMessage := translateReasonCode(c.in)
+			if gotType != c.wantType || gotMessage != c.wantMessage {
+				t.Errorf("Got (%q, %q), want (%q, %q)", gotType, gotMessage, c.wantType, c.wantMessage)
+			}
+		})
+	}
+}
+
+func TestTranslateDeclineReason(t *testing.T) {
+	cases := []struct {
+		in   string
+		want sleet.DeclineReason
+	}{
+		{"104", sleet.DeclineReasonDoNotHonor},
+		{"105", sleet.DeclineReasonLimitExceeded},
+		{"107", sleet.DeclineReasonExpiredCard},
+		{"164", sleet.DeclineReasonLostOrStolenCard},
+		{"200", sleet.DeclineReasonSuspectedFraud},
+		{"315", sleet.DeclineReasonProcessingError},
+		{"405", sleet.DeclineReasonInvalidAmount},
+		{"999", sleet.DeclineReasonUnknown},
+		{"", sleet.DeclineReasonUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got := translateDeclineReason(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBuildAuthResponse(t *testing.T) {
+	t.Run("Approved", func(t *testing.T) {
+		gatewayResponse := response.NewGatewayResponse()
+		gatewayResponse.SetResults(response.RESPONSE_SUCCESS, response.REASON_SUCCESS)
+		gatewayResponse.Set(response.TRANSACT_ID, "1000176B2F0A6C4")
+		gatewayResponse.Set(response.AUTH_NO, "123456")
+		gatewayResponse.Set(response.AVS_RESPONSE, "Y")
+		gatewayResponse.Set(response.CVV2_CODE, "M")
+		gatewayResponse.Set(response.CARD_HASH, "m77xlHZiPKVsF9p1/VdzTb+CUwaGBDpuSRxtcb7+j24=")
+		gatewayResponse.Set(response.CARD_BIN, "411111")
+
+		want := &sleet.AuthorizationResponse{
+			Success:              true,
+			TransactionReference: "1000176B2F0A6C4",
+			Response:             "0",
+			Message:              "Success",
+			ResultType:           sleet.ResultTypeSuccess,
+			AvsResult:            sleet.AVSResponseZip5MatchAddressMatch,
+			CvvResult:            sleet.CVVResponseMatch,
+			AvsResultRaw:         "Y",
+			CvvResultRaw:         "M",
+			Metadata: map[string]string{
+				sleet.AuthCodeMetadata: "123456",
+				sleet.CardHashMetadata: "m77xlHZiPKVsF9p1/VdzTb+CUwaGBDpuSRxtcb7+j24=",
+				sleet.CardBINMetadata:  "411111",
+			},
+		}
+		if diff := deep.Equal(buildAuthResponse(true, gatewayResponse), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		gatewayResponse := response.NewGatewayResponse()
+		gatewayResponse.SetResults(response.RESPONSE_BANK_FAIL, response.REASON_DECLINED_CVV2)
+		gatewayResponse.Set(response.TRANSACT_ID, "1000176B2F0A6C5")
+		gatewayResponse.Set(response.AVS_RESPONSE, "Y")
+		gatewayResponse.Set(response.CVV2_CODE, "N")
+
+		want := &sleet.AuthorizationResponse{
+			Success:       false,
+			Response:      "1",
+			ErrorCode:     "106",
+			Message:       "Declined, CVV2 mismatch",
+			ResultType:    sleet.ResultTypePaymentError,
+			AvsResult:     sleet.AVSResponseZip5MatchAddressMatch,
+			CvvResult:     sleet.CVVResponseNoMatch,
+			AvsResultRaw:  "Y",
+			CvvResultRaw:  "N",
+			DeclineReason: sleet.DeclineReasonCVVFailure,
+		}
+		if diff := deep.Equal(buildAuthResponse(false, gatewayResponse), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package rocketgate
+
+import "fmt"
+
+// AVSResponseCode is the avsResponse returned by RocketGate, which follows the card network AVS codes
+type AVSResponseCode string
+
+const (
+	AVSResponseZipNoMatchAddressMatch          AVSResponseCode = "A"
+	AVSResponseInternationalZipUnverified      AVSResponseCode = "B" // street address matches, postal code not verified
+	AVSResponseInternationalUnverified         AVSResponseCode = "C" // street address and postal code not verified
+	AVSResponseInternationalMatch              AVSResponseCode = "D"
+	AVSResponseError                           AVSResponseCode = "E"
+	AVSResponseInternationalUnsupported        AVSResponseCode = "G"
+	AVSResponseInternationalAddressNotVerified AVSResponseCode = "I"
+	AVSResponseInternationalZipAndAddressMatch AVSResponseCode = "M"
+	AVSResponseNoMatch                         AVSResponseCode = "N"
+	AVSResponseInternationalAddressUnverified  AVSResponseCode = "P" // postal code matches, street address not verified
+	AVSResponseRetry                           AVSResponseCode = "R"
+	AVSResponseUnsupported                     AVSResponseCode = "S"
+	AVSResponseUnavailable                     AVSResponseCode = "U"
+	AVSResponseZip9MatchAddressNoMatch         AVSResponseCode = "W"
+	AVSResponseZip9MatchAddressMatch           AVSResponseCode = "X"
+	AVSResponseZip5MatchAddressMatch           AVSResponseCode = "Y"
+	AVSResponseZip5MatchAddressNoMatch         AVSResponseCode = "Z"
+)
+
+// CVV2ResponseCode is the cvv2Code returned by RocketGate
+type CVV2ResponseCode string
+
+const (
+	CVV2ResponseMatch        CVV2ResponseCode = "M"
+	CVV2ResponseNoMatch      CVV2ResponseCode = "N"
+	CVV2ResponseNotProcessed CVV2ResponseCode = "P"
+	CVV2ResponseNotPresent   CVV2ResponseCode = "S" // should be on the card but the cardholder indicated it is not
+	CVV2ResponseUnsupported  CVV2ResponseCode = "U" // issuer is not certified for CVV2
+)
+
+// ContextError is returned when the context of a call is cancelled or its deadline expires before RocketGate answers.
+// The outcome of the transaction is unknown. It unwraps to context.Canceled or context.DeadlineExceeded.
+type ContextError struct {
+	Operation string
+	Err       error
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf("rocketgate: %s abandoned: %s", e.Operation, e.Err)
+}
+
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
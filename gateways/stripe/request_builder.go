@@ -1,58 +1,199 @@
 package stripe
 
 import (
-	"context"
 	"strconv"
+	"strings"
 
 	"github.com/stripe/stripe-go"
 
 	"github.com/BoltApp/sleet"
 )
 
-func buildChargeParams(ctx context.Context, authRequest *sleet.AuthorizationRequest) *stripe.ChargeParams {
-	return &stripe.ChargeParams{
-		Params: stripe.Params{
-			Context: ctx,
+// Metadata keys the sleet references are stored under on payment intents
+const (
+	clientTransactionReferenceMetadata = "client_transaction_reference"
+	merchantOrderReferenceMetadata     = "merchant_order_reference"
+)
+
+// chargeIDPrefix identifies the references of authorizations made with the Charges API, before the client moved to
+// PaymentIntents. They are still captured, voided and refunded through the Charges API.
+const chargeIDPrefix = "ch_"
+
+// paymentMethodIdempotencySuffix keeps the idempotency key of the payment method distinct from the one of the payment
+// intent, so that a replayed authorization confirms the same payment method
+const paymentMethodIdempotencySuffix = "-payment-method"
+
+// refundIdempotencyInfix keeps the idempotency key of a refund distinct from the one of the authorization sharing its
+// ClientTransactionReference
+const refundIdempotencyInfix = "-refund-"
+
+// idempotencyKey returns the IdempotencyKeyOption of an authorization, or an empty string if the caller set none.
+// ClientTransactionReference is not used as a key, so that a declined authorization can be retried with changed
+// details under the same reference.
+func idempotencyKey(authRequest *sleet.AuthorizationRequest) string {
+	key, _ := authRequest.Options[sleet.IdempotencyKeyOption].(string)
+	return key
+}
+
+func isChargeID(transactionReference string) bool {
+	return strings.HasPrefix(transactionReference, chargeIDPrefix)
+}
+
+func buildPaymentMethodParams(base stripe.Params, authRequest *sleet.AuthorizationRequest) *stripe.PaymentMethodParams {
+	params := &stripe.PaymentMethodParams{
+		Params: base,
+		Type:   stripe.String(string(stripe.PaymentMethodTypeCard)),
+		Card: &stripe.PaymentMethodCardParams{
+			Number:   stripe.String(authRequest.CreditCard.Number), // raw PAN, requires raw card data APIs to be enabled
+			ExpMonth: stripe.String(strconv.Itoa(authRequest.CreditCard.ExpirationMonth)),
+			ExpYear:  stripe.String(strconv.Itoa(authRequest.CreditCard.ExpirationYear)),
 		},
-		Amount:   stripe.Int64(authRequest.Amount.Amount),
-		Currency: stripe.String(authRequest.Amount.Currency),
-		Source: &stripe.SourceParams{
-			Card: &stripe.CardParams{
-				Number:   stripe.String(authRequest.CreditCard.Number), // raw PAN as we're testing token creation
-				ExpMonth: stripe.String(strconv.Itoa(authRequest.CreditCard.ExpirationMonth)),
-				ExpYear:  stripe.String(strconv.Itoa(authRequest.CreditCard.ExpirationYear)),
-				CVC:      stripe.String(authRequest.CreditCard.CVV),
-				Name:     stripe.String(authRequest.CreditCard.FirstName + " " + authRequest.CreditCard.LastName),
-			},
+		BillingDetails: &stripe.BillingDetailsParams{
+			Name: stripe.String(cardholderName(authRequest.CreditCard)),
 		},
-		Capture: stripe.Bool(false),
 	}
+	if authRequest.CreditCard.CVV != "" {
+		params.Card.CVC = stripe.String(authRequest.CreditCard.CVV)
+	}
+	if key := idempotencyKey(authRequest); key != "" {
+		params.IdempotencyKey = stripe.String(key + paymentMethodIdempotencySuffix)
+	}
+	if billingAddress := authRequest.BillingAddress; billingAddress != nil {
+		params.BillingDetails.Address = buildAddressParams(billingAddress)
+		params.BillingDetails.Email = billingAddress.Email
+		params.BillingDetails.Phone = billingAddress.PhoneNumber
+	}
+	return params
 }
 
-func buildRefundParams(ctx context.Context, refundRequest *sleet.RefundRequest) *stripe.RefundParams {
-	return &stripe.RefundParams{
-		Params: stripe.Params{
-			Context: ctx,
-		},
+func buildPaymentIntentParams(base stripe.Params, authRequest *sleet.AuthorizationRequest, paymentMethodID string) *stripe.PaymentIntentParams {
+	params := &stripe.PaymentIntentParams{
+		Params:             base,
+		Amount:             stripe.Int64(authRequest.Amount.Amount),
+		Currency:           stripe.String(authRequest.Amount.Currency),
+		PaymentMethod:      stripe.String(paymentMethodID),
+		PaymentMethodTypes: []*string{stripe.String(string(stripe.PaymentMethodTypeCard))},
+		CaptureMethod:      stripe.String(string(stripe.PaymentIntentCaptureMethodManual)),
+		Confirm:            stripe.Bool(true),
+		// authorizations are made server to server, cards requiring a challenge are declined instead
+		ErrorOnRequiresAction: stripe.Bool(true),
+	}
+	if key := idempotencyKey(authRequest); key != "" {
+		params.IdempotencyKey = stripe.String(key)
+	}
+	if authRequest.ClientTransactionReference != nil {
+		params.AddMetadata(clientTransactionReferenceMetadata, *authRequest.ClientTransactionReference)
+	}
+	if authRequest.MerchantOrderReference != "" {
+		params.AddMetadata(merchantOrderReferenceMetadata, authRequest.MerchantOrderReference)
+	}
+	if shippingAddress := authRequest.ShippingAddress; shippingAddress != nil {
+		params.Shipping = &stripe.ShippingDetailsParams{
+			Address: buildAddressParams(shippingAddress),
+			Name:    stripe.String(cardholderName(authRequest.CreditCard)),
+			Phone:   shippingAddress.PhoneNumber,
+		}
+	}
+	if authRequest.ProcessingInitiator != nil {
+		switch *authRequest.ProcessingInitiator {
+		case sleet.ProcessingInitiatorTypeInitialCardOnFile, sleet.ProcessingInitiatorTypeInitialRecurring:
+			params.SetupFutureUsage = stripe.String(string(stripe.PaymentIntentSetupFutureUsageOffSession))
+		case sleet.ProcessingInitiatorTypeStoredMerchantInitiated, sleet.ProcessingInitiatorTypeFollowingRecurring:
+			params.OffSession = stripe.Bool(true)
+		}
+	}
+	if authRequest.ThreeDS != nil {
+		addThreeDSParams(&params.Params, authRequest)
+	}
+	return params
+}
+
+// addThreeDSParams sends the result of a 3DS authentication performed outside of Stripe. The client library does not
+// model these parameters, so they are added as extra form values.
+func addThreeDSParams(params *stripe.Params, authRequest *sleet.AuthorizationRequest) {
+	threeDS := authRequest.ThreeDS
+	const prefix = "payment_method_options[card][three_d_secure]"
+	add := func(key string, value string) {
+		if value != "" {
+			params.AddExtra(prefix+"["+key+"]", value)
+		}
+	}
+	transactionID := threeDS.DSTransactionID
+	if strings.HasPrefix(threeDS.Version, "1") {
+		transactionID = threeDS.XID
+	}
+	add("version", threeDS.Version)
+	add("cryptogram", threeDS.CAVV)
+	add("transaction_id", transactionID)
+	add("electronic_commerce_indicator", authRequest.ECI)
+	add("ares_trans_status", threeDS.PAResStatus)
+}
+
+func buildAddressParams(address *sleet.Address) *stripe.AddressParams {
+	return &stripe.AddressParams{
+		Line1:      address.StreetAddress1,
+		Line2:      address.StreetAddress2,
+		City:       address.Locality,
+		State:      address.RegionCode,
+		PostalCode: address.PostalCode,
+		Country:    address.CountryCode,
+	}
+}
+
+func cardholderName(creditCard *sleet.CreditCard) string {
+	return strings.TrimSpace(creditCard.FirstName + " " + creditCard.LastName)
+}
+
+func buildRefundParams(base stripe.Params, refundRequest *sleet.RefundRequest) *stripe.RefundParams {
+	params := &stripe.RefundParams{
+		Params: base,
 		Amount: stripe.Int64(refundRequest.Amount.Amount),
-		Charge: stripe.String(refundRequest.TransactionReference),
+	}
+	if isChargeID(refundRequest.TransactionReference) {
+		params.Charge = stripe.String(refundRequest.TransactionReference)
+	} else {
+		params.PaymentIntent = stripe.String(refundRequest.TransactionReference)
+	}
+	if refundRequest.ClientTransactionReference != nil {
+		params.IdempotencyKey = stripe.String(refundIdempotencyKey(refundRequest))
+		params.AddMetadata(clientTransactionReferenceMetadata, *refundRequest.ClientTransactionReference)
+	}
+	return params
+}
+
+// refundIdempotencyKey derives the idempotency key of a refund from its ClientTransactionReference, the refunded
+// transaction and the amount. A replayed refund is made once, while partial refunds of different amounts sharing a
+// ClientTransactionReference are all made. Refunds of the same amount need distinct ClientTransactionReferences.
+func refundIdempotencyKey(refundRequest *sleet.RefundRequest) string {
+	return *refundRequest.ClientTransactionReference + refundIdempotencyInfix + refundRequest.TransactionReference +
+		"-" + strconv.FormatInt(refundRequest.Amount.Amount, 10)
+}
+
+func buildCaptureParams(base stripe.Params, captureRequest *sleet.CaptureRequest) *stripe.PaymentIntentCaptureParams {
+	return &stripe.PaymentIntentCaptureParams{
+		Params:          base,
+		AmountToCapture: stripe.Int64(captureRequest.Amount.Amount),
 	}
 }
 
-func buildCaptureParams(ctx context.Context, captureRequest *sleet.CaptureRequest) *stripe.CaptureParams {
+func buildChargeCaptureParams(base stripe.Params, captureRequest *sleet.CaptureRequest) *stripe.CaptureParams {
 	return &stripe.CaptureParams{
-		Params: stripe.Params{
-			Context: ctx,
-		},
+		Params: base,
 		Amount: stripe.Int64(captureRequest.Amount.Amount),
 	}
 }
 
-func buildVoidParams(ctx context.Context, voidRequest *sleet.VoidRequest) *stripe.RefundParams {
+func buildVoidParams(base stripe.Params, voidRequest *sleet.VoidRequest) *stripe.PaymentIntentCancelParams {
+	return &stripe.PaymentIntentCancelParams{
+		Params: base,
+	}
+}
+
+// buildChargeVoidParams voids an authorization made with the Charges API, which releases uncaptured charges by
+// refunding them
+func buildChargeVoidParams(base stripe.Params, voidRequest *sleet.VoidRequest) *stripe.RefundParams {
 	return &stripe.RefundParams{
-		Params: stripe.Params{
-			Context: ctx,
-		},
+		Params: base,
 		Charge: stripe.String(voidRequest.TransactionReference),
 	}
 }
@@ -0,0 +1,208 @@
+package stripe
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/stripe/stripe-go"
+	"github.com/stripe/stripe-go/form"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+	sleet_testing "github.com/BoltApp/sleet/testing"
+)
+
+func TestBuildPaymentMethodParams(t *testing.T) {
+	request := sleet_testing.BaseAuthorizationRequestWithEmailPhoneNumber()
+	request.ClientTransactionReference = common.SPtr("ref")
+	request.Options = map[string]interface{}{sleet.IdempotencyKeyOption: "key"}
+
+	got := buildPaymentMethodParams(stripe.Params{Context: context.TODO()}, request)
+	want := &stripe.PaymentMethodParams{
+		Params: stripe.Params{
+			Context:        context.TODO(),
+			IdempotencyKey: stripe.String("key-payment-method"),
+		},
+		Type: stripe.String("card"),
+		Card: &stripe.PaymentMethodCardParams{
+			Number:   stripe.String("4111111111111111"),
+			ExpMonth: stripe.String("10"),
+			ExpYear:  stripe.String("2025"),
+			CVC:      stripe.String("737"),
+		},
+		BillingDetails: &stripe.BillingDetailsParams{
+			Name:  stripe.String("Bolt Checkout"),
+			Email: stripe.String("test@bolt.com"),
+			Phone: stripe.String("555-555-5555"),
+			Address: &stripe.AddressParams{
+				Line1:      stripe.String("7683 Railroad Street"),
+				City:       stripe.String("Zion"),
+				State:      stripe.String("IL"),
+				PostalCode: stripe.String("94103"),
+				Country:    stripe.String("US"),
+			},
+		},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestBuildPaymentIntentParams(t *testing.T) {
+	encode := func(params *stripe.PaymentIntentParams) url.Values {
+		values := &form.Values{}
+		form.AppendTo(values, params)
+		return values.ToValues()
+	}
+
+	t.Run("Manual capture", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.ClientTransactionReference = common.SPtr("ref")
+		request.MerchantOrderReference = "order"
+		request.ShippingAddress = request.BillingAddress
+		request.Options = map[string]interface{}{sleet.IdempotencyKeyOption: "key"}
+
+		got := buildPaymentIntentParams(stripe.Params{Context: context.TODO()}, request, "pm_123")
+		if got.IdempotencyKey == nil || *got.IdempotencyKey != "key" {
+			t.Errorf("expected idempotency key key, got %v", got.IdempotencyKey)
+		}
+		want := url.Values{
+			"amount":                                 {"100"},
+			"capture_method":                         {"manual"},
+			"confirm":                                {"true"},
+			"currency":                               {"USD"},
+			"error_on_requires_action":               {"true"},
+			"metadata[client_transaction_reference]": {"ref"},
+			"metadata[merchant_order_reference]":     {"order"},
+			"payment_method":                         {"pm_123"},
+			"payment_method_types[0]":                {"card"},
+			"shipping[address][city]":                {"Zion"},
+			"shipping[address][country]":             {"US"},
+			"shipping[address][line1]":               {"7683 Railroad Street"},
+			"shipping[address][postal_code]":         {"94103"},
+			"shipping[address][state]":               {"IL"},
+			"shipping[name]":                         {"Bolt Checkout"},
+		}
+		if diff := deep.Equal(encode(got), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Merchant initiated", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		initiator := sleet.ProcessingInitiatorTypeStoredMerchantInitiated
+		request.ProcessingInitiator = &initiator
+
+		got := buildPaymentIntentParams(stripe.Params{Context: context.TODO()}, request, "pm_123")
+		if got.OffSession == nil || !*got.OffSession || got.SetupFutureUsage != nil {
+			t.Errorf("expected off session payment, got %v, %v", got.OffSession, got.SetupFutureUsage)
+		}
+	})
+
+	t.Run("Initial recurring", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		initiator := sleet.ProcessingInitiatorTypeInitialRecurring
+		request.ProcessingInitiator = &initiator
+
+		got := buildPaymentIntentParams(stripe.Params{Context: context.TODO()}, request, "pm_123")
+		if got.SetupFutureUsage == nil || *got.SetupFutureUsage != "off_session" || got.OffSession != nil {
+			t.Errorf("expected setup for off session usage, got %v, %v", got.OffSession, got.SetupFutureUsage)
+		}
+	})
+
+	t.Run("3DS", func(t *testing.T) {
+		request := sleet_testing.BaseAuthorizationRequest()
+		request.ECI = "05"
+		request.ThreeDS = &sleet.ThreeDS{
+			Version:         "2.2.0",
+			CAVV:            "AAABBBCCC",
+			DSTransactionID: "ds-transaction",
+			XID:             "xid",
+			PAResStatus:     "Y",
+		}
+
+		got := encode(buildPaymentIntentParams(stripe.Params{Context: context.TODO()}, request, "pm_123"))
+		want := map[string]string{
+			"payment_method_options[card][three_d_secure][version]":                       "2.2.0",
+			"payment_method_options[card][three_d_secure][cryptogram]":                    "AAABBBCCC",
+			"payment_method_options[card][three_d_secure][transaction_id]":                "ds-transaction",
+			"payment_method_options[card][three_d_secure][electronic_commerce_indicator]": "05",
+			"payment_method_options[card][three_d_secure][ares_trans_status]":             "Y",
+		}
+		for key, value := range want {
+			if got.Get(key) != value {
+				t.Errorf("Got %q for %s, want %q", got.Get(key), key, value)
+			}
+		}
+	})
+}
+
+func TestBuildAuthorizationIdempotencyKeys(t *testing.T) {
+	declined := sleet_testing.BaseAuthorizationRequest()
+	declined.ClientTransactionReference = common.SPtr("ref")
+	declined.CreditCard.CVV = "000"
+	retried := sleet_testing.BaseAuthorizationRequest()
+	retried.ClientTransactionReference = common.SPtr("ref")
+
+	for _, request := range []*sleet.AuthorizationRequest{declined, retried} {
+		paymentMethod := buildPaymentMethodParams(stripe.Params{}, request)
+		paymentIntent := buildPaymentIntentParams(stripe.Params{}, request, "pm_123")
+		if paymentMethod.IdempotencyKey != nil || paymentIntent.IdempotencyKey != nil {
+			t.Errorf("expected no idempotency keys without the option, got %v, %v",
+				paymentMethod.IdempotencyKey, paymentIntent.IdempotencyKey)
+		}
+	}
+
+	declined.Options = map[string]interface{}{sleet.IdempotencyKeyOption: "first-attempt"}
+	retried.Options = map[string]interface{}{sleet.IdempotencyKeyOption: "second-attempt"}
+	first := buildPaymentIntentParams(stripe.Params{}, declined, "pm_123").IdempotencyKey
+	second := buildPaymentIntentParams(stripe.Params{}, retried, "pm_456").IdempotencyKey
+	if *first == *second {
+		t.Errorf("expected requests sharing a reference to use the keys given, got %q twice", *first)
+	}
+}
+
+func TestBuildRefundParams(t *testing.T) {
+	amount := &sleet.Amount{Amount: 50, Currency: "USD"}
+
+	got := buildRefundParams(stripe.Params{Context: context.TODO()}, &sleet.RefundRequest{Amount: amount, TransactionReference: "pi_123"})
+	if got.PaymentIntent == nil || *got.PaymentIntent != "pi_123" || got.Charge != nil {
+		t.Errorf("expected refund of payment intent, got %v, %v", got.PaymentIntent, got.Charge)
+	}
+
+	got = buildRefundParams(stripe.Params{Context: context.TODO()}, &sleet.RefundRequest{Amount: amount, TransactionReference: "ch_123"})
+	if got.Charge == nil || *got.Charge != "ch_123" || got.PaymentIntent != nil {
+		t.Errorf("expected refund of charge, got %v, %v", got.PaymentIntent, got.Charge)
+	}
+}
+
+func TestBuildRefundParamsIdempotencyKey(t *testing.T) {
+	authorization := buildPaymentIntentParams(stripe.Params{}, &sleet.AuthorizationRequest{
+		Amount:                     sleet.Amount{Amount: 100, Currency: "USD"},
+		ClientTransactionReference: common.SPtr("ref"),
+		Options:                    map[string]interface{}{sleet.IdempotencyKeyOption: "ref"},
+	}, "pm_123")
+	refund := func(amount int64) *string {
+		return buildRefundParams(stripe.Params{}, &sleet.RefundRequest{
+			Amount:                     &sleet.Amount{Amount: amount, Currency: "USD"},
+			TransactionReference:       "pi_123",
+			ClientTransactionReference: common.SPtr("ref"),
+		}).IdempotencyKey
+	}
+
+	first, replayed, second := refund(50), refund(50), refund(25)
+	if *first != "ref-refund-pi_123-50" {
+		t.Errorf("Got idempotency key %q, want %q", *first, "ref-refund-pi_123-50")
+	}
+	if *first == *authorization.IdempotencyKey {
+		t.Errorf("expected refund key distinct from authorization key %q", *authorization.IdempotencyKey)
+	}
+	if *replayed != *first {
+		t.Errorf("expected replayed refund to reuse key %q, got %q", *first, *replayed)
+	}
+	if *second == *first {
+		t.Errorf("expected partial refunds of different amounts to use distinct keys, got %q", *second)
+	}
+}
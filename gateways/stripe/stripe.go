@@ -11,6 +11,8 @@ import (
 
 	"github.com/stripe/stripe-go"
 	"github.com/stripe/stripe-go/charge"
+	"github.com/stripe/stripe-go/paymentintent"
+	"github.com/stripe/stripe-go/paymentmethod"
 	"github.com/stripe/stripe-go/refund"
 )
 
@@ -19,10 +21,20 @@ var (
 	_ sleet.ClientWithContext = &StripeClient{}
 )
 
-// StripeClient uses API-Key and custom http client to make http calls
+// StripeClient uses API-Key and custom http client to make http calls. Each client has its own backend, so clients
+// for different accounts can be used concurrently.
 type StripeClient struct {
 	apiKey     string
 	httpClient *http.Client
+	// stripeAccount is the connected account requests are made on behalf of, sent as the Stripe-Account header
+	stripeAccount *string
+	backend       stripe.Backend
+
+	// resource clients are built once with the backend and key of the client and shared by its requests
+	paymentMethods *paymentmethod.Client
+	paymentIntents *paymentintent.Client
+	charges        *charge.Client
+	refunds        *refund.Client
 }
 
 var defaultHttpClient = &http.Client{
@@ -36,17 +48,50 @@ var defaultHttpClient = &http.Client{
 
 // NewClient uses default http client with provided Stripe API Key
 // Note: the environment is kind of explicitly given to us by the apiKey
-func NewClient(apiKey string) *StripeClient {
-	return NewWithHTTPClient(apiKey, defaultHttpClient)
+func NewClient(apiKey string, options ...common.ClientOption) *StripeClient {
+	return NewWithHTTPClient(apiKey, defaultHttpClient, options...)
 }
 
 // NewWithHTTPClient uses a custom http client for requests
-func NewWithHTTPClient(apiKey string, httpClient *http.Client) *StripeClient {
-	// set the Stripe global key for requests
-	stripe.Key = apiKey
+func NewWithHTTPClient(apiKey string, httpClient *http.Client, options ...common.ClientOption) *StripeClient {
+	return newClient(apiKey, nil, httpClient, options)
+}
+
+// NewConnectClient uses default http client to make requests on behalf of a connected account with the API Key of
+// the platform
+func NewConnectClient(apiKey string, stripeAccount string, options ...common.ClientOption) *StripeClient {
+	return NewConnectWithHTTPClient(apiKey, stripeAccount, defaultHttpClient, options...)
+}
+
+// NewConnectWithHTTPClient uses a custom http client to make requests on behalf of a connected account with the API
+// Key of the platform
+func NewConnectWithHTTPClient(apiKey string, stripeAccount string, httpClient *http.Client, options ...common.ClientOption) *StripeClient {
+	return newClient(apiKey, &stripeAccount, httpClient, options)
+}
+
+func newClient(apiKey string, stripeAccount *string, httpClient *http.Client, options []common.ClientOption) *StripeClient {
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		HTTPClient: httpClient,
+		// an empty URL keeps the default of the backend
+		URL: common.NewClientOptions(options...).BaseURL,
+	})
 	return &StripeClient{
-		apiKey:     apiKey,
-		httpClient: httpClient,
+		apiKey:         apiKey,
+		httpClient:     httpClient,
+		stripeAccount:  stripeAccount,
+		backend:        backend,
+		paymentMethods: &paymentmethod.Client{B: backend, Key: apiKey},
+		paymentIntents: &paymentintent.Client{B: backend, Key: apiKey},
+		charges:        &charge.Client{B: backend, Key: apiKey},
+		refunds:        &refund.Client{B: backend, Key: apiKey},
+	}
+}
+
+// params returns the parameters shared by all requests of the client
+func (client *StripeClient) params(ctx context.Context) stripe.Params {
+	return stripe.Params{
+		Context:       ctx,
+		StripeAccount: client.stripeAccount,
 	}
 }
 
@@ -55,63 +100,89 @@ func (client *StripeClient) Authorize(request *sleet.AuthorizationRequest) (*sle
 	return client.AuthorizeWithContext(context.TODO(), request)
 }
 
-// AuthorizeWithContext a transaction for specified amount using stripe-go library
+// AuthorizeWithContext creates a payment method for the card and confirms a payment intent with manual capture for
+// the specified amount. Declines are returned as responses with Success false.
 func (client *StripeClient) AuthorizeWithContext(ctx context.Context, request *sleet.AuthorizationRequest) (*sleet.AuthorizationResponse, error) {
-	chargeClient := charge.Client{B: stripe.GetBackend(stripe.APIBackend), Key: client.apiKey}
-	charge, err := chargeClient.New(buildChargeParams(ctx, request))
+	if err := sleet.CheckAuthorizationFields(request); err != nil {
+		return nil, err
+	}
+	paymentMethod, err := client.paymentMethods.New(buildPaymentMethodParams(client.params(ctx), request))
 	if err != nil {
-		return &sleet.AuthorizationResponse{Success: false, TransactionReference: "", AvsResult: sleet.AVSResponseUnknown, CvvResult: sleet.CVVResponseUnknown, ErrorCode: err.Error()}, err
+		return translateAuthorizationError(err)
 	}
-	return &sleet.AuthorizationResponse{
-		Success:              true,
-		TransactionReference: charge.ID,
-		AvsResult:            sleet.AVSresponseZipMatchAddressMatch, // TODO: Add translator
-		CvvResult:            sleet.CVVResponseMatch,                // TODO: Add translator
-		AvsResultRaw:         string(charge.Source.Card.AddressLine1Check),
-		CvvResultRaw:         string(charge.Source.Card.CVCCheck)}, nil
+
+	intent, err := client.paymentIntents.New(buildPaymentIntentParams(client.params(ctx), request, paymentMethod.ID))
+	if err != nil {
+		return translateAuthorizationError(err)
+	}
+	return translatePaymentIntent(intent), nil
 }
 
-// Capture an authorized transaction by charge ID
+// Capture an authorized transaction by payment intent ID
 func (client *StripeClient) Capture(request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
 	return client.CaptureWithContext(context.TODO(), request)
 }
 
-// CaptureWithContext an authorized transaction by charge ID
+// CaptureWithContext captures the requested amount of an authorized payment intent, the remaining amount is released
 func (client *StripeClient) CaptureWithContext(ctx context.Context, request *sleet.CaptureRequest) (*sleet.CaptureResponse, error) {
-	chargeClient := charge.Client{B: stripe.GetBackend(stripe.APIBackend), Key: client.apiKey}
-	capture, err := chargeClient.Capture(request.TransactionReference, buildCaptureParams(ctx, request))
+	if isChargeID(request.TransactionReference) {
+		capture, err := client.charges.Capture(request.TransactionReference, buildChargeCaptureParams(client.params(ctx), request))
+		if err != nil {
+			errorCode, err := translateError(err)
+			return &sleet.CaptureResponse{Success: false, ErrorCode: errorCode}, err
+		}
+		return &sleet.CaptureResponse{Success: true, TransactionReference: capture.ID}, nil
+	}
+
+	intent, err := client.paymentIntents.Capture(request.TransactionReference, buildCaptureParams(client.params(ctx), request))
 	if err != nil {
-		return &sleet.CaptureResponse{Success: false, ErrorCode: common.SPtr(err.Error())}, nil
+		errorCode, err := translateError(err)
+		return &sleet.CaptureResponse{Success: false, ErrorCode: errorCode}, err
 	}
-	return &sleet.CaptureResponse{Success: true, TransactionReference: capture.ID}, nil
+	return &sleet.CaptureResponse{Success: true, TransactionReference: intent.ID}, nil
 }
 
-// Refund a captured transaction with amount and charge ID
+// Refund a captured transaction with amount and payment intent ID
 func (client *StripeClient) Refund(request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
 	return client.RefundWithContext(context.TODO(), request)
 }
 
-// RefundWithContext a captured transaction with amount and charge ID
+// RefundWithContext refunds the specified amount of a captured payment intent or charge
 func (client *StripeClient) RefundWithContext(ctx context.Context, request *sleet.RefundRequest) (*sleet.RefundResponse, error) {
-	refundClient := refund.Client{B: stripe.GetBackend(stripe.APIBackend), Key: client.apiKey}
-	refund, err := refundClient.New(buildRefundParams(ctx, request))
+	if err := sleet.CheckRefundFields(request); err != nil {
+		return nil, err
+	}
+	refund, err := client.refunds.New(buildRefundParams(client.params(ctx), request))
 	if err != nil {
-		return &sleet.RefundResponse{Success: false, ErrorCode: common.SPtr(err.Error())}, nil
+		errorCode, err := translateError(err)
+		return &sleet.RefundResponse{Success: false, ErrorCode: errorCode}, err
 	}
 	return &sleet.RefundResponse{Success: true, TransactionReference: refund.ID}, nil
 }
 
-// Void an authorized transaction with charge ID
+// Void an authorized transaction with payment intent ID
 func (client *StripeClient) Void(request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
 	return client.VoidWithContext(context.TODO(), request)
 }
 
-// VoidWithContext an authorized transaction with charge ID
+// VoidWithContext cancels an authorized payment intent, which releases the authorization
 func (client *StripeClient) VoidWithContext(ctx context.Context, request *sleet.VoidRequest) (*sleet.VoidResponse, error) {
-	voidClient := refund.Client{B: stripe.GetBackend(stripe.APIBackend), Key: client.apiKey}
-	void, err := voidClient.New(buildVoidParams(ctx, request))
+	if err := sleet.CheckVoidFields(request); err != nil {
+		return nil, err
+	}
+	if isChargeID(request.TransactionReference) {
+		void, err := client.refunds.New(buildChargeVoidParams(client.params(ctx), request))
+		if err != nil {
+			errorCode, err := translateError(err)
+			return &sleet.VoidResponse{Success: false, ErrorCode: errorCode}, err
+		}
+		return &sleet.VoidResponse{Success: true, TransactionReference: void.ID}, nil
+	}
+
+	intent, err := client.paymentIntents.Cancel(request.TransactionReference, buildVoidParams(client.params(ctx), request))
 	if err != nil {
-		return &sleet.VoidResponse{Success: false, ErrorCode: common.SPtr(err.Error())}, nil
+		errorCode, err := translateError(err)
+		return &sleet.VoidResponse{Success: false, ErrorCode: errorCode}, err
 	}
-	return &sleet.VoidResponse{Success: true, TransactionReference: void.ID}, nil
+	return &sleet.VoidResponse{Success: true, TransactionReference: intent.ID}, nil
 }
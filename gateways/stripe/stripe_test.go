@@ -0,0 +1,172 @@
+package stripe
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BoltApp/sleet"
+	"github.com/BoltApp/sleet/common"
+)
+
+// recordingTransport answers every request with a payment intent and records the credentials it was sent with
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	authorization string
+	stripeAccount string
+	path          string
+}
+
+func (transport *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.mu.Lock()
+	transport.requests = append(transport.requests, recordedRequest{
+		authorization: req.Header.Get("Authorization"),
+		stripeAccount: req.Header.Get("Stripe-Account"),
+		path:          req.URL.Path,
+	})
+	transport.mu.Unlock()
+
+	body := `{"id": "pi_123", "object": "payment_intent", "status": "requires_capture"}`
+	if strings.HasSuffix(req.URL.Path, "/payment_methods") {
+		body = `{"id": "pm_123", "object": "payment_method"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestClientsWithDifferentKeysInParallel(t *testing.T) {
+	transport := &recordingTransport{}
+	httpClient := &http.Client{Transport: transport}
+	clients := map[string]*StripeClient{
+		"Bearer sk_test_merchant_a": NewWithHTTPClient("sk_test_merchant_a", httpClient),
+		"Bearer sk_test_merchant_b": NewWithHTTPClient("sk_test_merchant_b", httpClient),
+		"Bearer sk_test_platform":   NewConnectWithHTTPClient("sk_test_platform", "acct_connected", httpClient),
+	}
+	wantAccounts := map[string]string{
+		"Bearer sk_test_merchant_a": "",
+		"Bearer sk_test_merchant_b": "",
+		"Bearer sk_test_platform":   "acct_connected",
+	}
+
+	const authorizationsPerClient = 20
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		for i := 0; i < authorizationsPerClient; i++ {
+			wg.Add(1)
+			go func(client *StripeClient, i int) {
+				defer wg.Done()
+				request := &sleet.AuthorizationRequest{
+					Amount:     sleet.Amount{Amount: 100, Currency: "USD"},
+					CreditCard: &sleet.CreditCard{Number: "4111111111111111", ExpirationMonth: 10, ExpirationYear: 2030},
+				}
+				response, err := client.Authorize(request)
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+				if !response.Success {
+					t.Errorf("expected approved authorization %d, got %+v", i, response)
+				}
+			}(client, i)
+		}
+	}
+	wg.Wait()
+
+	counts := map[string]int{}
+	for _, request := range transport.requests {
+		wantAccount, ok := wantAccounts[request.authorization]
+		if !ok {
+			t.Errorf("request to %s sent with unexpected credentials %q", request.path, request.authorization)
+			continue
+		}
+		if request.stripeAccount != wantAccount {
+			t.Errorf("Got Stripe-Account %q for %q, want %q", request.stripeAccount, request.authorization, wantAccount)
+		}
+		counts[request.authorization]++
+	}
+	for authorization := range clients {
+		// each authorization creates a payment method and a payment intent
+		if got, want := counts[authorization], 2*authorizationsPerClient; got != want {
+			t.Errorf("Got %d requests for %q, want %d", got, authorization, want)
+		}
+	}
+}
+
+// BenchmarkAuthorize compares a client shared by all authorizations with a client built for each one, which pays
+// for building the Stripe backend and resource clients on every request
+func BenchmarkAuthorize(b *testing.B) {
+	httpClient := &http.Client{Transport: &recordingTransport{}}
+	request := &sleet.AuthorizationRequest{
+		Amount:     sleet.Amount{Amount: 100, Currency: "USD"},
+		CreditCard: &sleet.CreditCard{Number: "4111111111111111", ExpirationMonth: 10, ExpirationYear: 2030},
+	}
+
+	b.Run("SharedClient", func(b *testing.B) {
+		client := NewWithHTTPClient("sk_test_merchant", httpClient)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("SharedClientParallel", func(b *testing.B) {
+		client := NewWithHTTPClient("sk_test_merchant", httpClient)
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := client.Authorize(request); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	b.Run("ClientPerRequest", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewWithHTTPClient("sk_test_merchant", httpClient).Authorize(request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// failingTransport records the URLs requests are sent to and fails them, as an unavailable stand-in would
+type failingTransport struct {
+	urls []string
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.urls = append(transport.urls, req.URL.String())
+	return nil, errors.New("stand-in unavailable")
+}
+
+func TestBaseURLOverride(t *testing.T) {
+	transport := &failingTransport{}
+	client := NewWithHTTPClient("sk_test_merchant", &http.Client{Transport: transport}, common.WithBaseURL("http://localhost:12111"))
+
+	_, _ = client.Authorize(&sleet.AuthorizationRequest{
+		Amount:     sleet.Amount{Amount: 100, Currency: "USD"},
+		CreditCard: &sleet.CreditCard{Number: "4111111111111111", ExpirationMonth: 10, ExpirationYear: 2030},
+	})
+
+	want := "http://localhost:12111/v1/payment_methods"
+	if len(transport.urls) == 0 || transport.urls[0] != want {
+		t.Errorf("Got requests to %q, want %q", transport.urls, want)
+	}
+}
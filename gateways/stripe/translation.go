@@ -0,0 +1,270 @@
+package stripe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stripe/stripe-go"
+
+	"github.com/BoltApp/sleet"
+)
+
+// Codes taken from: https://stripe.com/docs/declines/codes
+var declineReasonMap = map[stripe.DeclineCode]sleet.DeclineReason{
+	stripe.DeclineCodeAuthenticationRequired:         sleet.DeclineReasonAuthenticationRequired,
+	stripe.DeclineCodeApproveWithID:                  sleet.DeclineReasonCallIssuer,
+	stripe.DeclineCodeCallIssuer:                     sleet.DeclineReasonCallIssuer,
+	stripe.DeclineCodeCardNotSupported:               sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeCardVelocityExceeded:           sleet.DeclineReasonLimitExceeded,
+	stripe.DeclineCodeCurrencyNotSupported:           sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeDoNotHonor:                     sleet.DeclineReasonDoNotHonor,
+	stripe.DeclineCodeDoNotTryAgain:                  sleet.DeclineReasonDoNotHonor,
+	stripe.DeclineCodeDuplicateTransaction:           sleet.DeclineReasonDuplicateTransaction,
+	stripe.DeclineCodeExpiredCard:                    sleet.DeclineReasonExpiredCard,
+	stripe.DeclineCodeFraudulent:                     sleet.DeclineReasonSuspectedFraud,
+	stripe.DeclineCodeGenericDecline:                 sleet.DeclineReasonDoNotHonor,
+	stripe.DeclineCodeIncorrectNumber:                sleet.DeclineReasonInvalidCardNumber,
+	stripe.DeclineCodeIncorrectCVC:                   sleet.DeclineReasonCVVFailure,
+	stripe.DeclineCodeIncorrectZip:                   sleet.DeclineReasonAVSFailure,
+	stripe.DeclineCodeInsufficientFunds:              sleet.DeclineReasonInsufficientFunds,
+	stripe.DeclineCodeInvalidAccount:                 sleet.DeclineReasonInvalidCardNumber,
+	stripe.DeclineCodeInvalidAmount:                  sleet.DeclineReasonInvalidAmount,
+	stripe.DeclineCodeInvalidCVC:                     sleet.DeclineReasonCVVFailure,
+	stripe.DeclineCodeInvalidExpiryYear:              sleet.DeclineReasonInvalidExpirationDate,
+	stripe.DeclineCodeInvalidNumber:                  sleet.DeclineReasonInvalidCardNumber,
+	stripe.DeclineCodeIssuerNotAvailable:             sleet.DeclineReasonIssuerUnavailable,
+	stripe.DeclineCodeLostCard:                       sleet.DeclineReasonLostOrStolenCard,
+	stripe.DeclineCodeMerchantBlacklist:              sleet.DeclineReasonSuspectedFraud,
+	stripe.DeclineCodeNewAccountInformationAvailable: sleet.DeclineReasonInvalidCardNumber,
+	stripe.DeclineCodeNotPermitted:                   sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodePickupCard:                     sleet.DeclineReasonPickUpCard,
+	stripe.DeclineCodePINTryExceeded:                 sleet.DeclineReasonLimitExceeded,
+	stripe.DeclineCodeProcessingError:                sleet.DeclineReasonProcessingError,
+	stripe.DeclineCodeReenterTransaction:             sleet.DeclineReasonProcessingError,
+	stripe.DeclineCodeRestrictedCard:                 sleet.DeclineReasonRestrictedCard,
+	stripe.DeclineCodeRevocationOfAllAuthorizations:  sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeRevocationOfAuthorization:      sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeSecurityViolation:              sleet.DeclineReasonSuspectedFraud,
+	stripe.DeclineCodeServiceNotAllowed:              sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeStolenCard:                     sleet.DeclineReasonLostOrStolenCard,
+	stripe.DeclineCodeStopPaymentOrder:               sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeTransactionNotAllowed:          sleet.DeclineReasonTransactionNotPermitted,
+	stripe.DeclineCodeTryAgainLater:                  sleet.DeclineReasonIssuerUnavailable,
+	stripe.DeclineCodeWithdrawalCountLimitExceeded:   sleet.DeclineReasonLimitExceeded,
+}
+
+// translateDeclineReason converts the decline code of a Stripe card error to its equivalent Sleet decline reason.
+// Errors that are not card errors are not declines.
+func translateDeclineReason(err error) sleet.DeclineReason {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) || stripeErr.Type != stripe.ErrorTypeCard {
+		return sleet.DeclineReasonNone
+	}
+	reason, ok := declineReasonMap[stripeErr.DeclineCode]
+	if !ok {
+		return sleet.DeclineReasonUnknown
+	}
+	return reason
+}
+
+// errorTypeIdempotency is returned when an idempotency key is reused with different parameters. It is not defined by
+// the client library.
+const errorTypeIdempotency stripe.ErrorType = "idempotency_error"
+
+var resultTypeMap = map[stripe.ErrorType]sleet.ResultType{
+	stripe.ErrorTypeCard:           sleet.ResultTypePaymentError,
+	stripe.ErrorTypeInvalidRequest: sleet.ResultTypeAPIError,
+	stripe.ErrorTypeAuthentication: sleet.ResultTypeAPIError,
+	stripe.ErrorTypePermission:     sleet.ResultTypeAPIError,
+	stripe.ErrorTypeRateLimit:      sleet.ResultTypeAPIError,
+	errorTypeIdempotency:           sleet.ResultTypeAPIError,
+	stripe.ErrorTypeAPI:            sleet.ResultTypeServerError,
+	stripe.ErrorTypeAPIConnection:  sleet.ResultTypeServerError,
+}
+
+// translateResultType converts the type of a Stripe error to the Sleet result type
+func translateResultType(errorType stripe.ErrorType) sleet.ResultType {
+	resultType, ok := resultTypeMap[errorType]
+	if !ok {
+		return sleet.ResultTypeUnknownError
+	}
+	return resultType
+}
+
+// translateErrorCode returns the most specific code of a Stripe error: the decline code of card errors, the error
+// code, or the error type when Stripe did not send a code
+func translateErrorCode(stripeErr *stripe.Error) string {
+	if stripeErr.DeclineCode != "" {
+		return string(stripeErr.DeclineCode)
+	}
+	if stripeErr.Code != "" {
+		return string(stripeErr.Code)
+	}
+	return string(stripeErr.Type)
+}
+
+// translateError returns the error code of a failed capture, void or refund. Stripe errors are reported through the
+// error code only, other errors such as network errors are returned.
+func translateError(err error) (*string, error) {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		errorCode := err.Error()
+		return &errorCode, err
+	}
+	errorCode := translateErrorCode(stripeErr)
+	return &errorCode, nil
+}
+
+// checks is the pair of postal code and address line 1 checks Stripe returns for a charge
+type checks struct {
+	postalCode stripe.CardVerification
+	line1      stripe.CardVerification
+}
+
+// An empty check means the component was not sent. Stripe does not report whether a 5 or 9 digit postal code was
+// matched; postal code matches map to the 5-digit codes.
+var avsMap = map[checks]sleet.AVSResponse{
+	{stripe.CardVerificationPass, stripe.CardVerificationPass}:               sleet.AVSResponseMatch,
+	{stripe.CardVerificationPass, stripe.CardVerificationFail}:               sleet.AVSResponseZip5MatchAddressNoMatch,
+	{stripe.CardVerificationPass, stripe.CardVerificationUnavailable}:        sleet.AVSResponseZipMatchAddressUnverified,
+	{stripe.CardVerificationPass, stripe.CardVerificationUnchecked}:          sleet.AVSResponseZipMatchAddressUnverified,
+	{stripe.CardVerificationPass, ""}:                                        sleet.AVSResponseZipMatchAddressUnverified,
+	{stripe.CardVerificationFail, stripe.CardVerificationPass}:               sleet.AVSResponseZipNoMatchAddressMatch,
+	{stripe.CardVerificationFail, stripe.CardVerificationFail}:               sleet.AVSResponseNoMatch,
+	{stripe.CardVerificationFail, stripe.CardVerificationUnavailable}:        sleet.AVSResponseZipNoMatchAddressUnverified,
+	{stripe.CardVerificationFail, stripe.CardVerificationUnchecked}:          sleet.AVSResponseZipNoMatchAddressUnverified,
+	{stripe.CardVerificationFail, ""}:                                        sleet.AVSResponseZipNoMatchAddressUnverified,
+	{stripe.CardVerificationUnavailable, stripe.CardVerificationPass}:        sleet.AVSResponseZipUnverifiedAddressMatch,
+	{stripe.CardVerificationUnchecked, stripe.CardVerificationPass}:          sleet.AVSResponseZipUnverifiedAddressMatch,
+	{"", stripe.CardVerificationPass}:                                        sleet.AVSResponseZipUnverifiedAddressMatch,
+	{stripe.CardVerificationUnavailable, stripe.CardVerificationFail}:        sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{stripe.CardVerificationUnchecked, stripe.CardVerificationFail}:          sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{"", stripe.CardVerificationFail}:                                        sleet.AVSResponseZipUnverifiedAddressNoMatch,
+	{stripe.CardVerificationUnavailable, stripe.CardVerificationUnavailable}: sleet.AVSResponseUnsupported,
+	{stripe.CardVerificationUnavailable, ""}:                                 sleet.AVSResponseUnsupported,
+	{stripe.CardVerificationUnchecked, stripe.CardVerificationUnchecked}:     sleet.AVSResponseSkipped,
+	{stripe.CardVerificationUnchecked, ""}:                                   sleet.AVSResponseSkipped,
+	{"", ""}:                                                                 sleet.AVSResponseSkipped,
+}
+
+// translateAvs converts the address_postal_code_check and address_line1_check of a charge to the equivalent Sleet
+// standard code
+func translateAvs(postalCode stripe.CardVerification, line1 stripe.CardVerification) sleet.AVSResponse {
+	sleetCode, ok := avsMap[checks{postalCode: postalCode, line1: line1}]
+	if !ok {
+		return sleet.AVSResponseUnknown
+	}
+	return sleetCode
+}
+
+var cvvMap = map[stripe.CardVerification]sleet.CVVResponse{
+	stripe.CardVerificationPass:        sleet.CVVResponseMatch,
+	stripe.CardVerificationFail:        sleet.CVVResponseNoMatch,
+	stripe.CardVerificationUnavailable: sleet.CVVResponseUnsupported,
+	stripe.CardVerificationUnchecked:   sleet.CVVResponseNotProcessed,
+	"":                                 sleet.CVVResponseSkipped,
+}
+
+// translateCvv converts the cvc_check of a charge to the equivalent Sleet standard code
+func translateCvv(cvcCheck stripe.CardVerification) sleet.CVVResponse {
+	sleetCode, ok := cvvMap[cvcCheck]
+	if !ok {
+		return sleet.CVVResponseUnknown
+	}
+	return sleetCode
+}
+
+// translateCharge adds the verification results and codes of the latest charge of a payment intent to the response
+func translateCharge(intent *stripe.PaymentIntent, response *sleet.AuthorizationResponse) {
+	if intent.Charges == nil || len(intent.Charges.Data) == 0 {
+		return
+	}
+	// charges are listed from newest to oldest
+	charge := intent.Charges.Data[0]
+	if charge.AuthorizationCode != "" {
+		response.Metadata = map[string]string{sleet.AuthCodeMetadata: charge.AuthorizationCode}
+	}
+	if charge.Outcome != nil {
+		response.Message = charge.Outcome.SellerMessage
+	}
+	if charge.PaymentMethodDetails == nil || charge.PaymentMethodDetails.Card == nil || charge.PaymentMethodDetails.Card.Checks == nil {
+		return
+	}
+	checks := charge.PaymentMethodDetails.Card.Checks
+	response.AvsResult = translateAvs(checks.AddressPostalCodeCheck, checks.AddressLine1Check)
+	response.AvsResultRaw = fmt.Sprintf("%s:%s", checks.AddressPostalCodeCheck, checks.AddressLine1Check)
+	response.CvvResult = translateCvv(checks.CVCCheck)
+	response.CvvResultRaw = string(checks.CVCCheck)
+}
+
+// translatePaymentIntent converts a confirmed payment intent to an authorization response. Intents awaiting capture
+// are approved, intents still processing are pending and all other intents were declined.
+func translatePaymentIntent(intent *stripe.PaymentIntent) *sleet.AuthorizationResponse {
+	response := &sleet.AuthorizationResponse{
+		TransactionReference: intent.ID,
+		Response:             string(intent.Status),
+		AvsResult:            sleet.AVSResponseUnknown,
+		CvvResult:            sleet.CVVResponseUnknown,
+	}
+	translateCharge(intent, response)
+
+	switch intent.Status {
+	case stripe.PaymentIntentStatusRequiresCapture, stripe.PaymentIntentStatusSucceeded:
+		response.Success = true
+		response.ResultType = sleet.ResultTypeSuccess
+	case stripe.PaymentIntentStatusProcessing:
+		response.ResultType = sleet.ResultTypePending
+		response.PendingReason = sleet.PendingReasonAsyncProcessing
+	case stripe.PaymentIntentStatusRequiresAction:
+		response.ResultType = sleet.ResultTypePaymentError
+		response.ErrorCode = string(stripe.DeclineCodeAuthenticationRequired)
+		response.DeclineReason = sleet.DeclineReasonAuthenticationRequired
+	default:
+		response.ResultType = sleet.ResultTypePaymentError
+		response.DeclineReason = sleet.DeclineReasonUnknown
+		if intent.LastPaymentError != nil {
+			response.ErrorCode = translateErrorCode(intent.LastPaymentError)
+			response.Message = intent.LastPaymentError.Msg
+			response.DeclineReason = translateDeclineReason(intent.LastPaymentError)
+		}
+	}
+	if !response.Success {
+		response.RetryAdvice = sleet.RetryAdviceFromDeclineReason(response.DeclineReason)
+	}
+	return response
+}
+
+// translateAuthorizationError converts the error of an authorization to a response. Declines and API errors are
+// returned as responses, server and network errors are returned as errors since the outcome of the authorization is
+// unknown.
+func translateAuthorizationError(err error) (*sleet.AuthorizationResponse, error) {
+	response := &sleet.AuthorizationResponse{
+		Success:    false,
+		AvsResult:  sleet.AVSResponseUnknown,
+		CvvResult:  sleet.CVVResponseUnknown,
+		ErrorCode:  err.Error(),
+		ResultType: sleet.ResultTypeServerError,
+	}
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return response, err
+	}
+
+	response.ErrorCode = translateErrorCode(stripeErr)
+	response.Message = stripeErr.Msg
+	response.StatusCode = stripeErr.HTTPStatusCode
+	response.ResultType = translateResultType(stripeErr.Type)
+	if response.ResultType == sleet.ResultTypeServerError {
+		return response, err
+	}
+	if stripeErr.PaymentIntent != nil {
+		response.TransactionReference = stripeErr.PaymentIntent.ID
+		translateCharge(stripeErr.PaymentIntent, response)
+		response.Message = stripeErr.Msg
+	}
+	response.DeclineReason = translateDeclineReason(err)
+	if response.DeclineReason != sleet.DeclineReasonNone {
+		response.RetryAdvice = sleet.RetryAdviceFromDeclineReason(response.DeclineReason)
+	}
+	return response, nil
+}
@@ -0,0 +1,178 @@
+package stripe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/stripe/stripe-go"
+
+	"github.com/BoltApp/sleet"
+)
+
+func TestTranslateAvs(t *testing.T) {
+	cases := []struct {
+		postalCode stripe.CardVerification
+		line1      stripe.CardVerification
+		want       sleet.AVSResponse
+	}{
+		{"pass", "pass", sleet.AVSResponseMatch},
+		{"pass", "fail", sleet.AVSResponseZip5MatchAddressNoMatch},
+		{"pass", "unchecked", sleet.AVSResponseZipMatchAddressUnverified},
+		{"pass", "", sleet.AVSResponseZipMatchAddressUnverified},
+		{"fail", "pass", sleet.AVSResponseZipNoMatchAddressMatch},
+		{"fail", "fail", sleet.AVSResponseNoMatch},
+		{"unavailable", "pass", sleet.AVSResponseZipUnverifiedAddressMatch},
+		{"unavailable", "unavailable", sleet.AVSResponseUnsupported},
+		{"unchecked", "unchecked", sleet.AVSResponseSkipped},
+		{"", "", sleet.AVSResponseSkipped},
+		{"fail", "unavailable", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"fail", "unchecked", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"fail", "", sleet.AVSResponseZipNoMatchAddressUnverified},
+		{"unavailable", "fail", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"unchecked", "fail", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"", "fail", sleet.AVSResponseZipUnverifiedAddressNoMatch},
+		{"Fake Result", "pass", sleet.AVSResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s:%s", c.postalCode, c.line1), func(t *testing.T) {
+			got := translateAvs(c.postalCode, c.line1)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslateCvv(t *testing.T) {
+	cases := []struct {
+		in   stripe.CardVerification
+		want sleet.CVVResponse
+	}{
+		{"pass", sleet.CVVResponseMatch},
+		{"fail", sleet.CVVResponseNoMatch},
+		{"unavailable", sleet.CVVResponseUnsupported},
+		{"unchecked", sleet.CVVResponseNotProcessed},
+		{"", sleet.CVVResponseSkipped},
+		{"Fake Result", sleet.CVVResponseUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.in), func(t *testing.T) {
+			got := translateCvv(c.in)
+			if got != c.want {
+				t.Errorf("Got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslatePaymentIntent(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/payment_intents_requires_capture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var intent stripe.PaymentIntent
+	if err := json.Unmarshal(raw, &intent); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Requires capture", func(t *testing.T) {
+		want := &sleet.AuthorizationResponse{
+			Success:              true,
+			TransactionReference: "pi_1IqkbRFSEDlaFyqY0nN8vE3z",
+			Response:             "requires_capture",
+			Message:              "Payment complete.",
+			ResultType:           sleet.ResultTypeSuccess,
+			AvsResult:            sleet.AVSResponseZip5MatchAddressNoMatch,
+			AvsResultRaw:         "pass:fail",
+			CvvResult:            sleet.CVVResponseMatch,
+			CvvResultRaw:         "pass",
+			Metadata:             map[string]string{sleet.AuthCodeMetadata: "123456"},
+		}
+		if diff := deep.Equal(translatePaymentIntent(&intent), want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Processing", func(t *testing.T) {
+		processing := intent
+		processing.Status = stripe.PaymentIntentStatusProcessing
+		got := translatePaymentIntent(&processing)
+		if got.Success || got.ResultType != sleet.ResultTypePending || got.PendingReason != sleet.PendingReasonAsyncProcessing {
+			t.Errorf("expected pending response, got %+v", got)
+		}
+	})
+
+	t.Run("Requires payment method", func(t *testing.T) {
+		declined := intent
+		declined.Status = stripe.PaymentIntentStatusRequiresPaymentMethod
+		declined.LastPaymentError = &stripe.Error{Type: stripe.ErrorTypeCard, Code: stripe.ErrorCodeCardDeclined, DeclineCode: stripe.DeclineCodeInsufficientFunds, Msg: "Your card has insufficient funds."}
+		got := translatePaymentIntent(&declined)
+		if got.Success || got.ResultType != sleet.ResultTypePaymentError || got.ErrorCode != "insufficient_funds" ||
+			got.DeclineReason != sleet.DeclineReasonInsufficientFunds || got.RetryAdvice == nil {
+			t.Errorf("expected declined response, got %+v", got)
+		}
+	})
+}
+
+func TestTranslateAuthorizationError(t *testing.T) {
+	t.Run("Card error", func(t *testing.T) {
+		err := &stripe.Error{
+			Type:           stripe.ErrorTypeCard,
+			Code:           stripe.ErrorCodeCardDeclined,
+			DeclineCode:    stripe.DeclineCodeInsufficientFunds,
+			Msg:            "Your card has insufficient funds.",
+			HTTPStatusCode: 402,
+			PaymentIntent:  &stripe.PaymentIntent{ID: "pi_123"},
+		}
+		got, gotErr := translateAuthorizationError(err)
+		if gotErr != nil {
+			t.Fatalf("unexpected error: %s", gotErr)
+		}
+		want := &sleet.AuthorizationResponse{
+			Success:              false,
+			TransactionReference: "pi_123",
+			AvsResult:            sleet.AVSResponseUnknown,
+			CvvResult:            sleet.CVVResponseUnknown,
+			ErrorCode:            "insufficient_funds",
+			Message:              "Your card has insufficient funds.",
+			StatusCode:           402,
+			ResultType:           sleet.ResultTypePaymentError,
+			DeclineReason:        sleet.DeclineReasonInsufficientFunds,
+			RetryAdvice:          sleet.RetryAdviceFromDeclineReason(sleet.DeclineReasonInsufficientFunds),
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		err := &stripe.Error{Type: stripe.ErrorTypeInvalidRequest, Code: stripe.ErrorCodeParameterMissing, HTTPStatusCode: 400}
+		got, gotErr := translateAuthorizationError(err)
+		if gotErr != nil || got.ResultType != sleet.ResultTypeAPIError || got.ErrorCode != "parameter_missing" || got.DeclineReason != sleet.DeclineReasonNone {
+			t.Errorf("expected API error response, got %+v, %v", got, gotErr)
+		}
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		err := &stripe.Error{Type: stripe.ErrorTypeAPI, HTTPStatusCode: 500}
+		got, gotErr := translateAuthorizationError(err)
+		if gotErr != err || got.ResultType != sleet.ResultTypeServerError || got.ErrorCode != "api_error" {
+			t.Errorf("expected server error, got %+v, %v", got, gotErr)
+		}
+	})
+
+	t.Run("Network error", func(t *testing.T) {
+		err := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		got, gotErr := translateAuthorizationError(err)
+		if gotErr != err || got.ResultType != sleet.ResultTypeServerError {
+			t.Errorf("expected server error, got %+v, %v", got, gotErr)
+		}
+	})
+}
@@ -9,7 +9,7 @@ import (
 	sleet_testing "github.com/BoltApp/sleet/testing"
 )
 
-// Note: For all of these tests, we enabled raw credit card processing to charges API
+// Note: For all of these tests, we enabled raw credit card processing to the PaymentMethods API
 // You can enable the setting here: https://dashboard.stripe.com/settings/integration
 // In the future, we might tokenize the card first through Stripe depending on demand
 
@@ -22,13 +22,21 @@ func TestStripeAuthorizeFailed(t *testing.T) {
 	failedRequest := sleet_testing.BaseAuthorizationRequest()
 	// set ClientTransactionReference to be empty
 	failedRequest.CreditCard.Number = "4000000000009995"
-	_, err := client.Authorize(failedRequest)
-	if err == nil {
+	auth, err := client.Authorize(failedRequest)
+	if err != nil {
+		t.Fatalf("Authorize request should have been declined without error- %s", err)
+	}
+
+	if auth.Success {
 		t.Error("Authorize request should have failed with bad card number")
 	}
 
-	if !strings.Contains(err.Error(), "Your card has insufficient funds.") {
-		t.Errorf("Response should contain insufficient funds- %s", err.Error())
+	if auth.DeclineReason != sleet.DeclineReasonInsufficientFunds {
+		t.Errorf("Decline reason should be insufficient funds- %s", auth.DeclineReason)
+	}
+
+	if !strings.Contains(auth.Message, "Your card has insufficient funds.") {
+		t.Errorf("Response should contain insufficient funds- %s", auth.Message)
 	}
 }
 
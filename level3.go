@@ -0,0 +1,292 @@
+package sleet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level3Derivation selects which side of the Level 3 data is computed from the other before validation
+type Level3Derivation int
+
+const (
+	// Level3DeriveNone validates the Level 3 data as provided
+	Level3DeriveNone Level3Derivation = iota
+	// Level3DeriveHeaderTotals sets TaxAmount and DiscountAmount to the sum of the line items
+	Level3DeriveHeaderTotals
+	// Level3DeriveLineItems distributes TaxAmount and DiscountAmount over the line items, proportionally to their
+	// gross amount, when none of the line items carry a tax or discount amount. The TotalAmount of the line items
+	// with a UnitPrice is then recomputed to include their share.
+	Level3DeriveLineItems
+)
+
+var level3DerivationToString = map[Level3Derivation]string{
+	Level3DeriveNone:         "Level3DeriveNone",
+	Level3DeriveHeaderTotals: "Level3DeriveHeaderTotals",
+	Level3DeriveLineItems:    "Level3DeriveLineItems",
+}
+
+// String returns a string representation of a Level 3 derivation
+func (derivation Level3Derivation) String() string {
+	return level3DerivationToString[derivation]
+}
+
+var stringToLevel3Derivation = func() map[string]Level3Derivation {
+	values := make(map[string]Level3Derivation, len(level3DerivationToString))
+	for value, name := range level3DerivationToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseLevel3Derivation returns the Level 3 derivation with the given name
+func ParseLevel3Derivation(name string) (Level3Derivation, error) {
+	derivation, ok := stringToLevel3Derivation[name]
+	if !ok {
+		return Level3DeriveNone, &EnumNameError{Enum: "Level3Derivation", Name: name}
+	}
+	return derivation, nil
+}
+
+// MarshalText encodes the Level 3 derivation as its name
+func (derivation Level3Derivation) MarshalText() ([]byte, error) {
+	return marshalEnumText("Level3Derivation", int(derivation), derivation.String())
+}
+
+// UnmarshalText decodes the Level 3 derivation from its name
+func (derivation *Level3Derivation) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel3Derivation(string(text))
+	if err != nil {
+		return err
+	}
+	*derivation = parsed
+	return nil
+}
+
+// MarshalJSON encodes the Level 3 derivation as a JSON string of its name
+func (derivation Level3Derivation) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(derivation)
+}
+
+// UnmarshalJSON decodes the Level 3 derivation from a JSON string of its name, or from a JSON number of its value
+func (derivation *Level3Derivation) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("Level3Derivation", data, derivation, func(value int) string { return Level3Derivation(value).String() })
+}
+
+// Level3ValidationOptions configures how Level 3 data is reconciled with the authorization amount.
+//
+// Line item totals are expected to equal UnitPrice * Quantity - ItemDiscountAmount, plus ItemTaxAmount when
+// LineTotalsIncludeTax is set. The authorization amount is expected to equal the sum of the line item totals plus
+// ShippingAmount, DutyAmount and, unless LineTotalsIncludeTax is set, TaxAmount.
+type Level3ValidationOptions struct {
+	Derive               Level3Derivation
+	LineTotalsIncludeTax bool
+	// Tolerance is the difference, in minor units, accepted between an expected and an actual amount to allow for rounding
+	Tolerance int64
+}
+
+// Level3Field identifies the Level 3 amount that failed validation
+type Level3Field string
+
+const (
+	Level3FieldAmount              Level3Field = "Amount"
+	Level3FieldTaxAmount           Level3Field = "TaxAmount"
+	Level3FieldDiscountAmount      Level3Field = "DiscountAmount"
+	Level3FieldLineItemTotalAmount Level3Field = "LineItem.TotalAmount"
+)
+
+// level3HeaderLineItemIndex is the LineItemIndex of mismatches on header totals
+const level3HeaderLineItemIndex = -1
+
+// Level3Mismatch describes an amount that does not reconcile with the rest of the Level 3 data
+type Level3Mismatch struct {
+	Field Level3Field
+	// LineItemIndex is the index of the offending line item, or -1 when the mismatch concerns a header total
+	LineItemIndex int
+	Expected      int64
+	Actual        int64
+}
+
+func (m Level3Mismatch) String() string {
+	if m.LineItemIndex >= 0 {
+		return fmt.Sprintf("%s of line item %d is %d, expected %d", m.Field, m.LineItemIndex, m.Actual, m.Expected)
+	}
+	return fmt.Sprintf("%s is %d, expected %d", m.Field, m.Actual, m.Expected)
+}
+
+// Level3ValidationError is returned when Level 3 data does not reconcile with its line items or the authorization amount
+type Level3ValidationError struct {
+	Mismatches []Level3Mismatch
+}
+
+func (e *Level3ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Mismatches))
+	for _, mismatch := range e.Mismatches {
+		messages = append(messages, mismatch.String())
+	}
+	return "level 3 data does not reconcile: " + strings.Join(messages, "; ")
+}
+
+// ValidateLevel3Data checks that the line items of level3 add up to its header totals and to the authorization amount.
+// Data without line items is not validated. A *Level3ValidationError listing every mismatch is returned on failure.
+func ValidateLevel3Data(amount Amount, level3 *Level3Data, options Level3ValidationOptions) error {
+	if level3 == nil || len(level3.LineItems) == 0 {
+		return nil
+	}
+
+	var mismatches []Level3Mismatch
+	check := func(field Level3Field, index int, expected int64, actual int64) {
+		if abs(expected-actual) > options.Tolerance {
+			mismatches = append(mismatches, Level3Mismatch{Field: field, LineItemIndex: index, Expected: expected, Actual: actual})
+		}
+	}
+
+	var lineTotal, lineTax, lineDiscount int64
+	for i, lineItem := range level3.LineItems {
+		if lineItem.UnitPrice.Amount != 0 {
+			check(Level3FieldLineItemTotalAmount, i, expectedLineItemTotal(lineItem, options), lineItem.TotalAmount.Amount)
+		}
+		lineTotal += lineItem.TotalAmount.Amount
+		lineTax += lineItem.ItemTaxAmount.Amount
+		lineDiscount += lineItem.ItemDiscountAmount.Amount
+	}
+	check(Level3FieldTaxAmount, level3HeaderLineItemIndex, lineTax, level3.TaxAmount.Amount)
+	check(Level3FieldDiscountAmount, level3HeaderLineItemIndex, lineDiscount, level3.DiscountAmount.Amount)
+
+	expectedAmount := lineTotal + level3.ShippingAmount.Amount + level3.DutyAmount.Amount
+	if !options.LineTotalsIncludeTax {
+		expectedAmount += level3.TaxAmount.Amount
+	}
+	check(Level3FieldAmount, level3HeaderLineItemIndex, expectedAmount, amount.Amount)
+
+	if len(mismatches) > 0 {
+		return &Level3ValidationError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// DeriveLevel3Data returns a copy of level3 in which the amounts selected by options.Derive are computed from the
+// rest of the data. Line items without a TotalAmount get one computed from their unit price in every mode, as do all
+// line items with a unit price once Level3DeriveLineItems has distributed an amount over them.
+func DeriveLevel3Data(level3 *Level3Data, options Level3ValidationOptions) *Level3Data {
+	if level3 == nil {
+		return nil
+	}
+	derived := *level3
+	derived.LineItems = append([]LineItem(nil), level3.LineItems...)
+	if len(derived.LineItems) == 0 {
+		return &derived
+	}
+
+	var distributed bool
+	switch options.Derive {
+	case Level3DeriveHeaderTotals:
+		var tax, discount int64
+		for _, lineItem := range derived.LineItems {
+			tax += lineItem.ItemTaxAmount.Amount
+			discount += lineItem.ItemDiscountAmount.Amount
+		}
+		derived.TaxAmount = Amount{Amount: tax, Currency: derived.TaxAmount.Currency}
+		derived.DiscountAmount = Amount{Amount: discount, Currency: derived.DiscountAmount.Currency}
+	case Level3DeriveLineItems:
+		weights := make([]int64, len(derived.LineItems))
+		for i, lineItem := range derived.LineItems {
+			weights[i] = lineItemGross(lineItem)
+		}
+		if allLineItemsZero(derived.LineItems, func(l LineItem) int64 { return l.ItemDiscountAmount.Amount }) {
+			for i, share := range distribute(derived.DiscountAmount.Amount, weights) {
+				derived.LineItems[i].ItemDiscountAmount = Amount{Amount: share, Currency: derived.DiscountAmount.Currency}
+			}
+			distributed = distributed || derived.DiscountAmount.Amount != 0
+		}
+		if allLineItemsZero(derived.LineItems, func(l LineItem) int64 { return l.ItemTaxAmount.Amount }) {
+			for i, share := range distribute(derived.TaxAmount.Amount, weights) {
+				derived.LineItems[i].ItemTaxAmount = Amount{Amount: share, Currency: derived.TaxAmount.Currency}
+			}
+			distributed = distributed || derived.TaxAmount.Amount != 0
+		}
+	}
+
+	for i, lineItem := range derived.LineItems {
+		if (lineItem.TotalAmount.Amount == 0 || distributed) && lineItem.UnitPrice.Amount != 0 {
+			derived.LineItems[i].TotalAmount = Amount{
+				Amount:   expectedLineItemTotal(lineItem, options),
+				Currency: lineItem.UnitPrice.Currency,
+			}
+		}
+	}
+	return &derived
+}
+
+// PrepareLevel3Data applies the Level3ValidationOption of request, if any. The returned request is a shallow copy
+// carrying the derived Level 3 data; request itself is not modified. Requests without the option are returned as-is.
+func PrepareLevel3Data(request *AuthorizationRequest) (*AuthorizationRequest, error) {
+	if request.Level3Data == nil || request.Options[Level3ValidationOption] == nil {
+		return request, nil
+	}
+	options, ok := request.Options[Level3ValidationOption].(Level3ValidationOptions)
+	if !ok {
+		return nil, fmt.Errorf("%s option must be of type Level3ValidationOptions", Level3ValidationOption)
+	}
+
+	prepared := *request
+	prepared.Level3Data = DeriveLevel3Data(request.Level3Data, options)
+	if err := ValidateLevel3Data(prepared.Amount, prepared.Level3Data, options); err != nil {
+		return nil, err
+	}
+	return &prepared, nil
+}
+
+func expectedLineItemTotal(lineItem LineItem, options Level3ValidationOptions) int64 {
+	total := lineItem.UnitPrice.Amount*lineItem.Quantity - lineItem.ItemDiscountAmount.Amount
+	if options.LineTotalsIncludeTax {
+		total += lineItem.ItemTaxAmount.Amount
+	}
+	return total
+}
+
+// lineItemGross is the amount of a line item before discount and tax, used to weight distributed header amounts
+func lineItemGross(lineItem LineItem) int64 {
+	if lineItem.UnitPrice.Amount != 0 {
+		return lineItem.UnitPrice.Amount * lineItem.Quantity
+	}
+	return lineItem.TotalAmount.Amount
+}
+
+// allLineItemsZero reports whether amount is zero for every line item
+func allLineItemsZero(lineItems []LineItem, amount func(LineItem) int64) bool {
+	for _, lineItem := range lineItems {
+		if amount(lineItem) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// distribute splits total proportionally to weights. Rounding remainders go to the last line item so the shares
+// always add up to total. Without any weight the total is split evenly.
+func distribute(total int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var weightSum int64
+	for _, weight := range weights {
+		weightSum += weight
+	}
+
+	var allocated int64
+	for i := range weights {
+		if weightSum != 0 {
+			shares[i] = total * weights[i] / weightSum
+		} else {
+			shares[i] = total / int64(len(weights))
+		}
+		allocated += shares[i]
+	}
+	shares[len(shares)-1] += total - allocated
+	return shares
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
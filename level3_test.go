@@ -0,0 +1,192 @@
+package sleet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func baseLevel3Data() *Level3Data {
+	return &Level3Data{
+		TaxAmount:      Amount{Amount: 80, Currency: "USD"},
+		DiscountAmount: Amount{Amount: 100, Currency: "USD"},
+		ShippingAmount: Amount{Amount: 300, Currency: "USD"},
+		LineItems: []LineItem{
+			{
+				UnitPrice:          Amount{Amount: 500, Currency: "USD"},
+				Quantity:           2,
+				TotalAmount:        Amount{Amount: 900, Currency: "USD"},
+				ItemTaxAmount:      Amount{Amount: 20, Currency: "USD"},
+				ItemDiscountAmount: Amount{Amount: 100, Currency: "USD"},
+			},
+			{
+				UnitPrice:     Amount{Amount: 1000, Currency: "USD"},
+				Quantity:      3,
+				TotalAmount:   Amount{Amount: 3000, Currency: "USD"},
+				ItemTaxAmount: Amount{Amount: 60, Currency: "USD"},
+			},
+		},
+	}
+}
+
+func TestValidateLevel3Data(t *testing.T) {
+	amount := Amount{Amount: 4280, Currency: "USD"}
+
+	t.Run("Consistent data", func(t *testing.T) {
+		if err := ValidateLevel3Data(amount, baseLevel3Data(), Level3ValidationOptions{}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("No line items", func(t *testing.T) {
+		if err := ValidateLevel3Data(amount, &Level3Data{TaxAmount: Amount{Amount: 1}}, Level3ValidationOptions{}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Line totals include tax", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.LineItems[0].TotalAmount.Amount = 920
+		level3.LineItems[1].TotalAmount.Amount = 3060
+		if err := ValidateLevel3Data(amount, level3, Level3ValidationOptions{LineTotalsIncludeTax: true}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Mismatches", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.TaxAmount.Amount = 90
+		level3.LineItems[1].TotalAmount.Amount = 2999
+
+		err := ValidateLevel3Data(amount, level3, Level3ValidationOptions{})
+		var validationErr *Level3ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected *Level3ValidationError, got %v", err)
+		}
+		want := []Level3Mismatch{
+			{Field: Level3FieldLineItemTotalAmount, LineItemIndex: 1, Expected: 3000, Actual: 2999},
+			{Field: Level3FieldTaxAmount, LineItemIndex: -1, Expected: 80, Actual: 90},
+			{Field: Level3FieldAmount, LineItemIndex: -1, Expected: 4289, Actual: 4280},
+		}
+		if diff := deep.Equal(validationErr.Mismatches, want); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Tolerance", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.LineItems[1].TotalAmount.Amount = 2999
+		if err := ValidateLevel3Data(Amount{Amount: 4279}, level3, Level3ValidationOptions{Tolerance: 1}); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+}
+
+func TestDeriveLevel3Data(t *testing.T) {
+	t.Run("Header totals", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.TaxAmount.Amount = 0
+		level3.DiscountAmount.Amount = 0
+
+		derived := DeriveLevel3Data(level3, Level3ValidationOptions{Derive: Level3DeriveHeaderTotals})
+		if diff := deep.Equal(derived, baseLevel3Data()); diff != nil {
+			t.Error(diff)
+		}
+		if level3.TaxAmount.Amount != 0 {
+			t.Error("input was modified")
+		}
+	})
+
+	t.Run("Line items", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.TaxAmount.Amount = 101
+		for i := range level3.LineItems {
+			level3.LineItems[i].TotalAmount = Amount{}
+			level3.LineItems[i].ItemTaxAmount = Amount{}
+			level3.LineItems[i].ItemDiscountAmount = Amount{}
+		}
+
+		derived := DeriveLevel3Data(level3, Level3ValidationOptions{Derive: Level3DeriveLineItems})
+		want := []LineItem{
+			{
+				UnitPrice:          Amount{Amount: 500, Currency: "USD"},
+				Quantity:           2,
+				TotalAmount:        Amount{Amount: 975, Currency: "USD"},
+				ItemTaxAmount:      Amount{Amount: 25, Currency: "USD"},
+				ItemDiscountAmount: Amount{Amount: 25, Currency: "USD"},
+			},
+			{
+				UnitPrice:          Amount{Amount: 1000, Currency: "USD"},
+				Quantity:           3,
+				TotalAmount:        Amount{Amount: 2925, Currency: "USD"},
+				ItemTaxAmount:      Amount{Amount: 76, Currency: "USD"},
+				ItemDiscountAmount: Amount{Amount: 75, Currency: "USD"},
+			},
+		}
+		if diff := deep.Equal(derived.LineItems, want); diff != nil {
+			t.Error(diff)
+		}
+		if err := ValidateLevel3Data(Amount{Amount: 975 + 2925 + 101 + 300}, derived, Level3ValidationOptions{}); err != nil {
+			t.Errorf("derived data does not validate: %s", err)
+		}
+	})
+
+	t.Run("Line items with gross totals", func(t *testing.T) {
+		level3 := baseLevel3Data()
+		level3.TaxAmount.Amount = 101
+		for i, lineItem := range level3.LineItems {
+			level3.LineItems[i].TotalAmount = Amount{Amount: lineItem.UnitPrice.Amount * lineItem.Quantity, Currency: "USD"}
+			level3.LineItems[i].ItemTaxAmount = Amount{}
+			level3.LineItems[i].ItemDiscountAmount = Amount{}
+		}
+
+		options := Level3ValidationOptions{Derive: Level3DeriveLineItems, LineTotalsIncludeTax: true}
+		derived := DeriveLevel3Data(level3, options)
+		wantTotals := []int64{1000, 3001}
+		for i, lineItem := range derived.LineItems {
+			if lineItem.TotalAmount.Amount != wantTotals[i] {
+				t.Errorf("Got total %d for line item %d, want %d", lineItem.TotalAmount.Amount, i, wantTotals[i])
+			}
+		}
+		if err := ValidateLevel3Data(Amount{Amount: 1000 + 3001 + 300}, derived, options); err != nil {
+			t.Errorf("derived data does not validate: %s", err)
+		}
+	})
+}
+
+func TestPrepareLevel3Data(t *testing.T) {
+	request := &AuthorizationRequest{Amount: Amount{Amount: 4280, Currency: "USD"}, Level3Data: baseLevel3Data()}
+
+	t.Run("Without option", func(t *testing.T) {
+		got, err := PrepareLevel3Data(request)
+		if err != nil || got != request {
+			t.Errorf("expected request to be returned as-is, got (%v, %v)", got, err)
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		withOption := *request
+		withOption.Options = map[string]interface{}{Level3ValidationOption: Level3ValidationOptions{}}
+		if _, err := PrepareLevel3Data(&withOption); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		withOption := *request
+		withOption.Amount.Amount = 1
+		withOption.Options = map[string]interface{}{Level3ValidationOption: Level3ValidationOptions{}}
+		if _, err := PrepareLevel3Data(&withOption); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("Wrong option type", func(t *testing.T) {
+		withOption := *request
+		withOption.Options = map[string]interface{}{Level3ValidationOption: true}
+		if _, err := PrepareLevel3Data(&withOption); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
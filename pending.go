@@ -0,0 +1,115 @@
+package sleet
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// PendingReason explains why an authorization with ResultTypePending has not been approved or declined yet.
+type PendingReason int
+
+// Consts representing the reasons an authorization can be pending
+const (
+	PendingReasonNone            PendingReason = iota // The authorization is not pending.
+	PendingReasonUnknown                              // The gateway did not specify why the authorization is pending.
+	PendingReasonFraudReview                          // The authorization is held for a fraud or risk review.
+	PendingReasonAsyncProcessing                      // The authorization is processed asynchronously by the gateway.
+)
+
+var pendingReasonToString = map[PendingReason]string{
+	PendingReasonNone:            "PendingReasonNone",
+	PendingReasonUnknown:         "PendingReasonUnknown",
+	PendingReasonFraudReview:     "PendingReasonFraudReview",
+	PendingReasonAsyncProcessing: "PendingReasonAsyncProcessing",
+}
+
+// String returns a string representation of a pending reason
+func (reason PendingReason) String() string {
+	return pendingReasonToString[reason]
+}
+
+var stringToPendingReason = func() map[string]PendingReason {
+	values := make(map[string]PendingReason, len(pendingReasonToString))
+	for value, name := range pendingReasonToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParsePendingReason returns the pending reason with the given name
+func ParsePendingReason(name string) (PendingReason, error) {
+	reason, ok := stringToPendingReason[name]
+	if !ok {
+		return PendingReasonNone, &EnumNameError{Enum: "PendingReason", Name: name}
+	}
+	return reason, nil
+}
+
+// MarshalText encodes the pending reason as its name
+func (reason PendingReason) MarshalText() ([]byte, error) {
+	return marshalEnumText("PendingReason", int(reason), reason.String())
+}
+
+// UnmarshalText decodes the pending reason from its name
+func (reason *PendingReason) UnmarshalText(text []byte) error {
+	parsed, err := ParsePendingReason(string(text))
+	if err != nil {
+		return err
+	}
+	*reason = parsed
+	return nil
+}
+
+// MarshalJSON encodes the pending reason as a JSON string of its name
+func (reason PendingReason) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(reason)
+}
+
+// UnmarshalJSON decodes the pending reason from a JSON string of its name, or from a JSON number of its value
+func (reason *PendingReason) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("PendingReason", data, reason, func(value int) string { return PendingReason(value).String() })
+}
+
+// ErrStillPending is returned by PollTransactionDetails when the transaction has not resolved before the deadline.
+var ErrStillPending = errors.New("transaction is still pending")
+
+// TransactionDetailsClient is implemented by the gateways that can look up the status of a transaction.
+type TransactionDetailsClient interface {
+	GetTransactionDetailsWithContext(ctx context.Context, request *TransactionDetailsRequest) (*TransactionDetailsResponse, error)
+}
+
+// PollTransactionDetails looks up the transaction every interval until its ResultType is no longer
+// ResultTypePending and returns the resolved details. If the deadline passes first, the last details are returned
+// together with ErrStillPending. Lookup errors and the cancellation of ctx stop the polling immediately.
+func PollTransactionDetails(
+	ctx context.Context,
+	client TransactionDetailsClient,
+	request *TransactionDetailsRequest,
+	interval time.Duration,
+	deadline time.Time,
+) (*TransactionDetailsResponse, error) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var details *TransactionDetailsResponse
+	for {
+		select {
+		case <-ctx.Done():
+			return details, ctx.Err()
+		case <-timer.C:
+		}
+
+		var err error
+		details, err = client.GetTransactionDetailsWithContext(ctx, request)
+		if err != nil {
+			return details, err
+		}
+		if details.ResultType != ResultTypePending {
+			return details, nil
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return details, ErrStillPending
+		}
+		timer.Reset(interval)
+	}
+}
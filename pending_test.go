@@ -0,0 +1,67 @@
+package sleet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeTransactionDetailsClient struct {
+	responses []*TransactionDetailsResponse
+	err       error
+	calls     int
+}
+
+func (c *fakeTransactionDetailsClient) GetTransactionDetailsWithContext(_ context.Context, _ *TransactionDetailsRequest) (*TransactionDetailsResponse, error) {
+	response := c.responses[c.calls]
+	if c.calls < len(c.responses)-1 {
+		c.calls++
+	}
+	return response, c.err
+}
+
+func TestPollTransactionDetails(t *testing.T) {
+	pending := &TransactionDetailsResponse{ResultType: ResultTypePending, PendingReason: PendingReasonFraudReview}
+	approved := &TransactionDetailsResponse{ResultType: ResultTypeSuccess}
+	request := &TransactionDetailsRequest{TransactionReference: "txn"}
+
+	t.Run("Resolves", func(t *testing.T) {
+		client := &fakeTransactionDetailsClient{responses: []*TransactionDetailsResponse{pending, pending, approved}}
+		got, err := PollTransactionDetails(context.Background(), client, request, time.Millisecond, time.Now().Add(time.Second))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != approved || client.calls != 2 {
+			t.Errorf("expected approved details after 3 lookups, got %v after %d", got, client.calls+1)
+		}
+	})
+
+	t.Run("Deadline passes", func(t *testing.T) {
+		client := &fakeTransactionDetailsClient{responses: []*TransactionDetailsResponse{pending}}
+		got, err := PollTransactionDetails(context.Background(), client, request, 10*time.Millisecond, time.Now().Add(25*time.Millisecond))
+		if !errors.Is(err, ErrStillPending) {
+			t.Errorf("expected ErrStillPending, got %v", err)
+		}
+		if got != pending {
+			t.Errorf("expected last pending details, got %v", got)
+		}
+	})
+
+	t.Run("Lookup fails", func(t *testing.T) {
+		lookupErr := errors.New("connection reset")
+		client := &fakeTransactionDetailsClient{responses: []*TransactionDetailsResponse{nil}, err: lookupErr}
+		if _, err := PollTransactionDetails(context.Background(), client, request, time.Millisecond, time.Now().Add(time.Second)); !errors.Is(err, lookupErr) {
+			t.Errorf("expected lookup error, got %v", err)
+		}
+	})
+
+	t.Run("Context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		client := &fakeTransactionDetailsClient{responses: []*TransactionDetailsResponse{pending}}
+		if _, err := PollTransactionDetails(ctx, client, request, time.Millisecond, time.Now().Add(time.Second)); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
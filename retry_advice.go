@@ -0,0 +1,283 @@
+package sleet
+
+import (
+	"strings"
+	"time"
+)
+
+// DeclineType distinguishes declines that may be retried from declines the card networks forbid retrying.
+type DeclineType int
+
+// Consts representing the decline types
+const (
+	DeclineTypeUnknown DeclineType = iota // The decline could not be classified.
+	DeclineTypeSoft                       // The decline is temporary and the transaction may be retried.
+	DeclineTypeHard                       // The decline is permanent and the transaction must not be retried as is.
+)
+
+var declineTypeToString = map[DeclineType]string{
+	DeclineTypeUnknown: "DeclineTypeUnknown",
+	DeclineTypeSoft:    "DeclineTypeSoft",
+	DeclineTypeHard:    "DeclineTypeHard",
+}
+
+// String returns a string representation of a decline type
+func (declineType DeclineType) String() string {
+	return declineTypeToString[declineType]
+}
+
+var stringToDeclineType = func() map[string]DeclineType {
+	values := make(map[string]DeclineType, len(declineTypeToString))
+	for value, name := range declineTypeToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseDeclineType returns the decline type with the given name
+func ParseDeclineType(name string) (DeclineType, error) {
+	declineType, ok := stringToDeclineType[name]
+	if !ok {
+		return DeclineTypeUnknown, &EnumNameError{Enum: "DeclineType", Name: name}
+	}
+	return declineType, nil
+}
+
+// MarshalText encodes the decline type as its name
+func (declineType DeclineType) MarshalText() ([]byte, error) {
+	return marshalEnumText("DeclineType", int(declineType), declineType.String())
+}
+
+// UnmarshalText decodes the decline type from its name
+func (declineType *DeclineType) UnmarshalText(text []byte) error {
+	parsed, err := ParseDeclineType(string(text))
+	if err != nil {
+		return err
+	}
+	*declineType = parsed
+	return nil
+}
+
+// MarshalJSON encodes the decline type as a JSON string of its name
+func (declineType DeclineType) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(declineType)
+}
+
+// UnmarshalJSON decodes the decline type from a JSON string of its name, or from a JSON number of its value
+func (declineType *DeclineType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("DeclineType", data, declineType, func(value int) string { return DeclineType(value).String() })
+}
+
+// RetryAdviceSource identifies the code a RetryAdvice was derived from.
+type RetryAdviceSource int
+
+// Consts representing the sources of retry advice, from least to most specific
+const (
+	RetryAdviceSourceDeclineReason      RetryAdviceSource = iota // Derived from the DeclineReason of the gateway code.
+	RetryAdviceSourceGateway                                     // Derived from a gateway specific soft/hard decline indicator.
+	RetryAdviceSourceVisaCategory                                // Derived from the Visa decline category of the network response code.
+	RetryAdviceSourceMerchantAdviceCode                          // Derived from the Mastercard Merchant Advice Code.
+)
+
+var retryAdviceSourceToString = map[RetryAdviceSource]string{
+	RetryAdviceSourceDeclineReason:      "RetryAdviceSourceDeclineReason",
+	RetryAdviceSourceGateway:            "RetryAdviceSourceGateway",
+	RetryAdviceSourceVisaCategory:       "RetryAdviceSourceVisaCategory",
+	RetryAdviceSourceMerchantAdviceCode: "RetryAdviceSourceMerchantAdviceCode",
+}
+
+// String returns a string representation of a retry advice source
+func (source RetryAdviceSource) String() string {
+	return retryAdviceSourceToString[source]
+}
+
+var stringToRetryAdviceSource = func() map[string]RetryAdviceSource {
+	values := make(map[string]RetryAdviceSource, len(retryAdviceSourceToString))
+	for value, name := range retryAdviceSourceToString {
+		values[name] = value
+	}
+	return values
+}()
+
+// ParseRetryAdviceSource returns the retry advice source with the given name
+func ParseRetryAdviceSource(name string) (RetryAdviceSource, error) {
+	source, ok := stringToRetryAdviceSource[name]
+	if !ok {
+		return RetryAdviceSourceDeclineReason, &EnumNameError{Enum: "RetryAdviceSource", Name: name}
+	}
+	return source, nil
+}
+
+// MarshalText encodes the retry advice source as its name
+func (source RetryAdviceSource) MarshalText() ([]byte, error) {
+	return marshalEnumText("RetryAdviceSource", int(source), source.String())
+}
+
+// UnmarshalText decodes the retry advice source from its name
+func (source *RetryAdviceSource) UnmarshalText(text []byte) error {
+	parsed, err := ParseRetryAdviceSource(string(text))
+	if err != nil {
+		return err
+	}
+	*source = parsed
+	return nil
+}
+
+// MarshalJSON encodes the retry advice source as a JSON string of its name
+func (source RetryAdviceSource) MarshalJSON() ([]byte, error) {
+	return marshalEnumJSON(source)
+}
+
+// UnmarshalJSON decodes the retry advice source from a JSON string of its name, or from a JSON number of its value
+func (source *RetryAdviceSource) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON("RetryAdviceSource", data, source, func(value int) string { return RetryAdviceSource(value).String() })
+}
+
+// RetryAdvice classifies a declined authorization. Retryable is true if the same card may be authorized again, after
+// waiting RetryAfter if it is set. UpdateCard is true if the card details should be refreshed, for example through an
+// account updater or by asking the cardholder, before trying again.
+type RetryAdvice struct {
+	DeclineType DeclineType
+	Retryable   bool
+	RetryAfter  time.Duration
+	UpdateCard  bool
+	Source      RetryAdviceSource
+	// Code is the merchant advice code, network response code or gateway code the advice was derived from.
+	Code string
+}
+
+var (
+	softDecline = RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true}
+	hardDecline = RetryAdvice{DeclineType: DeclineTypeHard}
+	updateCard  = RetryAdvice{DeclineType: DeclineTypeHard, UpdateCard: true}
+	fixAndRetry = RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, UpdateCard: true}
+)
+
+var declineReasonToRetryAdvice = map[DeclineReason]RetryAdvice{
+	DeclineReasonUnknown:                 {},
+	DeclineReasonInsufficientFunds:       softDecline,
+	DeclineReasonDoNotHonor:              softDecline,
+	DeclineReasonExpiredCard:             updateCard,
+	DeclineReasonInvalidCardNumber:       updateCard,
+	DeclineReasonInvalidExpirationDate:   fixAndRetry,
+	DeclineReasonLostOrStolenCard:        hardDecline,
+	DeclineReasonSuspectedFraud:          hardDecline,
+	DeclineReasonCVVFailure:              fixAndRetry,
+	DeclineReasonAVSFailure:              fixAndRetry,
+	DeclineReasonLimitExceeded:           softDecline,
+	DeclineReasonRestrictedCard:          hardDecline,
+	DeclineReasonTransactionNotPermitted: hardDecline,
+	DeclineReasonCallIssuer:              hardDecline,
+	DeclineReasonPickUpCard:              hardDecline,
+	DeclineReasonInvalidAmount:           fixAndRetry,
+	DeclineReasonAuthenticationRequired:  softDecline,
+	DeclineReasonDuplicateTransaction:    hardDecline,
+	DeclineReasonIssuerUnavailable:       softDecline,
+	DeclineReasonProcessingError:         softDecline,
+}
+
+// RetryAdviceFromDeclineReason returns the baseline advice for a decline reason. It returns nil for DeclineReasonNone.
+func RetryAdviceFromDeclineReason(reason DeclineReason) *RetryAdvice {
+	if reason == DeclineReasonNone {
+		return nil
+	}
+	advice := declineReasonToRetryAdvice[reason]
+	advice.Source = RetryAdviceSourceDeclineReason
+	advice.Code = reason.String()
+	return &advice
+}
+
+var merchantAdviceCodeToRetryAdvice = map[string]RetryAdvice{
+	"01": updateCard,
+	"02": softDecline,
+	"03": hardDecline,
+	"04": hardDecline,
+	"21": hardDecline,
+	"22": hardDecline,
+	"24": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: time.Hour},
+	"25": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 24 * time.Hour},
+	"26": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 2 * 24 * time.Hour},
+	"27": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 4 * 24 * time.Hour},
+	"28": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 6 * 24 * time.Hour},
+	"29": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 8 * 24 * time.Hour},
+	"30": {DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 10 * 24 * time.Hour},
+	"40": hardDecline,
+	"41": hardDecline,
+}
+
+// RetryAdviceFromMerchantAdviceCode translates a Mastercard Merchant Advice Code. Gateways return the code either bare
+// ("03") or followed by its description ("03: Do not try again"); only the leading two characters are used.
+// It returns false for empty and unknown codes.
+func RetryAdviceFromMerchantAdviceCode(code string) (*RetryAdvice, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return nil, false
+	}
+	advice, ok := merchantAdviceCodeToRetryAdvice[code[:2]]
+	if !ok {
+		return nil, false
+	}
+	advice.Source = RetryAdviceSourceMerchantAdviceCode
+	advice.Code = code[:2]
+	return &advice, true
+}
+
+// visaDeclineCategories maps Visa response codes to the decline categories of the Visa retry rules.
+// Category 1 codes will never be approved, category 2 codes may be approved later and category 3 codes
+// may be approved once the card data is corrected. Codes not listed belong to category 4, generic declines.
+var visaDeclineCategories = map[string]int{
+	"04": 1, "07": 1, "12": 1, "15": 1, "41": 1, "43": 1, "46": 1, "57": 1, "R0": 1, "R1": 1, "R3": 1,
+	"03": 2, "19": 2, "51": 2, "59": 2, "61": 2, "62": 2, "65": 2, "75": 2, "78": 2, "86": 2, "91": 2, "93": 2,
+	"96": 2, "N3": 2, "N4": 2, "Z5": 2,
+	"14": 3, "54": 3, "55": 3, "70": 3, "82": 3, "1A": 3, "N7": 3,
+}
+
+var visaDeclineCategoryToRetryAdvice = map[int]RetryAdvice{
+	1: hardDecline,
+	2: softDecline,
+	3: fixAndRetry,
+	4: softDecline,
+}
+
+// RetryAdviceFromVisaResponseCode translates a Visa network response code through its decline category. Gateways
+// return the code either bare ("51") or followed by its description ("51 : Insufficient funds"); only the leading
+// two characters are used. It returns false for empty and approval codes.
+func RetryAdviceFromVisaResponseCode(code string) (*RetryAdvice, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return nil, false
+	}
+	code = code[:2]
+	if code == "00" || code == "10" || code == "85" {
+		return nil, false
+	}
+	category, ok := visaDeclineCategories[code]
+	if !ok {
+		category = 4
+	}
+	advice := visaDeclineCategoryToRetryAdvice[category]
+	advice.Source = RetryAdviceSourceVisaCategory
+	advice.Code = code
+	return &advice, true
+}
+
+// NewRetryAdvice returns the most specific advice for a declined authorization: the Merchant Advice Code for
+// Mastercard, the decline category of the network response code for Visa, and the decline reason otherwise.
+// Merchant Advice Codes are only issued by Mastercard, so they are also used when the network is unknown.
+// merchantAdviceCode and networkResponseCode may be empty if the gateway does not return them.
+func NewRetryAdvice(reason DeclineReason, network CreditCardNetwork, merchantAdviceCode string, networkResponseCode string) *RetryAdvice {
+	if network == CreditCardNetworkMastercard || network == CreditCardNetworkUnknown {
+		if advice, ok := RetryAdviceFromMerchantAdviceCode(merchantAdviceCode); ok {
+			return advice
+		}
+	}
+	if network == CreditCardNetworkVisa {
+		if advice, ok := RetryAdviceFromVisaResponseCode(networkResponseCode); ok {
+			return advice
+		}
+	}
+	if reason == DeclineReasonNone {
+		reason = DeclineReasonUnknown
+	}
+	return RetryAdviceFromDeclineReason(reason)
+}
@@ -0,0 +1,96 @@
+package sleet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestRetryAdviceFromDeclineReasonCoversAllReasons(t *testing.T) {
+	for reason := DeclineReasonUnknown; reason <= DeclineReasonProcessingError; reason++ {
+		if _, ok := declineReasonToRetryAdvice[reason]; !ok {
+			t.Errorf("decline reason %s has no retry advice", reason)
+		}
+	}
+	if advice := RetryAdviceFromDeclineReason(DeclineReasonNone); advice != nil {
+		t.Errorf("Got %v, want nil", advice)
+	}
+}
+
+func TestNewRetryAdvice(t *testing.T) {
+	cases := []struct {
+		label               string
+		reason              DeclineReason
+		network             CreditCardNetwork
+		merchantAdviceCode  string
+		networkResponseCode string
+		want                *RetryAdvice
+	}{
+		{
+			"Mastercard update card", DeclineReasonDoNotHonor, CreditCardNetworkMastercard, "01: New account information available", "05",
+			&RetryAdvice{DeclineType: DeclineTypeHard, UpdateCard: true, Source: RetryAdviceSourceMerchantAdviceCode, Code: "01"},
+		},
+		{
+			"Mastercard do not try again", DeclineReasonInsufficientFunds, CreditCardNetworkMastercard, "03", "51",
+			&RetryAdvice{DeclineType: DeclineTypeHard, Source: RetryAdviceSourceMerchantAdviceCode, Code: "03"},
+		},
+		{
+			"Mastercard retry after 24 hours", DeclineReasonDoNotHonor, CreditCardNetworkMastercard, "25", "",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: 24 * time.Hour, Source: RetryAdviceSourceMerchantAdviceCode, Code: "25"},
+		},
+		{
+			"Unknown network with merchant advice code", DeclineReasonDoNotHonor, CreditCardNetworkUnknown, "24", "",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, RetryAfter: time.Hour, Source: RetryAdviceSourceMerchantAdviceCode, Code: "24"},
+		},
+		{
+			"Mastercard unknown merchant advice code", DeclineReasonLostOrStolenCard, CreditCardNetworkMastercard, "99", "",
+			&RetryAdvice{DeclineType: DeclineTypeHard, Source: RetryAdviceSourceDeclineReason, Code: "DeclineReasonLostOrStolenCard"},
+		},
+		{
+			"Visa category 1", DeclineReasonDoNotHonor, CreditCardNetworkVisa, "", "41",
+			&RetryAdvice{DeclineType: DeclineTypeHard, Source: RetryAdviceSourceVisaCategory, Code: "41"},
+		},
+		{
+			"Visa category 2", DeclineReasonInsufficientFunds, CreditCardNetworkVisa, "", "51 : Insufficient funds",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, Source: RetryAdviceSourceVisaCategory, Code: "51"},
+		},
+		{
+			"Visa category 3", DeclineReasonExpiredCard, CreditCardNetworkVisa, "", "54",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, UpdateCard: true, Source: RetryAdviceSourceVisaCategory, Code: "54"},
+		},
+		{
+			"Visa category 4", DeclineReasonDoNotHonor, CreditCardNetworkVisa, "", "05",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, Source: RetryAdviceSourceVisaCategory, Code: "05"},
+		},
+		{
+			"Visa ignores merchant advice code", DeclineReasonExpiredCard, CreditCardNetworkVisa, "03", "",
+			&RetryAdvice{DeclineType: DeclineTypeHard, UpdateCard: true, Source: RetryAdviceSourceDeclineReason, Code: "DeclineReasonExpiredCard"},
+		},
+		{
+			"Amex decline reason", DeclineReasonIssuerUnavailable, CreditCardNetworkAmex, "", "91",
+			&RetryAdvice{DeclineType: DeclineTypeSoft, Retryable: true, Source: RetryAdviceSourceDeclineReason, Code: "DeclineReasonIssuerUnavailable"},
+		},
+		{
+			"No codes", DeclineReasonNone, CreditCardNetworkDiscover, "", "",
+			&RetryAdvice{DeclineType: DeclineTypeUnknown, Source: RetryAdviceSourceDeclineReason, Code: "DeclineReasonUnknown"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got := NewRetryAdvice(c.reason, c.network, c.merchantAdviceCode, c.networkResponseCode)
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRetryAdviceFromVisaResponseCodeApproval(t *testing.T) {
+	for _, code := range []string{"", "0", "00", "10", "85"} {
+		if advice, ok := RetryAdviceFromVisaResponseCode(code); ok {
+			t.Errorf("Got %v for %q, want no advice", advice, code)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package sleet
+
+import (
+	"context"
+	"fmt"
+)
+
+// RiskRule decides whether an authorization approved by the PSP should be declined based on its AVS and CVV results.
+// Name is reported as the RiskDeclineReason of declined authorizations.
+type RiskRule struct {
+	Name    string
+	Decline func(request *AuthorizationRequest, response *AuthorizationResponse) bool
+}
+
+// RiskPolicy is an ordered list of rules. An authorization is declined by the first rule that matches.
+type RiskPolicy struct {
+	Rules []RiskRule
+}
+
+// Evaluate returns the first rule of the policy that declines the authorization, if any
+func (policy RiskPolicy) Evaluate(request *AuthorizationRequest, response *AuthorizationResponse) (*RiskRule, bool) {
+	for i := range policy.Rules {
+		if policy.Rules[i].Decline(request, response) {
+			return &policy.Rules[i], true
+		}
+	}
+	return nil, false
+}
+
+// DeclineOnCVVResults declines authorizations whose CVV result is one of results
+func DeclineOnCVVResults(name string, results ...CVVResponse) RiskRule {
+	return RiskRule{
+		Name: name,
+		Decline: func(_ *AuthorizationRequest, response *AuthorizationResponse) bool {
+			for _, result := range results {
+				if response.CvvResult == result {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// DeclineOnCVVNoMatch declines authorizations where the CVV was checked and did not match
+func DeclineOnCVVNoMatch() RiskRule {
+	return DeclineOnCVVResults("CVVNoMatch", CVVResponseNoMatch)
+}
+
+// DeclineOnAVSResults declines authorizations whose AVS result is one of results
+func DeclineOnAVSResults(name string, results ...AVSResponse) RiskRule {
+	return RiskRule{
+		Name: name,
+		Decline: func(_ *AuthorizationRequest, response *AuthorizationResponse) bool {
+			for _, result := range results {
+				if response.AvsResult == result {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// DeclineOnAVSNoMatch declines U.S. card authorizations where neither the postal code nor the street matched
+func DeclineOnAVSNoMatch() RiskRule {
+	return RiskRule{
+		Name: "AVSNoMatch",
+		Decline: func(_ *AuthorizationRequest, response *AuthorizationResponse) bool {
+			components := response.AVSComponents()
+			return !components.International && components.PostalCode == AVSMatchNo && components.Street == AVSMatchNo
+		},
+	}
+}
+
+// DeclineOnPostalCodeNoMatch declines authorizations where the postal code was checked and did not match
+func DeclineOnPostalCodeNoMatch() RiskRule {
+	return RiskRule{
+		Name: "PostalCodeNoMatch",
+		Decline: func(_ *AuthorizationRequest, response *AuthorizationResponse) bool {
+			return response.AVSComponents().PostalCode == AVSMatchNo
+		},
+	}
+}
+
+// RiskPolicyClient wraps a client and applies a RiskPolicy to every approved authorization. Authorizations declined
+// by the policy are voided and returned with Success set to false, ResultTypeRiskDeclined and the rule name in
+// RiskDeclineReason. If the void fails, ResultTypeRiskDeclinedVoidFailed is returned instead together with an error.
+// All other calls are passed through.
+type RiskPolicyClient struct {
+	client ClientWithContext
+	policy RiskPolicy
+}
+
+var _ ClientWithContext = &RiskPolicyClient{}
+
+// NewRiskPolicyClient returns a client applying policy to the authorizations made through client
+func NewRiskPolicyClient(client ClientWithContext, policy RiskPolicy) *RiskPolicyClient {
+	return &RiskPolicyClient{
+		client: client,
+		policy: policy,
+	}
+}
+
+// Authorize an authorization request and apply the risk policy to the result
+func (client *RiskPolicyClient) Authorize(request *AuthorizationRequest) (*AuthorizationResponse, error) {
+	return client.AuthorizeWithContext(context.TODO(), request)
+}
+
+// AuthorizeWithContext authorizes through the wrapped client and voids approved authorizations declined by the policy.
+// If the void fails the declined response is returned with ResultTypeRiskDeclinedVoidFailed and an error, as the
+// authorization is still open. Captured requests (AutoCapture or OfflineApprovalCode) cannot be voided and are returned
+// the same way without a void, to be refunded by the caller.
+func (client *RiskPolicyClient) AuthorizeWithContext(ctx context.Context, request *AuthorizationRequest) (*AuthorizationResponse, error) {
+	response, err := client.client.AuthorizeWithContext(ctx, request)
+	if err != nil || response == nil || !response.Success {
+		return response, err
+	}
+
+	rule, declined := client.policy.Evaluate(request, response)
+	if !declined {
+		return response, nil
+	}
+
+	declinedResponse := *response
+	declinedResponse.Success = false
+	declinedResponse.ResultType = ResultTypeRiskDeclined
+	declinedResponse.RiskDeclineReason = rule.Name
+	declinedResponse.Message = fmt.Sprintf("declined by risk policy: %s", rule.Name)
+
+	if request.AutoCapture || request.OfflineApprovalCode != "" {
+		declinedResponse.ResultType = ResultTypeRiskDeclinedVoidFailed
+		return &declinedResponse, fmt.Errorf("risk declined authorization %s is captured and cannot be voided", response.TransactionReference)
+	}
+
+	voidRequest := &VoidRequest{
+		TransactionReference:       response.TransactionReference,
+		ClientTransactionReference: request.ClientTransactionReference,
+	}
+	if request.MerchantOrderReference != "" {
+		voidRequest.MerchantOrderReference = &request.MerchantOrderReference
+	}
+	voidResponse, err := client.client.VoidWithContext(ctx, voidRequest)
+	if err != nil {
+		declinedResponse.ResultType = ResultTypeRiskDeclinedVoidFailed
+		return &declinedResponse, fmt.Errorf("void of risk declined authorization %s failed: %w", response.TransactionReference, err)
+	}
+	if voidResponse == nil {
+		declinedResponse.ResultType = ResultTypeRiskDeclinedVoidFailed
+		return &declinedResponse, fmt.Errorf("void of risk declined authorization %s returned no response", response.TransactionReference)
+	}
+	if !voidResponse.Success {
+		declinedResponse.ResultType = ResultTypeRiskDeclinedVoidFailed
+		return &declinedResponse, fmt.Errorf("void of risk declined authorization %s failed with error code %s",
+			response.TransactionReference, safeString(voidResponse.ErrorCode))
+	}
+	return &declinedResponse, nil
+}
+
+// Capture passes the request through to the wrapped client
+func (client *RiskPolicyClient) Capture(request *CaptureRequest) (*CaptureResponse, error) {
+	return client.CaptureWithContext(context.TODO(), request)
+}
+
+// CaptureWithContext passes the request through to the wrapped client
+func (client *RiskPolicyClient) CaptureWithContext(ctx context.Context, request *CaptureRequest) (*CaptureResponse, error) {
+	return client.client.CaptureWithContext(ctx, request)
+}
+
+// Void passes the request through to the wrapped client
+func (client *RiskPolicyClient) Void(request *VoidRequest) (*VoidResponse, error) {
+	return client.VoidWithContext(context.TODO(), request)
+}
+
+// VoidWithContext passes the request through to the wrapped client
+func (client *RiskPolicyClient) VoidWithContext(ctx context.Context, request *VoidRequest) (*VoidResponse, error) {
+	return client.client.VoidWithContext(ctx, request)
+}
+
+// Refund passes the request through to the wrapped client
+func (client *RiskPolicyClient) Refund(request *RefundRequest) (*RefundResponse, error) {
+	return client.RefundWithContext(context.TODO(), request)
+}
+
+// RefundWithContext passes the request through to the wrapped client
+func (client *RiskPolicyClient) RefundWithContext(ctx context.Context, request *RefundRequest) (*RefundResponse, error) {
+	return client.client.RefundWithContext(ctx, request)
+}
+
+func safeString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,202 @@
+package sleet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type fakeClient struct {
+	authResponse *AuthorizationResponse
+	voidResponse *VoidResponse
+	voidErr      error
+	voids        []*VoidRequest
+}
+
+func (c *fakeClient) Authorize(request *AuthorizationRequest) (*AuthorizationResponse, error) {
+	return c.AuthorizeWithContext(context.TODO(), request)
+}
+
+func (c *fakeClient) AuthorizeWithContext(_ context.Context, _ *AuthorizationRequest) (*AuthorizationResponse, error) {
+	return c.authResponse, nil
+}
+
+func (c *fakeClient) Capture(request *CaptureRequest) (*CaptureResponse, error) {
+	return c.CaptureWithContext(context.TODO(), request)
+}
+
+func (c *fakeClient) CaptureWithContext(_ context.Context, _ *CaptureRequest) (*CaptureResponse, error) {
+	return &CaptureResponse{Success: true}, nil
+}
+
+func (c *fakeClient) Void(request *VoidRequest) (*VoidResponse, error) {
+	return c.VoidWithContext(context.TODO(), request)
+}
+
+func (c *fakeClient) VoidWithContext(_ context.Context, request *VoidRequest) (*VoidResponse, error) {
+	c.voids = append(c.voids, request)
+	return c.voidResponse, c.voidErr
+}
+
+func (c *fakeClient) Refund(request *RefundRequest) (*RefundResponse, error) {
+	return c.RefundWithContext(context.TODO(), request)
+}
+
+func (c *fakeClient) RefundWithContext(_ context.Context, _ *RefundRequest) (*RefundResponse, error) {
+	return &RefundResponse{Success: true}, nil
+}
+
+func TestRiskPolicyRules(t *testing.T) {
+	cases := []struct {
+		label    string
+		rule     RiskRule
+		response AuthorizationResponse
+		declined bool
+	}{
+		{"CVV no match", DeclineOnCVVNoMatch(), AuthorizationResponse{CvvResult: CVVResponseNoMatch}, true},
+		{"CVV match", DeclineOnCVVNoMatch(), AuthorizationResponse{CvvResult: CVVResponseMatch}, false},
+		{"CVV results", DeclineOnCVVResults("CVVSuspicious", CVVResponseSuspicious, CVVResponseError), AuthorizationResponse{CvvResult: CVVResponseError}, true},
+		{"AVS no match", DeclineOnAVSNoMatch(), AuthorizationResponse{AvsResult: AVSResponseNoMatch}, true},
+		{"AVS zip match", DeclineOnAVSNoMatch(), AuthorizationResponse{AvsResult: AVSResponseZip5MatchAddressNoMatch}, false},
+		{"AVS international no match", DeclineOnAVSNoMatch(), AuthorizationResponse{AvsResult: AVSResponseNonUsZipNoMatchAddressNoMatch}, false},
+		{"AVS skipped", DeclineOnAVSNoMatch(), AuthorizationResponse{AvsResult: AVSResponseSkipped}, false},
+		{"Postal code no match", DeclineOnPostalCodeNoMatch(), AuthorizationResponse{AvsResult: AVSResponseZipNoMatchAddressMatch}, true},
+		{"Postal code no match address unverified", DeclineOnPostalCodeNoMatch(), AuthorizationResponse{AvsResult: AVSResponseZipNoMatchAddressUnverified}, true},
+		{"AVS results", DeclineOnAVSResults("AVSError", AVSResponseError), AuthorizationResponse{AvsResult: AVSResponseError}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if declined := c.rule.Decline(&AuthorizationRequest{}, &c.response); declined != c.declined {
+				t.Errorf("got %t, want %t", declined, c.declined)
+			}
+		})
+	}
+}
+
+func TestRiskPolicyClient(t *testing.T) {
+	policy := RiskPolicy{Rules: []RiskRule{DeclineOnCVVNoMatch(), DeclineOnAVSNoMatch()}}
+	clientReference := "client-reference"
+	request := &AuthorizationRequest{ClientTransactionReference: &clientReference, MerchantOrderReference: "order"}
+
+	t.Run("Approved", func(t *testing.T) {
+		approved := &AuthorizationResponse{Success: true, TransactionReference: "txn", CvvResult: CVVResponseMatch}
+		fake := &fakeClient{authResponse: approved}
+		got, err := NewRiskPolicyClient(fake, policy).Authorize(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != approved || len(fake.voids) != 0 {
+			t.Errorf("expected approved response without void, got %v and %d voids", got, len(fake.voids))
+		}
+	})
+
+	t.Run("Declined by PSP", func(t *testing.T) {
+		fake := &fakeClient{authResponse: &AuthorizationResponse{Success: false, CvvResult: CVVResponseNoMatch}}
+		if _, err := NewRiskPolicyClient(fake, policy).Authorize(request); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(fake.voids) != 0 {
+			t.Error("declined authorization should not be voided")
+		}
+	})
+
+	t.Run("Declined by policy", func(t *testing.T) {
+		fake := &fakeClient{
+			authResponse: &AuthorizationResponse{Success: true, TransactionReference: "txn", AvsResult: AVSResponseNoMatch},
+			voidResponse: &VoidResponse{Success: true},
+		}
+		got, err := NewRiskPolicyClient(fake, policy).Authorize(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := &AuthorizationResponse{
+			Success:              false,
+			TransactionReference: "txn",
+			AvsResult:            AVSResponseNoMatch,
+			ResultType:           ResultTypeRiskDeclined,
+			Message:              "declined by risk policy: AVSNoMatch",
+			RiskDeclineReason:    "AVSNoMatch",
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Error(diff)
+		}
+		order := "order"
+		wantVoids := []*VoidRequest{{TransactionReference: "txn", ClientTransactionReference: &clientReference, MerchantOrderReference: &order}}
+		if diff := deep.Equal(fake.voids, wantVoids); diff != nil {
+			t.Error(diff)
+		}
+		if !fake.authResponse.Success {
+			t.Error("wrapped client response was modified")
+		}
+	})
+
+	t.Run("Void fails", func(t *testing.T) {
+		voidErr := errors.New("connection reset")
+		fake := &fakeClient{
+			authResponse: &AuthorizationResponse{Success: true, TransactionReference: "txn", CvvResult: CVVResponseNoMatch},
+			voidErr:      voidErr,
+		}
+		got, err := NewRiskPolicyClient(fake, policy).Authorize(request)
+		if !errors.Is(err, voidErr) {
+			t.Errorf("expected void error, got %v", err)
+		}
+		if got == nil || got.ResultType != ResultTypeRiskDeclinedVoidFailed || got.RiskDeclineReason != "CVVNoMatch" {
+			t.Errorf("expected risk declined response with a failed void, got %v", got)
+		}
+	})
+
+	t.Run("Void declined", func(t *testing.T) {
+		errorCode := "E1"
+		fake := &fakeClient{
+			authResponse: &AuthorizationResponse{Success: true, TransactionReference: "txn", CvvResult: CVVResponseNoMatch},
+			voidResponse: &VoidResponse{Success: false, ErrorCode: &errorCode},
+		}
+		got, err := NewRiskPolicyClient(fake, policy).Authorize(request)
+		if err == nil {
+			t.Error("expected error")
+		}
+		if got == nil || got.ResultType != ResultTypeRiskDeclinedVoidFailed {
+			t.Errorf("expected risk declined response with a failed void, got %v", got)
+		}
+	})
+
+	t.Run("Captured", func(t *testing.T) {
+		for label, captured := range map[string]*AuthorizationRequest{
+			"Auto capture":     {AutoCapture: true},
+			"Offline approval": {OfflineApprovalCode: "123456"},
+		} {
+			t.Run(label, func(t *testing.T) {
+				fake := &fakeClient{
+					authResponse: &AuthorizationResponse{Success: true, TransactionReference: "txn", CvvResult: CVVResponseNoMatch},
+					voidResponse: &VoidResponse{Success: true},
+				}
+				got, err := NewRiskPolicyClient(fake, policy).Authorize(captured)
+				if err == nil {
+					t.Error("expected error")
+				}
+				if got == nil || got.ResultType != ResultTypeRiskDeclinedVoidFailed || got.RiskDeclineReason != "CVVNoMatch" {
+					t.Errorf("expected risk declined response with a failed void, got %v", got)
+				}
+				if len(fake.voids) != 0 {
+					t.Error("captured authorization should not be voided")
+				}
+			})
+		}
+	})
+
+	t.Run("Void without response", func(t *testing.T) {
+		fake := &fakeClient{
+			authResponse: &AuthorizationResponse{Success: true, TransactionReference: "txn", CvvResult: CVVResponseNoMatch},
+		}
+		got, err := NewRiskPolicyClient(fake, policy).Authorize(request)
+		if err == nil {
+			t.Error("expected error")
+		}
+		if got == nil || got.ResultType != ResultTypeRiskDeclinedVoidFailed {
+			t.Errorf("expected risk declined response with a failed void, got %v", got)
+		}
+	})
+}
@@ -146,6 +146,18 @@ func BaseLevel3DataMultipleItem() *sleet.Level3Data {
 	}
 }
 
+func BaseLevel2Data() *sleet.Level2Data {
+	return &sleet.Level2Data{
+		PurchaseOrderNumber: "PO-12345",
+		TaxAmount: sleet.Amount{
+			Amount:   825,
+			Currency: "USD",
+		},
+		MerchantTaxID:      "12-3456789",
+		ShipFromPostalCode: "94105",
+	}
+}
+
 func BaseCaptureRequest() *sleet.CaptureRequest {
 	clientRef := "222222"
 
@@ -70,7 +70,7 @@ type LineItem struct {
 	TotalAmount        Amount
 	ItemTaxAmount      Amount
 	ItemDiscountAmount Amount
-	UnitOfMeasure      string
+	UnitOfMeasure      string // ISO code, name or synonym, see common.LookupUnitOfMeasurementCode. Unknown units are rejected
 	CommodityCode      string
 }
 
@@ -87,6 +87,16 @@ type Level3Data struct {
 	LineItems              []LineItem
 }
 
+// Level2Data contains the purchase order and tax information used for Level 2 (commercial and purchasing card)
+// processing. Gateways send the fields they support and ignore the others.
+type Level2Data struct {
+	PurchaseOrderNumber string // customer purchase order number
+	TaxAmount           Amount
+	TaxExempt           bool
+	MerchantTaxID       string
+	ShipFromPostalCode  string
+}
+
 // AmountSplit represents a split of the total transaction amount that should be routed to a specific account
 // This is mostly used under Payfac models where we have different sub-accounts for merchants under a single platform account
 // The platform commission is taken out of the split amount and routed to the platform account
@@ -112,6 +122,17 @@ const (
 	// CyberSourceTokenizeOption will cause tokens to be requested for each token type listed in the option.
 	// Value type: []TokenType
 	CyberSourceTokenizeOption string = "CyberSourceTokenize"
+
+	// Level3ValidationOption derives and validates Level 3 totals before the authorization is sent. Gateways that
+	// send Level 3 data return a *Level3ValidationError instead of sending a request that does not reconcile.
+	// Value type: Level3ValidationOptions
+	Level3ValidationOption string = "Level3Validation"
+
+	// IdempotencyKeyOption sends the given key as the idempotency key of the authorization on gateways that support
+	// one (Adyen, Stripe). A request sent again with the same key returns the original response instead of
+	// authorizing twice, so use a new key when retrying a declined authorization with changed details.
+	// Value type: string
+	IdempotencyKeyOption string = "IdempotencyKey"
 )
 
 // AuthorizationRequest specifies needed information for request to authorize by PsPs
@@ -119,14 +140,17 @@ const (
 // Note: Options is a generic key-value pair that can be used to provide additional information to PsP
 type AuthorizationRequest struct {
 	Amount                        Amount
+	AutoCapture                   bool // Capture the authorization in the same request (a sale), Orbital only
 	BillingAddress                *Address
 	Channel                       string  // for PSPs that track the sales channel
 	ClientTransactionReference    *string // Custom transaction reference metadata that will be associated with this request
 	CreditCard                    *CreditCard
 	Cryptogram                    string // for Network Tokenization methods
 	ECI                           string // E-Commerce Indicator (can be used for Network Tokenization as well)
+	Level2Data                    *Level2Data
 	Level3Data                    *Level3Data
 	MerchantOrderReference        string                   // Similar to ClientTransactionReference but specifically if we want to store the shopping cart order id
+	OfflineApprovalCode           string                   // Approval code of an authorization obtained outside the PSP (e.g. a voice authorization) to force and capture, Orbital only
 	PreviousExternalTransactionID *string                  // If we are in a recurring situation, then we can use the PreviousExternalTransactionID as part of the auth request
 	ProcessingInitiator           *ProcessingInitiatorType // For Card on File transactions we want to store the various different types (initial cof, initial recurring, etc)
 	ShippingAddress               *Address
@@ -141,6 +165,13 @@ const (
 	AuthCodeMetadata     string = "authCode"
 	ApprovalCodeMetadata string = "approvalCode"
 	ResponseCodeMetadata string = "responseCode"
+	CardHashMetadata     string = "cardHash"
+	CardBINMetadata      string = "cardBin"
+	// HostMetadata is the host that served the request, for gateways that fail over between hosts
+	HostMetadata string = "host"
+	// TraceNumberMetadata is the trace number the request was sent with, for gateways that deduplicate retried
+	// requests by trace number
+	TraceNumberMetadata string = "traceNumber"
 )
 
 // AuthorizationResponse is a generic response returned back to client after data massaging from PsP Response.
@@ -150,7 +181,8 @@ const (
 // loss of granularity minimized. The latter should be preferred when
 // treating Sleet as a black box.
 type AuthorizationResponse struct {
-	// Success is true if Auth went through successfully
+	// Success is true if Auth went through successfully. Pending authorizations (ResultTypePending) are not
+	// approved yet, Success is false and they carry no DeclineReason or RetryAdvice.
 	Success               bool
 	TransactionReference  string
 	ExternalTransactionID string
@@ -174,6 +206,27 @@ type AuthorizationResponse struct {
 	StatusCode int
 	// Header is the HTTP header from the PSP response, filtered by the list of headers in the ResponseHeaderOption.
 	Header http.Header
+	// RiskDeclineReason is the name of the RiskRule that declined an otherwise approved authorization.
+	RiskDeclineReason string
+	// DeclineReason is the gateway independent translation of ErrorCode for declined authorizations.
+	DeclineReason DeclineReason
+	// RetryAdvice classifies declined authorizations as soft or hard declines. It is nil for approved authorizations.
+	RetryAdvice *RetryAdvice
+	// NetworkResponseCode is the raw authorization response code of the issuer, typically an ISO 8583 code.
+	NetworkResponseCode string
+	// MerchantAdviceCode is the Mastercard Merchant Advice Code returned with declined authorizations.
+	MerchantAdviceCode string
+	// NetworkTransactionID is the card network identifier of the authorization (Visa TransactionID,
+	// Mastercard Trace ID), required as the previous transaction reference of credential on file transactions.
+	NetworkTransactionID string
+	// PendingReason explains why the authorization is pending when ResultType is ResultTypePending. Pending
+	// authorizations may hold funds and can be voided, their status can be followed with PollTransactionDetails.
+	PendingReason PendingReason
+}
+
+// AVSComponents returns the per-component view of AvsResult, e.g. whether the postal code matched
+func (r *AuthorizationResponse) AVSComponents() AVSComponents {
+	return r.AvsResult.Components()
 }
 
 // CaptureRequest specifies the authorized transaction to capture and also an amount for partial capture use cases
@@ -184,6 +237,7 @@ type CaptureRequest struct {
 	MerchantOrderReference     *string                // Custom merchant order reference that will be associated with this request
 	Options                    map[string]interface{} // For additional options that need to be passed in
 	AmountSplits               []AmountSplit
+	Level2Data                 *Level2Data
 }
 
 // CaptureResponse will have Success be true if transaction is captured and also a reference to be used for subsequent operations
@@ -193,8 +247,9 @@ type CaptureResponse struct {
 	ErrorCode            *string
 }
 
-// VoidRequest cancels an authorized transaction
+// VoidRequest cancels an authorized transaction, or part of it when an Amount is given
 type VoidRequest struct {
+	Amount                     *Amount // Amount to reverse for a partial reversal, Orbital only. The whole authorization is reversed when nil
 	TransactionReference       string
 	ClientTransactionReference *string // Custom transaction reference metadata that will be associated with this request
 	MerchantOrderReference     *string // Custom merchant order reference that will be associated with this request
@@ -207,9 +262,11 @@ type VoidResponse struct {
 	ErrorCode            *string
 }
 
-// RefundRequest for refunding a captured transaction with generic Options and amount to be refunded
+// RefundRequest for refunding a captured transaction with generic Options and amount to be refunded.
+// Orbital refunds the CreditCard when TransactionReference is empty, the other PSPs reject a CreditCard.
 type RefundRequest struct {
 	Amount                     *Amount
+	CreditCard                 *CreditCard
 	TransactionReference       string
 	ClientTransactionReference *string // Custom transaction reference metadata that will be associated with this request
 	MerchantOrderReference     *string // Custom merchant order reference that will be associated with this request
@@ -230,10 +287,17 @@ type TransactionDetailsRequest struct {
 	TransactionReference string
 }
 
-// TransactionDetailsResponse indicating the transaction details. Currently, only the last 4 digits of credit card is returned.
+// TransactionDetailsResponse indicating the transaction details. Currently, only the last 4 digits of credit card and the
+// status of the transaction are returned.
 type TransactionDetailsResponse struct {
 	ResultCode string
 	CardNumber string
+	// Status is the raw transaction status of the gateway.
+	Status string
+	// ResultType is ResultTypeSuccess once the transaction is approved, ResultTypePending while it is pending and
+	// ResultTypePaymentError once it is declined.
+	ResultType    ResultType
+	PendingReason PendingReason
 }
 
 // GetHTTPResponseHeader returns the http response headers specified in the given options.
@@ -291,11 +355,17 @@ type ThreeDS struct {
 type ResultType string
 
 const (
-	ResultTypeSuccess      ResultType = "Approved"
-	ResultTypeUnknownError ResultType = "Unknown"
-	ResultTypePaymentError ResultType = "PaymentError" // payment or credit card related error
-	ResultTypeAPIError     ResultType = "APIError"     // error related to the PSPs API (validation error, authentication, idempotency, etc)
-	ResultTypeServerError  ResultType = "ServerError"  // network, connection, timeout etc. errors
+	ResultTypeSuccess       ResultType = "Approved"
+	ResultTypeUnknownError  ResultType = "Unknown"
+	ResultTypePaymentError  ResultType = "PaymentError"  // payment or credit card related error
+	ResultTypeAPIError      ResultType = "APIError"      // error related to the PSPs API (validation error, authentication, idempotency, etc)
+	ResultTypeServerError   ResultType = "ServerError"   // network, connection, timeout etc. errors
+	ResultTypeRiskDeclined  ResultType = "RiskDeclined"  // approved by the PSP but declined and voided by a RiskPolicy
+	ResultTypePending       ResultType = "Pending"       // neither approved nor declined yet, see PendingReason
+	ResultTypeIndeterminate ResultType = "Indeterminate" // outcome unknown after a network error, see UnknownOutcomeClient
+	// approved by the PSP and declined by a RiskPolicy, but the void failed or the authorization was captured so it is
+	// still open
+	ResultTypeRiskDeclinedVoidFailed ResultType = "RiskDeclinedVoidFailed"
 )
 
 // TokenType defines the type of token, used either as input to complete a transaction, or as output to be saved for
@@ -0,0 +1,205 @@
+package sleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// TransactionLookupRequest identifies a transaction whose outcome is unknown. Authorizations are searched by their
+// ClientTransactionReference or MerchantOrderReference; for captures TransactionReference is set to the reference of
+// the captured authorization.
+type TransactionLookupRequest struct {
+	TransactionReference       string
+	ClientTransactionReference *string
+	MerchantOrderReference     string
+}
+
+// TransactionLookupResponse is the state of a transaction found by a lookup. ResultType is ResultTypeSuccess for
+// approved authorizations, ResultTypePending for authorizations that are not resolved yet and ResultTypePaymentError
+// for declined authorizations. Captured is true once the authorization has been captured.
+type TransactionLookupResponse struct {
+	Found                bool
+	TransactionReference string
+	ResultType           ResultType
+	PendingReason        PendingReason
+	Captured             bool
+	// Status is the raw transaction status of the gateway.
+	Status string
+}
+
+// ErrTransactionLookupIncomplete is returned by a TransactionLookupClient that could not search every transaction the
+// references may match, so a transaction that was not found may still exist.
+var ErrTransactionLookupIncomplete = errors.New("transaction lookup incomplete")
+
+// TransactionLookupClient is implemented by the gateways that can search for a transaction by its references.
+// Found is only false when the search covered every candidate transaction, otherwise ErrTransactionLookupIncomplete
+// is returned.
+type TransactionLookupClient interface {
+	LookupTransactionWithContext(ctx context.Context, request *TransactionLookupRequest) (*TransactionLookupResponse, error)
+}
+
+// IndeterminateOutcomeError is returned by UnknownOutcomeClient when a request failed with a network error and the
+// transaction could not be found afterwards, or was found in an unknown or unresolved state. Err is the network error
+// and LookupErr the error of the lookup, if any. The transaction may or may not have been processed; retrying it may
+// process it twice unless the gateway deduplicates it, see IdempotencyKeyOption.
+type IndeterminateOutcomeError struct {
+	Err       error
+	LookupErr error
+}
+
+func (e *IndeterminateOutcomeError) Error() string {
+	if e.LookupErr != nil {
+		return fmt.Sprintf("outcome of the transaction is unknown: %v (lookup failed: %v)", e.Err, e.LookupErr)
+	}
+	return fmt.Sprintf("outcome of the transaction is unknown: %v (transaction not found or not resolved)", e.Err)
+}
+
+// Unwrap returns the network error
+func (e *IndeterminateOutcomeError) Unwrap() error {
+	return e.Err
+}
+
+// IsNetworkError returns true if err leaves the outcome of a request unknown: the connection failed, timed out or
+// was closed after the request may have been sent, or the context was done while waiting for the response.
+func IsNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// defaultLookupTimeout bounds lookups made after the context of the original request is done
+const defaultLookupTimeout = 30 * time.Second
+
+// UnknownOutcomeClient wraps a client and resolves authorizations and captures that fail with a network error by
+// looking the transaction up. Found transactions are returned as if the gateway had responded, otherwise the response
+// has ResultTypeIndeterminate and the error is an IndeterminateOutcomeError. All other calls are passed through.
+//
+// Requests are never sent again: without a TransactionLookupClient every network error leaves the outcome
+// indeterminate.
+type UnknownOutcomeClient struct {
+	client ClientWithContext
+	lookup TransactionLookupClient
+}
+
+var _ ClientWithContext = &UnknownOutcomeClient{}
+
+// NewUnknownOutcomeClient returns a client resolving the unknown outcomes of client through lookup, which is usually
+// the same gateway client. lookup is nil for gateways without a lookup, whose unknown outcomes stay indeterminate.
+func NewUnknownOutcomeClient(client ClientWithContext, lookup TransactionLookupClient) *UnknownOutcomeClient {
+	return &UnknownOutcomeClient{
+		client: client,
+		lookup: lookup,
+	}
+}
+
+// Authorize an authorization request and resolve its outcome on network errors
+func (client *UnknownOutcomeClient) Authorize(request *AuthorizationRequest) (*AuthorizationResponse, error) {
+	return client.AuthorizeWithContext(context.TODO(), request)
+}
+
+// AuthorizeWithContext authorizes through the wrapped client. On network errors the authorization is searched by its
+// ClientTransactionReference and MerchantOrderReference.
+func (client *UnknownOutcomeClient) AuthorizeWithContext(ctx context.Context, request *AuthorizationRequest) (*AuthorizationResponse, error) {
+	response, err := client.client.AuthorizeWithContext(ctx, request)
+	if err == nil || !IsNetworkError(err) {
+		return response, err
+	}
+
+	if client.lookup == nil {
+		return &AuthorizationResponse{Success: false, ResultType: ResultTypeIndeterminate}, &IndeterminateOutcomeError{Err: err}
+	}
+	lookup, lookupErr := client.lookupWithContext(ctx, &TransactionLookupRequest{
+		ClientTransactionReference: request.ClientTransactionReference,
+		MerchantOrderReference:     request.MerchantOrderReference,
+	})
+	if lookupErr != nil || !lookup.Found || lookup.ResultType == ResultTypeUnknownError {
+		return &AuthorizationResponse{Success: false, ResultType: ResultTypeIndeterminate},
+			&IndeterminateOutcomeError{Err: err, LookupErr: lookupErr}
+	}
+	return &AuthorizationResponse{
+		Success:              lookup.ResultType == ResultTypeSuccess,
+		TransactionReference: lookup.TransactionReference,
+		Response:             lookup.Status,
+		ResultType:           lookup.ResultType,
+		PendingReason:        lookup.PendingReason,
+	}, nil
+}
+
+// Capture a capture request and resolve its outcome on network errors
+func (client *UnknownOutcomeClient) Capture(request *CaptureRequest) (*CaptureResponse, error) {
+	return client.CaptureWithContext(context.TODO(), request)
+}
+
+// CaptureWithContext captures through the wrapped client. On network errors the captured authorization is looked up
+// to check whether the capture went through. An authorization that is not captured yet leaves the outcome
+// indeterminate, as captures may be processed asynchronously and the capture may still be in flight.
+func (client *UnknownOutcomeClient) CaptureWithContext(ctx context.Context, request *CaptureRequest) (*CaptureResponse, error) {
+	response, err := client.client.CaptureWithContext(ctx, request)
+	if err == nil || !IsNetworkError(err) {
+		return response, err
+	}
+
+	lookupRequest := &TransactionLookupRequest{
+		TransactionReference:       request.TransactionReference,
+		ClientTransactionReference: request.ClientTransactionReference,
+	}
+	if request.MerchantOrderReference != nil {
+		lookupRequest.MerchantOrderReference = *request.MerchantOrderReference
+	}
+	if client.lookup == nil {
+		return &CaptureResponse{Success: false}, &IndeterminateOutcomeError{Err: err}
+	}
+	lookup, lookupErr := client.lookupWithContext(ctx, lookupRequest)
+	if lookupErr != nil || !lookup.Found {
+		return &CaptureResponse{Success: false}, &IndeterminateOutcomeError{Err: err, LookupErr: lookupErr}
+	}
+	if !lookup.Captured {
+		status := lookup.Status
+		return &CaptureResponse{Success: false, TransactionReference: lookup.TransactionReference, ErrorCode: &status},
+			&IndeterminateOutcomeError{Err: err}
+	}
+	return &CaptureResponse{Success: true, TransactionReference: lookup.TransactionReference}, nil
+}
+
+// Void passes the request through to the wrapped client
+func (client *UnknownOutcomeClient) Void(request *VoidRequest) (*VoidResponse, error) {
+	return client.VoidWithContext(context.TODO(), request)
+}
+
+// VoidWithContext passes the request through to the wrapped client
+func (client *UnknownOutcomeClient) VoidWithContext(ctx context.Context, request *VoidRequest) (*VoidResponse, error) {
+	return client.client.VoidWithContext(ctx, request)
+}
+
+// Refund passes the request through to the wrapped client
+func (client *UnknownOutcomeClient) Refund(request *RefundRequest) (*RefundResponse, error) {
+	return client.RefundWithContext(context.TODO(), request)
+}
+
+// RefundWithContext passes the request through to the wrapped client
+func (client *UnknownOutcomeClient) RefundWithContext(ctx context.Context, request *RefundRequest) (*RefundResponse, error) {
+	return client.client.RefundWithContext(ctx, request)
+}
+
+// lookupWithContext looks the transaction up, with a fresh context if the context of the original request is done
+func (client *UnknownOutcomeClient) lookupWithContext(ctx context.Context, request *TransactionLookupRequest) (*TransactionLookupResponse, error) {
+	ctx, cancel := resolutionContext(ctx)
+	defer cancel()
+	return client.lookup.LookupTransactionWithContext(ctx, request)
+}
+
+// resolutionContext returns ctx, or a fresh context bounded by defaultLookupTimeout if ctx is already done. It is
+// only used for read-only lookups, never to send a payment after the caller gave up.
+func resolutionContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() != nil {
+		return context.WithTimeout(context.Background(), defaultLookupTimeout)
+	}
+	return ctx, func() {}
+}
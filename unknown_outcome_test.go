@@ -0,0 +1,209 @@
+package sleet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+type failingClient struct {
+	fakeClient
+	authErrs   []error
+	captureErr error
+	authCalls  int
+}
+
+func (c *failingClient) AuthorizeWithContext(_ context.Context, _ *AuthorizationRequest) (*AuthorizationResponse, error) {
+	err := c.authErrs[c.authCalls]
+	c.authCalls++
+	if err != nil {
+		return nil, err
+	}
+	return c.authResponse, nil
+}
+
+func (c *failingClient) CaptureWithContext(_ context.Context, _ *CaptureRequest) (*CaptureResponse, error) {
+	return nil, c.captureErr
+}
+
+type fakeLookupClient struct {
+	response *TransactionLookupResponse
+	err      error
+	requests []*TransactionLookupRequest
+}
+
+func (c *fakeLookupClient) LookupTransactionWithContext(_ context.Context, request *TransactionLookupRequest) (*TransactionLookupResponse, error) {
+	c.requests = append(c.requests, request)
+	return c.response, c.err
+}
+
+func TestIsNetworkError(t *testing.T) {
+	cases := []struct {
+		label string
+		err   error
+		want  bool
+	}{
+		{"timeout", &net.OpError{Op: "read", Err: timeoutError{}}, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"wrapped cancel", errors.Unwrap(&IndeterminateOutcomeError{Err: context.Canceled}), true},
+		{"other", errors.New("invalid request"), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if got := IsNetworkError(c.err); got != c.want {
+				t.Errorf("got %t, want %t", got, c.want)
+			}
+		})
+	}
+}
+
+func TestUnknownOutcomeClientAuthorize(t *testing.T) {
+	clientReference := "client-reference"
+	request := &AuthorizationRequest{ClientTransactionReference: &clientReference, MerchantOrderReference: "order"}
+	networkErr := &net.OpError{Op: "read", Err: timeoutError{}}
+
+	t.Run("Other errors are passed through", func(t *testing.T) {
+		apiErr := errors.New("invalid request")
+		lookup := &fakeLookupClient{}
+		_, err := NewUnknownOutcomeClient(&failingClient{authErrs: []error{apiErr}}, lookup).Authorize(request)
+		if err != apiErr || len(lookup.requests) != 0 {
+			t.Errorf("expected error without lookup, got %v after %d lookups", err, len(lookup.requests))
+		}
+	})
+
+	t.Run("Found approved", func(t *testing.T) {
+		lookup := &fakeLookupClient{response: &TransactionLookupResponse{
+			Found: true, TransactionReference: "txn", ResultType: ResultTypeSuccess, Status: "authorizedPendingCapture",
+		}}
+		got, err := NewUnknownOutcomeClient(&failingClient{authErrs: []error{networkErr}}, lookup).Authorize(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := &AuthorizationResponse{
+			Success:              true,
+			TransactionReference: "txn",
+			Response:             "authorizedPendingCapture",
+			ResultType:           ResultTypeSuccess,
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Error(diff)
+		}
+		wantRequests := []*TransactionLookupRequest{{ClientTransactionReference: &clientReference, MerchantOrderReference: "order"}}
+		if diff := deep.Equal(lookup.requests, wantRequests); diff != nil {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("Found declined", func(t *testing.T) {
+		lookup := &fakeLookupClient{response: &TransactionLookupResponse{Found: true, TransactionReference: "txn", ResultType: ResultTypePaymentError}}
+		got, err := NewUnknownOutcomeClient(&failingClient{authErrs: []error{networkErr}}, lookup).Authorize(request)
+		if err != nil || got.Success || got.ResultType != ResultTypePaymentError {
+			t.Errorf("expected declined response, got %v, %v", got, err)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		lookup := &fakeLookupClient{response: &TransactionLookupResponse{Found: false}}
+		got, err := NewUnknownOutcomeClient(&failingClient{authErrs: []error{networkErr}}, lookup).Authorize(request)
+		var indeterminate *IndeterminateOutcomeError
+		if !errors.As(err, &indeterminate) || !errors.Is(err, networkErr) || indeterminate.LookupErr != nil {
+			t.Errorf("expected indeterminate outcome error, got %v", err)
+		}
+		if got == nil || got.ResultType != ResultTypeIndeterminate {
+			t.Errorf("expected indeterminate response, got %v", got)
+		}
+	})
+
+	t.Run("Lookup fails", func(t *testing.T) {
+		lookupErr := errors.New("lookup failed")
+		lookup := &fakeLookupClient{err: lookupErr}
+		_, err := NewUnknownOutcomeClient(&failingClient{authErrs: []error{networkErr}}, lookup).Authorize(request)
+		var indeterminate *IndeterminateOutcomeError
+		if !errors.As(err, &indeterminate) || indeterminate.LookupErr != lookupErr {
+			t.Errorf("expected indeterminate outcome error with lookup error, got %v", err)
+		}
+	})
+
+	t.Run("Indeterminate without lookup", func(t *testing.T) {
+		approved := &AuthorizationResponse{Success: true, TransactionReference: "txn"}
+		client := &failingClient{fakeClient: fakeClient{authResponse: approved}, authErrs: []error{networkErr, nil}}
+		got, err := NewUnknownOutcomeClient(client, nil).Authorize(request)
+		var indeterminate *IndeterminateOutcomeError
+		if !errors.As(err, &indeterminate) || got.ResultType != ResultTypeIndeterminate {
+			t.Errorf("expected indeterminate outcome, got %v, %v", got, err)
+		}
+		if client.authCalls != 1 {
+			t.Errorf("expected the authorization to be sent once, sent %d times", client.authCalls)
+		}
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		lookup := &fakeLookupClient{response: &TransactionLookupResponse{Found: true, ResultType: ResultTypeSuccess}}
+		client := NewUnknownOutcomeClient(&failingClient{authErrs: []error{context.Canceled}}, lookup)
+		if got, err := client.AuthorizeWithContext(ctx, request); err != nil || !got.Success {
+			t.Errorf("expected lookup with a fresh context, got %v, %v", got, err)
+		}
+	})
+}
+
+func TestUnknownOutcomeClientCapture(t *testing.T) {
+	order := "order"
+	request := &CaptureRequest{TransactionReference: "txn", MerchantOrderReference: &order}
+	networkErr := &net.OpError{Op: "read", Err: timeoutError{}}
+
+	cases := []struct {
+		label   string
+		lookup  *fakeLookupClient
+		want    *CaptureResponse
+		wantErr bool
+	}{
+		{
+			"Captured",
+			&fakeLookupClient{response: &TransactionLookupResponse{Found: true, TransactionReference: "txn", Captured: true}},
+			&CaptureResponse{Success: true, TransactionReference: "txn"},
+			false,
+		},
+		{
+			"Not captured",
+			&fakeLookupClient{response: &TransactionLookupResponse{Found: true, TransactionReference: "txn", Status: "authorizedPendingCapture"}},
+			&CaptureResponse{Success: false, TransactionReference: "txn", ErrorCode: &[]string{"authorizedPendingCapture"}[0]},
+			true,
+		},
+		{
+			"Not found",
+			&fakeLookupClient{response: &TransactionLookupResponse{Found: false}},
+			&CaptureResponse{Success: false},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			got, err := NewUnknownOutcomeClient(&failingClient{captureErr: networkErr}, c.lookup).Capture(request)
+			if (err != nil) != c.wantErr {
+				t.Errorf("got error %v, want error %t", err, c.wantErr)
+			}
+			if diff := deep.Equal(got, c.want); diff != nil {
+				t.Error(diff)
+			}
+			wantRequests := []*TransactionLookupRequest{{TransactionReference: "txn", MerchantOrderReference: "order"}}
+			if diff := deep.Equal(c.lookup.requests, wantRequests); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
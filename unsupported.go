@@ -0,0 +1,40 @@
+package sleet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedField is returned by the gateways given a request field they do not support, rather than sending the
+// request without it.
+var ErrUnsupportedField = errors.New("request field not supported by the gateway")
+
+// CheckAuthorizationFields returns ErrUnsupportedField for the AuthorizationRequest fields only supported by Orbital,
+// for the other gateways to reject them.
+func CheckAuthorizationFields(request *AuthorizationRequest) error {
+	if request.AutoCapture {
+		return fmt.Errorf("%w: AutoCapture", ErrUnsupportedField)
+	}
+	if request.OfflineApprovalCode != "" {
+		return fmt.Errorf("%w: OfflineApprovalCode", ErrUnsupportedField)
+	}
+	return nil
+}
+
+// CheckVoidFields returns ErrUnsupportedField for the VoidRequest fields only supported by Orbital, for the other
+// gateways to reject them.
+func CheckVoidFields(request *VoidRequest) error {
+	if request.Amount != nil {
+		return fmt.Errorf("%w: Amount", ErrUnsupportedField)
+	}
+	return nil
+}
+
+// CheckRefundFields returns ErrUnsupportedField for the RefundRequest fields only supported by Orbital, for the other
+// gateways to reject them.
+func CheckRefundFields(request *RefundRequest) error {
+	if request.CreditCard != nil {
+		return fmt.Errorf("%w: CreditCard", ErrUnsupportedField)
+	}
+	return nil
+}
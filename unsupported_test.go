@@ -0,0 +1,30 @@
+package sleet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckFields(t *testing.T) {
+	cases := []struct {
+		label   string
+		check   func() error
+		wantErr error
+	}{
+		{"Authorization", func() error { return CheckAuthorizationFields(&AuthorizationRequest{}) }, nil},
+		{"Auto capture", func() error { return CheckAuthorizationFields(&AuthorizationRequest{AutoCapture: true}) }, ErrUnsupportedField},
+		{"Offline approval", func() error { return CheckAuthorizationFields(&AuthorizationRequest{OfflineApprovalCode: "123456"}) }, ErrUnsupportedField},
+		{"Void", func() error { return CheckVoidFields(&VoidRequest{}) }, nil},
+		{"Partial void", func() error { return CheckVoidFields(&VoidRequest{Amount: &Amount{Amount: 100, Currency: "USD"}}) }, ErrUnsupportedField},
+		{"Refund", func() error { return CheckRefundFields(&RefundRequest{}) }, nil},
+		{"Refund to card", func() error { return CheckRefundFields(&RefundRequest{CreditCard: &CreditCard{}}) }, ErrUnsupportedField},
+	}
+
+	for _, c := range cases {
+		t.Run(c.label, func(t *testing.T) {
+			if err := c.check(); !errors.Is(err, c.wantErr) {
+				t.Errorf("Got %v, want %v", err, c.wantErr)
+			}
+		})
+	}
+}